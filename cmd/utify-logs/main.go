@@ -0,0 +1,282 @@
+// Command utify-logs queries, follows and re-renders the JSON log files
+// written by utify.
+//
+// Usage:
+//
+//	utify-logs [flags] [file ...]
+//
+// Entries are read from the files, or from standard input without any, and
+// printed the way utify prints messages on the console. Only logs written
+// with the JSON encoder can be read; other lines are skipped and counted on
+// standard error:
+//
+//	utify-logs -type error,critical -since 1h ~/.local/state/app/app.log
+//	utify-logs -f -grep timeout app.log
+//	utify-logs -count app.log.1 app.log
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jsas4coding/utify/pkg/colors"
+	"github.com/jsas4coding/utify/pkg/formatter"
+	"github.com/jsas4coding/utify/pkg/layout"
+	"github.com/jsas4coding/utify/pkg/logger"
+	"github.com/jsas4coding/utify/pkg/messages"
+	"github.com/jsas4coding/utify/pkg/options"
+	"github.com/jsas4coding/utify/pkg/terminal"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	os.Exit(run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// config holds the parsed command line.
+type config struct {
+	filter  logger.Filter
+	follow  bool
+	count   bool
+	json    bool
+	layout  *layout.Layout
+	profile colors.Profile
+	icons   bool
+	files   []string
+}
+
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	cfg, err := parseArgs(args, stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "utify-logs: %v\n", err)
+		return 2
+	}
+
+	out := newOutput(cfg, stdout)
+	if cfg.follow {
+		err = logger.Follow(ctx, cfg.files[0], logger.FollowOptions{FromStart: true}, func(e logger.LogEntry) error {
+			if cfg.filter.Match(e) {
+				out.write(e)
+			}
+			return nil
+		})
+		if errors.Is(err, context.Canceled) {
+			err = nil
+		}
+	} else {
+		err = readAll(cfg, stdin, out)
+	}
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "utify-logs: %v\n", err)
+		return 1
+	}
+	if cfg.count {
+		out.printCounts()
+	}
+	if out.skipped > 0 {
+		_, _ = fmt.Fprintf(stderr, "utify-logs: skipped %d lines that are not JSON log entries\n", out.skipped)
+		if out.read == 0 {
+			return 1
+		}
+	}
+	return 0
+}
+
+func parseArgs(args []string, stderr io.Writer) (*config, error) {
+	fs := flag.NewFlagSet("utify-logs", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(stderr, "Usage: utify-logs [flags] [file ...]")
+		_, _ = fmt.Fprintln(stderr, "Reads logs written with the JSON encoder; other lines are skipped.")
+		fs.PrintDefaults()
+	}
+
+	var (
+		cfg                          config
+		types, since, until, pattern string
+		layoutSpec, color            string
+	)
+	fs.StringVar(&types, "type", "", "comma-separated message `types` to show, e.g. error,critical")
+	fs.StringVar(&since, "since", "", "show entries from this RFC 3339 `time`, or this long ago, e.g. 2h")
+	fs.StringVar(&until, "until", "", "show entries up to this RFC 3339 `time`, or this long ago")
+	fs.StringVar(&cfg.filter.Binary, "binary", "", "show entries written by this `binary`")
+	fs.StringVar(&cfg.filter.Text, "grep", "", "show entries whose message or fields contain `text`")
+	fs.StringVar(&pattern, "regex", "", "show entries whose message or fields match `pattern`")
+	fs.BoolVar(&cfg.follow, "f", false, "follow a single file as it grows, like tail -f")
+	fs.BoolVar(&cfg.count, "count", false, "print the number of entries per type instead of the entries")
+	fs.BoolVar(&cfg.json, "json", false, "print matching entries as JSON lines")
+	fs.StringVar(&layoutSpec, "layout", layout.Timestamped, "layout preset or template")
+	fs.StringVar(&color, "color", "auto", "colorize output: auto, always or never")
+	fs.BoolVar(&cfg.icons, "icons", true, "show message icons")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	cfg.files = fs.Args()
+
+	now := time.Now()
+	var err error
+	if types != "" {
+		for _, t := range strings.Split(types, ",") {
+			cfg.filter.Types = append(cfg.filter.Types, messages.Type(strings.TrimSpace(t)))
+		}
+	}
+	if cfg.filter.Since, err = parseTime(since, now); err != nil {
+		return nil, fmt.Errorf("-since: %w", err)
+	}
+	if cfg.filter.Until, err = parseTime(until, now); err != nil {
+		return nil, fmt.Errorf("-until: %w", err)
+	}
+	if pattern != "" {
+		if cfg.filter.Pattern, err = regexp.Compile(pattern); err != nil {
+			return nil, fmt.Errorf("-regex: %w", err)
+		}
+	}
+	if cfg.layout, err = layout.Resolve(layoutSpec); err != nil {
+		return nil, fmt.Errorf("-layout: %w", err)
+	}
+	switch color {
+	case "auto":
+		cfg.profile = colors.ProfileAuto
+	case "always":
+		cfg.profile = max(terminal.EnvProfile(), colors.ProfileANSI)
+	case "never":
+		cfg.profile = colors.ProfileNone
+	default:
+		return nil, fmt.Errorf("-color: unknown value %q", color)
+	}
+	if cfg.follow && len(cfg.files) != 1 {
+		return nil, errors.New("-f needs exactly one file")
+	}
+	if cfg.follow && cfg.count {
+		return nil, errors.New("-f and -count cannot be combined")
+	}
+	return &cfg, nil
+}
+
+// parseTime parses an RFC 3339 time or a duration before now. An empty
+// value is the zero time.
+func parseTime(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// readAll writes the matching entries of every input.
+func readAll(cfg *config, stdin io.Reader, out *output) error {
+	if len(cfg.files) == 0 {
+		return readEntries(cfg, stdin, out)
+	}
+	for _, name := range cfg.files {
+		file, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		err = readEntries(cfg, file, out)
+		_ = file.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func readEntries(cfg *config, r io.Reader, out *output) error {
+	reader := logger.NewReader(r)
+	for reader.Next() {
+		out.read++
+		if e := reader.Entry(); cfg.filter.Match(e) {
+			out.write(e)
+		}
+	}
+	out.skipped += reader.Skipped()
+	return reader.Err()
+}
+
+// output renders, prints or counts entries. read and skipped count the
+// entries and the other lines of the inputs.
+type output struct {
+	cfg       *config
+	w         io.Writer
+	formatter *formatter.Formatter
+	opts      *options.Options
+	counts    map[messages.Type]int
+	read      int
+	skipped   int
+}
+
+func newOutput(cfg *config, w io.Writer) *output {
+	trace := options.Levels{Console: messages.SeverityTrace, Log: messages.SeverityTrace}
+	opts := options.Default()
+	if cfg.icons {
+		opts = opts.WithIcon()
+	}
+	return &output{
+		cfg: cfg,
+		w:   w,
+		formatter: &formatter.Formatter{
+			Output:      w,
+			ErrorOutput: w,
+			Profile:     cfg.profile,
+			Levels:      &trace,
+			Layouts:     layout.Layouts{messages.Default: cfg.layout},
+			Logger:      logger.NewWriter(nil),
+		},
+		opts:   opts,
+		counts: map[messages.Type]int{},
+	}
+}
+
+func (o *output) write(e logger.LogEntry) {
+	switch {
+	case o.cfg.count:
+		o.counts[e.Type]++
+	case o.cfg.json:
+		data, err := e.MarshalJSON()
+		if err == nil {
+			_, _ = fmt.Fprintf(o.w, "%s\n", data)
+		}
+	default:
+		o.formatter.Replay(e, o.opts)
+	}
+}
+
+// printCounts prints the counts per type, most frequent first.
+func (o *output) printCounts() {
+	types := make([]messages.Type, 0, len(o.counts))
+	total := 0
+	for t, n := range o.counts {
+		types = append(types, t)
+		total += n
+	}
+	sort.Slice(types, func(i, j int) bool {
+		if o.counts[types[i]] != o.counts[types[j]] {
+			return o.counts[types[i]] > o.counts[types[j]]
+		}
+		return types[i] < types[j]
+	})
+
+	tw := tabwriter.NewWriter(o.w, 0, 0, 2, ' ', 0)
+	for _, t := range types {
+		_, _ = fmt.Fprintf(tw, "%s\t%d\n", t, o.counts[t])
+	}
+	_, _ = fmt.Fprintf(tw, "total\t%d\n", total)
+	_ = tw.Flush()
+}
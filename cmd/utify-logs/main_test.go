@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sample = `{"timestamp":"2024-05-01T10:00:00Z","level":"INFO","message":"Deployed","type":"info","binary":"api","service":"api"}
+{"timestamp":"2024-05-01T11:00:00Z","level":"ERROR","message":"Upload failed","type":"error","binary":"api","err":"timeout"}
+not an entry
+{"timestamp":"2024-05-01T12:00:00Z","level":"ERROR","message":"Sync failed","type":"error","binary":"worker"}
+{"timestamp":"2024-05-01T13:00:00Z","level":"WARNING","message":"Slow query","type":"warning","binary":"worker","took":"2s"}
+`
+
+func runLogs(t *testing.T, stdin string, args ...string) (string, string, int) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), append([]string{"-color", "never", "-icons=false"}, args...),
+		strings.NewReader(stdin), &stdout, &stderr)
+	return stdout.String(), stderr.String(), code
+}
+
+func TestRender(t *testing.T) {
+	out, _, code := runLogs(t, sample, "-layout", "{message} {fields}", "-type", "error,warning", "-since",
+		"2024-05-01T11:30:00Z")
+	if code != 0 || out != "Sync failed\nSlow query took=2s\n" {
+		t.Errorf("Unexpected output %q (exit %d)", out, code)
+	}
+}
+
+func TestFilters(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"-binary", "worker"}, "Sync failed\nSlow query\n"},
+		{[]string{"-grep", "timeout"}, "Upload failed\n"},
+		{[]string{"-regex", `^\w+ failed$`}, "Sync failed\n"},
+		{[]string{"-until", "2024-05-01T10:30:00Z"}, "Deployed\n"},
+	}
+	for _, tt := range tests {
+		out, _, _ := runLogs(t, sample, append([]string{"-layout", "{message}"}, tt.args...)...)
+		if out != tt.expected {
+			t.Errorf("%v: expected %q, got %q", tt.args, tt.expected, out)
+		}
+	}
+}
+
+func TestCountAndFiles(t *testing.T) {
+	dir := t.TempDir()
+	first, second := filepath.Join(dir, "app.log.1"), filepath.Join(dir, "app.log")
+	lines := strings.SplitAfter(sample, "\n")
+	if err := os.WriteFile(first, []byte(strings.Join(lines[:2], "")), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(second, []byte(strings.Join(lines[2:], "")), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, _, code := runLogs(t, "", "-count", first, second)
+	expected := "error    2\ninfo     1\nwarning  1\ntotal    4\n"
+	if code != 0 || out != expected {
+		t.Errorf("Expected counts %q, got %q (exit %d)", expected, out, code)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	out, _, _ := runLogs(t, sample, "-json", "-grep", "Slow")
+	expected := `{"timestamp":"2024-05-01T13:00:00Z","level":"WARNING","message":"Slow query","type":"warning",` +
+		`"binary":"worker","took":"2s"}` + "\n"
+	if out != expected {
+		t.Errorf("Expected %q, got %q", expected, out)
+	}
+}
+
+func TestInvalidArguments(t *testing.T) {
+	for _, args := range [][]string{
+		{"-since", "yesterday"},
+		{"-regex", "("},
+		{"-color", "sometimes"},
+		{"-f"},
+		{"-f", "-count", "a.log"},
+	} {
+		if _, stderr, code := runLogs(t, "", args...); code != 2 || !strings.HasPrefix(stderr, "utify-logs: ") {
+			t.Errorf("%v: expected exit 2 with an error, got %d and %q", args, code, stderr)
+		}
+	}
+	if _, _, code := runLogs(t, "", filepath.Join(t.TempDir(), "missing.log")); code != 1 {
+		t.Errorf("Expected exit 1 for a missing file, got %d", code)
+	}
+}
+
+func TestSkippedLines(t *testing.T) {
+	_, stderr, code := runLogs(t, sample, "-count")
+	if code != 0 || stderr != "utify-logs: skipped 1 lines that are not JSON log entries\n" {
+		t.Errorf("Expected the skipped line to be reported, got %q (exit %d)", stderr, code)
+	}
+	logfmt := "time=2024-05-01T10:00:00Z level=INFO msg=Deployed\n"
+	if out, stderr, code := runLogs(t, logfmt); code != 1 || out != "" || !strings.Contains(stderr, "skipped 1 ") {
+		t.Errorf("Expected exit 1 when no line is an entry, got %d, %q and %q", code, out, stderr)
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	if got, err := parseTime("90m", now); err != nil || !got.Equal(now.Add(-90*time.Minute)) {
+		t.Errorf("Expected 90 minutes ago, got %v, %v", got, err)
+	}
+	if got, err := parseTime("", now); err != nil || !got.IsZero() {
+		t.Errorf("Expected the zero time, got %v, %v", got, err)
+	}
+}
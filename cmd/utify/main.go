@@ -0,0 +1,382 @@
+// Command utify prints styled utify messages from shell scripts, so they
+// look like the output of Go programs using the library.
+//
+// Usage:
+//
+//	utify <type> [flags] <message> [args ...]
+//	utify log <type> [flags] <message> [args ...]
+//	utify types
+//
+// The message is a printf format when args are given and it has verbs;
+// otherwise the words are joined with spaces:
+//
+//	utify success "Deployed %s in %.1fs" api 3.24 --bold --icons=nerd
+//	utify info Hello world
+//	utify error "Build failed" --exit || exit
+//	utify log warning "Disk almost full" --field free=2GiB
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/jsas4coding/utify"
+	"github.com/jsas4coding/utify/pkg/colors"
+	"github.com/jsas4coding/utify/pkg/formatter"
+	"github.com/jsas4coding/utify/pkg/icons"
+	"github.com/jsas4coding/utify/pkg/layout"
+	"github.com/jsas4coding/utify/pkg/logger"
+	"github.com/jsas4coding/utify/pkg/messages"
+	"github.com/jsas4coding/utify/pkg/theme"
+)
+
+// Exit codes.
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return exitUsage
+	}
+	switch args[0] {
+	case "-h", "-help", "--help", "help":
+		usage(stdout)
+		return exitOK
+	case "types":
+		for _, t := range messages.Types() {
+			_, _ = fmt.Fprintln(stdout, t)
+		}
+		return exitOK
+	case "log":
+		return runLog(args[1:], stderr)
+	default:
+		return runPrint(args, stdout, stderr)
+	}
+}
+
+func usage(w io.Writer) {
+	_, _ = fmt.Fprint(w, `Usage:
+  utify <type> [flags] <message> [args ...]      print a message
+  utify log <type> [flags] <message> [args ...]  write a message to the log only
+  utify types                                    list the message types
+
+The message is a printf format when args are given and it has verbs;
+otherwise the words are joined with spaces. Run "utify <type> -h" or
+"utify log <type> -h" for the flags.
+`)
+}
+
+// fieldsFlag collects repeated --field key=value flags.
+type fieldsFlag []utify.Field
+
+func (f *fieldsFlag) String() string {
+	return ""
+}
+
+func (f *fieldsFlag) Set(value string) error {
+	key, val, found := strings.Cut(value, "=")
+	if !found || key == "" {
+		return fmt.Errorf("expected key=value, got %q", value)
+	}
+	*f = append(*f, utify.F(key, val))
+	return nil
+}
+
+// iconsFlag is --icons, alone or with a value: auto, nerd, regular or none.
+type iconsFlag struct {
+	set  bool
+	mode string
+}
+
+func (f *iconsFlag) String() string {
+	return f.mode
+}
+
+func (f *iconsFlag) Set(value string) error {
+	switch value {
+	case "true":
+		value = "auto"
+	case "false":
+		value = "none"
+	}
+	switch value {
+	case "auto", "nerd", "regular", "none":
+		f.set, f.mode = true, value
+		return nil
+	}
+	return fmt.Errorf("expected auto, nerd, regular or none, got %q", value)
+}
+
+func (f *iconsFlag) IsBoolFlag() bool {
+	return true
+}
+
+// parseType returns the message type named name.
+func parseType(name string) (messages.Type, error) {
+	t := messages.Type(name)
+	if !messages.IsRegistered(t) {
+		return "", fmt.Errorf("unknown message type %q; run \"utify types\" for the list", name)
+	}
+	return t, nil
+}
+
+// parseInterspersed parses fs allowing flags after positional arguments, as
+// in `utify success "Deployed" --bold`. Arguments after "--" are positional.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		rest := fs.Args()
+		if consumed := len(args) - len(rest); consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, rest...), nil
+		}
+		if len(rest) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+// message renders the message from the positional arguments, treating the
+// first one as a printf format when more follow and it has verbs, and
+// joining them with spaces otherwise.
+func message(positional []string) (string, error) {
+	if len(positional) == 0 {
+		return "", errors.New("missing message")
+	}
+	if len(positional) == 1 {
+		return positional[0], nil
+	}
+	if len(formatVerbs(positional[0])) == 0 {
+		return strings.Join(positional, " "), nil
+	}
+	return fmt.Sprintf(positional[0], convertArgs(positional[0], positional[1:])...), nil
+}
+
+// convertArgs converts the arguments of format to the types its verbs
+// expect, like the printf shell builtin: integers for %d, %x, %o, %b and
+// %c, floats for %e, %f and %g, and strings otherwise.
+func convertArgs(format string, args []string) []any {
+	verbs := formatVerbs(format)
+	converted := make([]any, len(args))
+	for i, arg := range args {
+		converted[i] = arg
+		if i >= len(verbs) {
+			continue
+		}
+		switch verbs[i] {
+		case 'd', 'x', 'X', 'o', 'O', 'b', 'c':
+			if n, err := strconv.ParseInt(arg, 0, 64); err == nil {
+				converted[i] = n
+			}
+		case 'e', 'E', 'f', 'F', 'g', 'G':
+			if n, err := strconv.ParseFloat(arg, 64); err == nil {
+				converted[i] = n
+			}
+		}
+	}
+	return converted
+}
+
+// formatVerbs returns the verbs of format in order, skipping %%.
+func formatVerbs(format string) []rune {
+	var verbs []rune
+	runes := []rune(format)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '%' {
+			continue
+		}
+		i++
+		for i < len(runes) && strings.ContainsRune("+-# 0123456789.*[]", runes[i]) {
+			i++
+		}
+		if i < len(runes) && runes[i] != '%' {
+			verbs = append(verbs, runes[i])
+		}
+	}
+	return verbs
+}
+
+func runPrint(args []string, stdout, stderr io.Writer) int {
+	msgType, err := parseType(args[0])
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "utify: %v\n", err)
+		return exitUsage
+	}
+
+	fs := flag.NewFlagSet("utify "+args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var (
+		opts                      = utify.OptionsDefault()
+		fields                    fieldsFlag
+		iconMode                  iconsFlag
+		forceColor, toStderr      bool
+		exit                      bool
+		scope, layoutSpec, themeN string
+	)
+	fs.BoolVar(&opts.Bold, "bold", false, "bold text")
+	fs.BoolVar(&opts.Italic, "italic", false, "italic text")
+	fs.BoolVar(&opts.NoColor, "no-color", false, "disable colors")
+	fs.BoolVar(&forceColor, "color", false, "force colors even when output is not a terminal")
+	fs.BoolVar(&opts.NoStyle, "no-style", false, "disable bold, italic and theme attributes")
+	fs.BoolVar(&opts.NoIcon, "no-icon", false, "hide the icon")
+	fs.Var(&iconMode, "icons", "show icons: auto, nerd, regular or none")
+	fs.BoolVar(&exit, "exit", false, "exit with status 1 after an error or critical message")
+	fs.BoolVar(&toStderr, "stderr", false, "print to stderr instead of the routed output")
+	fs.StringVar(&scope, "scope", "", "scope shown by layouts with a scope token")
+	fs.StringVar(&layoutSpec, "layout", "", "layout preset or template")
+	fs.StringVar(&themeN, "theme", "", "built-in theme: "+strings.Join(theme.Names(), ", "))
+	fs.Var(&fields, "field", "add a key=value field, repeatable")
+	positional, err := parseInterspersed(fs, args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+	if err != nil {
+		return exitUsage
+	}
+	text, err := message(positional)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "utify: %v\n", err)
+		return exitUsage
+	}
+
+	// The type was asked for by name, so it is shown whatever the threshold
+	levels := utify.GetLevels()
+	levels.Console = min(levels.Console, messages.SeverityOf(msgType))
+	printerOpts := []utify.PrinterOption{
+		utify.WithOutput(stdout),
+		utify.WithErrorOutput(stderr),
+		utify.WithLogger(logger.NewWriter(nil)),
+		utify.WithScope(scope),
+		utify.WithLevels(levels),
+	}
+	if iconMode.set {
+		switch iconMode.mode {
+		case "nerd":
+			printerOpts = append(printerOpts, utify.WithIconSet(icons.NewSet(icons.NerdFontIcons)))
+		case "regular":
+			printerOpts = append(printerOpts, utify.WithIconSet(icons.NewSet(icons.RegularIcons)))
+		}
+		if iconMode.mode == "none" {
+			opts.WithoutIcon()
+		} else if !opts.NoIcon {
+			opts.WithIcon()
+		}
+	}
+	if forceColor && !opts.NoColor {
+		opts.WithColor()
+	}
+	if layoutSpec != "" {
+		l, err := layout.Resolve(layoutSpec)
+		if err != nil {
+			_, _ = fmt.Fprintf(stderr, "utify: -layout: %v\n", err)
+			return exitUsage
+		}
+		printerOpts = append(printerOpts, utify.WithLayout(l))
+	}
+	if themeN != "" {
+		t, err := theme.Builtin(themeN)
+		if err != nil {
+			_, _ = fmt.Fprintf(stderr, "utify: -theme: %v\n", err)
+			return exitUsage
+		}
+		printerOpts = append(printerOpts, utify.WithTheme(t))
+	}
+	if opts.NoColor {
+		printerOpts = append(printerOpts, utify.WithColorProfile(colors.ProfileNone))
+	}
+
+	// The log subcommand writes to the log; printing never does
+	route := utify.GetRouting().Lookup(msgType) &^ (utify.RouteLog | utify.RouteCallback)
+	if toStderr {
+		route = utify.RouteStderr
+	}
+	opts.WithRoute(route)
+
+	_, _ = utify.NewPrinter(printerOpts...).Echo(msgType, text, opts, fields...)
+	if exit && messages.IsErrorType(msgType) {
+		return exitError
+	}
+	return exitOK
+}
+
+func runLog(args []string, stderr io.Writer) int {
+	if len(args) == 0 {
+		_, _ = fmt.Fprintln(stderr, "utify: missing message type")
+		return exitUsage
+	}
+	msgType, err := parseType(args[0])
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "utify: %v\n", err)
+		return exitUsage
+	}
+
+	fs := flag.NewFlagSet("utify log "+args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var (
+		fields         fieldsFlag
+		target, format string
+		binary         string
+	)
+	fs.Var(&fields, "field", "add a key=value field, repeatable")
+	fs.StringVar(&target, "target", "", "log file, instead of "+logger.EnvLogFile+" or the default location")
+	fs.StringVar(&format, "format", "", "log encoding: "+strings.Join(logger.EncoderNames(), ", "))
+	fs.StringVar(&binary, "binary", "", "binary name recorded in the entry, e.g. the script name")
+	positional, err := parseInterspersed(fs, args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+	if err != nil {
+		return exitUsage
+	}
+	text, err := message(positional)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "utify: %v\n", err)
+		return exitUsage
+	}
+
+	l := logger.Default()
+	if target != "" {
+		if l, err = logger.New(target); err != nil {
+			_, _ = fmt.Fprintf(stderr, "utify: %v\n", err)
+			return exitError
+		}
+	}
+	defer l.Close()
+	if format != "" {
+		enc, err := logger.EncoderByName(format)
+		if err != nil {
+			_, _ = fmt.Fprintf(stderr, "utify: -format: %v\n", err)
+			return exitUsage
+		}
+		l.SetEncoder(enc)
+	}
+
+	if !formatter.GetLevels().WritesToLog(msgType) {
+		return exitOK
+	}
+	l.WriteEntry(logger.LogEntry{Type: msgType, Message: text, Fields: fields, Binary: binary})
+	if err := l.Err(); err != nil {
+		_, _ = fmt.Fprintf(stderr, "utify: %v\n", err)
+		if !l.IsEnabled() {
+			return exitError
+		}
+	}
+	return exitOK
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jsas4coding/utify/pkg/logger"
+)
+
+func runUtify(t *testing.T, args ...string) (string, string, int) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := run(args, &stdout, &stderr)
+	return stdout.String(), stderr.String(), code
+}
+
+func TestPrint(t *testing.T) {
+	out, _, code := runUtify(t, "success", "Deployed %s in %.1fs", "api", "3.24", "--no-color", "--layout", "{message}")
+	if code != 0 || out != "Deployed api in 3.2s\n" {
+		t.Errorf("Unexpected output %q (exit %d)", out, code)
+	}
+}
+
+func TestWordsWithoutVerbs(t *testing.T) {
+	out, _, code := runUtify(t, "info", "Hello", "world", "--no-color", "--layout", "{message}")
+	if code != 0 || out != "Hello world\n" {
+		t.Errorf("Expected the words joined, got %q (exit %d)", out, code)
+	}
+}
+
+func TestDebug(t *testing.T) {
+	out, _, code := runUtify(t, "debug", "Cache miss", "--no-color", "--layout", "{message}")
+	if code != 0 || out != "Cache miss\n" {
+		t.Errorf("Expected the debug message to be printed, got %q (exit %d)", out, code)
+	}
+}
+
+func TestFlagsAfterDoubleDash(t *testing.T) {
+	out, _, _ := runUtify(t, "info", "--no-color", "--layout", "{message}", "--", "--bold")
+	if out != "--bold\n" {
+		t.Errorf("Expected the message after -- to be printed, got %q", out)
+	}
+}
+
+func TestFields(t *testing.T) {
+	out, _, _ := runUtify(t, "info", "Deployed", "--field", "env=prod", "--no-color", "--layout", "{message} {fields}")
+	if out != "Deployed env=prod\n" {
+		t.Errorf("Unexpected output %q", out)
+	}
+	if _, _, code := runUtify(t, "info", "Deployed", "--field", "env"); code != exitUsage {
+		t.Errorf("Expected exit %d for a field without a value, got %d", exitUsage, code)
+	}
+}
+
+func TestStderr(t *testing.T) {
+	out, errOut, _ := runUtify(t, "info", "Deployed", "--stderr", "--no-color", "--layout", "{message}")
+	if out != "" || errOut != "Deployed\n" {
+		t.Errorf("Expected the message on stderr, got stdout %q and stderr %q", out, errOut)
+	}
+}
+
+func TestExit(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected int
+	}{
+		{[]string{"error", "Failed"}, exitOK},
+		{[]string{"error", "Failed", "--exit"}, exitError},
+		{[]string{"critical", "Failed", "--exit"}, exitError},
+		{[]string{"warning", "Careful", "--exit"}, exitOK},
+		{[]string{"bogus", "Failed"}, exitUsage},
+		{[]string{"error"}, exitUsage},
+		{[]string{"error", "Failed", "--icons=emoji"}, exitUsage},
+		{nil, exitUsage},
+	}
+	for _, tt := range tests {
+		if _, _, code := runUtify(t, tt.args...); code != tt.expected {
+			t.Errorf("%v: expected exit %d, got %d", tt.args, tt.expected, code)
+		}
+	}
+}
+
+func TestTypes(t *testing.T) {
+	out, _, code := runUtify(t, "types")
+	if code != 0 || !strings.Contains(out, "success\n") || !strings.Contains(out, "critical\n") {
+		t.Errorf("Unexpected types output %q (exit %d)", out, code)
+	}
+}
+
+func TestConvertArgs(t *testing.T) {
+	args := convertArgs("%s %d%% %5.2f %x %v", []string{"a", "42", "1.5", "0x1f", "7", "extra"})
+	expected := []any{"a", int64(42), 1.5, int64(31), "7", "extra"}
+	for i := range expected {
+		if args[i] != expected[i] {
+			t.Errorf("Argument %d: expected %#v, got %#v", i, expected[i], args[i])
+		}
+	}
+}
+
+func TestLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	out, errOut, code := runUtify(t, "log", "warning", "Disk %d%% full", "93", "--target", path,
+		"--field", "free=2GiB", "--binary", "backup.sh")
+	if code != 0 || out != "" || errOut != "" {
+		t.Fatalf("Unexpected result: stdout %q, stderr %q, exit %d", out, errOut, code)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := logger.ParseEntry(bytes.TrimSpace(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Type != "warning" || e.Message != "Disk 93% full" || e.Binary != "backup.sh" ||
+		len(e.Fields) != 1 || e.Fields[0].Value != "2GiB" {
+		t.Errorf("Unexpected entry %+v", e)
+	}
+}
+
+func TestLogToInvalidTarget(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, errOut, code := runUtify(t, "log", "error", "Failed", "--target", filepath.Join(file, "app.log"))
+	if code != exitError || !strings.HasPrefix(errOut, "utify: ") {
+		t.Errorf("Expected exit %d with an error, got %d and %q", exitError, code, errOut)
+	}
+}
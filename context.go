@@ -0,0 +1,333 @@
+package utify
+
+import (
+	"context"
+
+	"github.com/jsas4coding/utify/pkg/ctxfield"
+	"github.com/jsas4coding/utify/pkg/formatter"
+)
+
+// ContextExtractor returns the fields a context contributes to messages
+// printed with it.
+type ContextExtractor = ctxfield.Extractor
+
+// ContextMessenger is the context-aware message method set implemented by
+// Printer. Fields extracted from the context, such as request and trace
+// IDs, are logged with each message.
+type ContextMessenger interface {
+	EchoCtx(ctx context.Context, msgType MessageType, text string, opts *Options, fields ...Field) (string, error)
+	SuccessCtx(ctx context.Context, text string, opts *Options, fields ...Field)
+	ErrorCtx(ctx context.Context, text string, opts *Options, fields ...Field)
+	WarningCtx(ctx context.Context, text string, opts *Options, fields ...Field)
+	InfoCtx(ctx context.Context, text string, opts *Options, fields ...Field)
+	DebugCtx(ctx context.Context, text string, opts *Options, fields ...Field)
+	CriticalCtx(ctx context.Context, text string, opts *Options, fields ...Field)
+	DeleteCtx(ctx context.Context, text string, opts *Options, fields ...Field)
+	UpdateCtx(ctx context.Context, text string, opts *Options, fields ...Field)
+	InstallCtx(ctx context.Context, text string, opts *Options, fields ...Field)
+	UpgradeCtx(ctx context.Context, text string, opts *Options, fields ...Field)
+	EditCtx(ctx context.Context, text string, opts *Options, fields ...Field)
+	NewCtx(ctx context.Context, text string, opts *Options, fields ...Field)
+	DownloadCtx(ctx context.Context, text string, opts *Options, fields ...Field)
+	UploadCtx(ctx context.Context, text string, opts *Options, fields ...Field)
+	SyncCtx(ctx context.Context, text string, opts *Options, fields ...Field)
+	SearchCtx(ctx context.Context, text string, opts *Options, fields ...Field)
+}
+
+var (
+	_ ContextMessenger = (*Printer)(nil)
+	_ ContextMessenger = NopPrinter{}
+)
+
+// RegisterContextExtractor adds an extractor of context fields under name,
+// replacing one with the same name. The built-in extractors read the
+// values set by WithFields, WithRequestID and WithTrace.
+func RegisterContextExtractor(name string, e ContextExtractor) {
+	ctxfield.Register(name, e)
+}
+
+// UnregisterContextExtractor removes the extractor registered under name
+// and reports whether it existed.
+func UnregisterContextExtractor(name string) bool {
+	return ctxfield.Unregister(name)
+}
+
+// WithFields returns a context carrying fields that are logged with every
+// message printed with it.
+func WithFields(ctx context.Context, fields ...Field) context.Context {
+	return ctxfield.With(ctx, fields...)
+}
+
+// WithRequestID returns a context carrying a request ID, logged as
+// request_id.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return ctxfield.WithRequestID(ctx, id)
+}
+
+// WithTrace returns a context carrying a trace and span ID, logged as
+// trace_id and span_id.
+func WithTrace(ctx context.Context, traceID, spanID string) context.Context {
+	return ctxfield.WithTrace(ctx, traceID, spanID)
+}
+
+// SetContextFieldsOnConsole sets whether context fields are shown on the
+// console as well as logged. Printers created with WithContextFields keep
+// their own setting.
+func SetContextFieldsOnConsole(show bool) {
+	formatter.SetContextOnConsole(show)
+}
+
+type printerKey struct{}
+
+// WithPrinter returns a context carrying p, so code further down the call
+// chain can print through it with FromContext or the Ctx functions.
+func WithPrinter(ctx context.Context, p *Printer) context.Context {
+	return context.WithValue(ctx, printerKey{}, p)
+}
+
+// FromContext returns the Printer stored in ctx by WithPrinter, or the
+// default printer.
+func FromContext(ctx context.Context) *Printer {
+	if ctx != nil {
+		if p, ok := ctx.Value(printerKey{}).(*Printer); ok && p != nil {
+			return p
+		}
+	}
+	return std
+}
+
+// EchoCtx formats and prints a message of any type with the fields of ctx.
+func (p *Printer) EchoCtx(ctx context.Context, msgType MessageType, text string, opts *Options, fields ...Field) (string, error) {
+	return p.formatter.EchoContext(ctx, msgType, text, opts, fields...)
+}
+
+// SuccessCtx prints a success message with the fields of ctx.
+func (p *Printer) SuccessCtx(ctx context.Context, text string, opts *Options, fields ...Field) {
+	_, _ = p.EchoCtx(ctx, MessageSuccess, text, opts, fields...)
+}
+
+// ErrorCtx prints an error message with the fields of ctx.
+func (p *Printer) ErrorCtx(ctx context.Context, text string, opts *Options, fields ...Field) {
+	_, _ = p.EchoCtx(ctx, MessageError, text, opts, fields...)
+}
+
+// WarningCtx prints a warning message with the fields of ctx.
+func (p *Printer) WarningCtx(ctx context.Context, text string, opts *Options, fields ...Field) {
+	_, _ = p.EchoCtx(ctx, MessageWarning, text, opts, fields...)
+}
+
+// InfoCtx prints an info message with the fields of ctx.
+func (p *Printer) InfoCtx(ctx context.Context, text string, opts *Options, fields ...Field) {
+	_, _ = p.EchoCtx(ctx, MessageInfo, text, opts, fields...)
+}
+
+// DebugCtx prints a debug message with the fields of ctx.
+func (p *Printer) DebugCtx(ctx context.Context, text string, opts *Options, fields ...Field) {
+	_, _ = p.EchoCtx(ctx, MessageDebug, text, opts, fields...)
+}
+
+// CriticalCtx prints a critical message with the fields of ctx.
+func (p *Printer) CriticalCtx(ctx context.Context, text string, opts *Options, fields ...Field) {
+	_, _ = p.EchoCtx(ctx, MessageCritical, text, opts, fields...)
+}
+
+// DeleteCtx prints a delete message with the fields of ctx.
+func (p *Printer) DeleteCtx(ctx context.Context, text string, opts *Options, fields ...Field) {
+	_, _ = p.EchoCtx(ctx, MessageDelete, text, opts, fields...)
+}
+
+// UpdateCtx prints an update message with the fields of ctx.
+func (p *Printer) UpdateCtx(ctx context.Context, text string, opts *Options, fields ...Field) {
+	_, _ = p.EchoCtx(ctx, MessageUpdate, text, opts, fields...)
+}
+
+// InstallCtx prints an install message with the fields of ctx.
+func (p *Printer) InstallCtx(ctx context.Context, text string, opts *Options, fields ...Field) {
+	_, _ = p.EchoCtx(ctx, MessageInstall, text, opts, fields...)
+}
+
+// UpgradeCtx prints an upgrade message with the fields of ctx.
+func (p *Printer) UpgradeCtx(ctx context.Context, text string, opts *Options, fields ...Field) {
+	_, _ = p.EchoCtx(ctx, MessageUpgrade, text, opts, fields...)
+}
+
+// EditCtx prints an edit message with the fields of ctx.
+func (p *Printer) EditCtx(ctx context.Context, text string, opts *Options, fields ...Field) {
+	_, _ = p.EchoCtx(ctx, MessageEdit, text, opts, fields...)
+}
+
+// NewCtx prints a new message with the fields of ctx.
+func (p *Printer) NewCtx(ctx context.Context, text string, opts *Options, fields ...Field) {
+	_, _ = p.EchoCtx(ctx, MessageNew, text, opts, fields...)
+}
+
+// DownloadCtx prints a download message with the fields of ctx.
+func (p *Printer) DownloadCtx(ctx context.Context, text string, opts *Options, fields ...Field) {
+	_, _ = p.EchoCtx(ctx, MessageDownload, text, opts, fields...)
+}
+
+// UploadCtx prints an upload message with the fields of ctx.
+func (p *Printer) UploadCtx(ctx context.Context, text string, opts *Options, fields ...Field) {
+	_, _ = p.EchoCtx(ctx, MessageUpload, text, opts, fields...)
+}
+
+// SyncCtx prints a sync message with the fields of ctx.
+func (p *Printer) SyncCtx(ctx context.Context, text string, opts *Options, fields ...Field) {
+	_, _ = p.EchoCtx(ctx, MessageSync, text, opts, fields...)
+}
+
+// SearchCtx prints a search message with the fields of ctx.
+func (p *Printer) SearchCtx(ctx context.Context, text string, opts *Options, fields ...Field) {
+	_, _ = p.EchoCtx(ctx, MessageSearch, text, opts, fields...)
+}
+
+// EchoCtx discards the message and returns its text.
+func (n NopPrinter) EchoCtx(_ context.Context, msgType MessageType, text string, opts *Options, fields ...Field) (string, error) {
+	return n.Echo(msgType, text, opts, fields...)
+}
+
+// SuccessCtx discards the message.
+func (NopPrinter) SuccessCtx(context.Context, string, *Options, ...Field) {}
+
+// ErrorCtx discards the message.
+func (NopPrinter) ErrorCtx(context.Context, string, *Options, ...Field) {}
+
+// WarningCtx discards the message.
+func (NopPrinter) WarningCtx(context.Context, string, *Options, ...Field) {}
+
+// InfoCtx discards the message.
+func (NopPrinter) InfoCtx(context.Context, string, *Options, ...Field) {}
+
+// DebugCtx discards the message.
+func (NopPrinter) DebugCtx(context.Context, string, *Options, ...Field) {}
+
+// CriticalCtx discards the message.
+func (NopPrinter) CriticalCtx(context.Context, string, *Options, ...Field) {}
+
+// DeleteCtx discards the message.
+func (NopPrinter) DeleteCtx(context.Context, string, *Options, ...Field) {}
+
+// UpdateCtx discards the message.
+func (NopPrinter) UpdateCtx(context.Context, string, *Options, ...Field) {}
+
+// InstallCtx discards the message.
+func (NopPrinter) InstallCtx(context.Context, string, *Options, ...Field) {}
+
+// UpgradeCtx discards the message.
+func (NopPrinter) UpgradeCtx(context.Context, string, *Options, ...Field) {}
+
+// EditCtx discards the message.
+func (NopPrinter) EditCtx(context.Context, string, *Options, ...Field) {}
+
+// NewCtx discards the message.
+func (NopPrinter) NewCtx(context.Context, string, *Options, ...Field) {}
+
+// DownloadCtx discards the message.
+func (NopPrinter) DownloadCtx(context.Context, string, *Options, ...Field) {}
+
+// UploadCtx discards the message.
+func (NopPrinter) UploadCtx(context.Context, string, *Options, ...Field) {}
+
+// SyncCtx discards the message.
+func (NopPrinter) SyncCtx(context.Context, string, *Options, ...Field) {}
+
+// SearchCtx discards the message.
+func (NopPrinter) SearchCtx(context.Context, string, *Options, ...Field) {}
+
+// --- Context-aware functions (print through the printer of ctx) ---
+
+// SuccessCtx prints a success message through the printer stored in ctx,
+// with the fields of ctx.
+func SuccessCtx(ctx context.Context, text string, opts *Options, fields ...Field) {
+	FromContext(ctx).SuccessCtx(ctx, text, opts, fields...)
+}
+
+// ErrorCtx prints an error message through the printer stored in ctx,
+// with the fields of ctx.
+func ErrorCtx(ctx context.Context, text string, opts *Options, fields ...Field) {
+	FromContext(ctx).ErrorCtx(ctx, text, opts, fields...)
+}
+
+// WarningCtx prints a warning message through the printer stored in ctx,
+// with the fields of ctx.
+func WarningCtx(ctx context.Context, text string, opts *Options, fields ...Field) {
+	FromContext(ctx).WarningCtx(ctx, text, opts, fields...)
+}
+
+// InfoCtx prints an info message through the printer stored in ctx,
+// with the fields of ctx.
+func InfoCtx(ctx context.Context, text string, opts *Options, fields ...Field) {
+	FromContext(ctx).InfoCtx(ctx, text, opts, fields...)
+}
+
+// DebugCtx prints a debug message through the printer stored in ctx,
+// with the fields of ctx.
+func DebugCtx(ctx context.Context, text string, opts *Options, fields ...Field) {
+	FromContext(ctx).DebugCtx(ctx, text, opts, fields...)
+}
+
+// CriticalCtx prints a critical message through the printer stored in ctx,
+// with the fields of ctx.
+func CriticalCtx(ctx context.Context, text string, opts *Options, fields ...Field) {
+	FromContext(ctx).CriticalCtx(ctx, text, opts, fields...)
+}
+
+// DeleteCtx prints a delete message through the printer stored in ctx,
+// with the fields of ctx.
+func DeleteCtx(ctx context.Context, text string, opts *Options, fields ...Field) {
+	FromContext(ctx).DeleteCtx(ctx, text, opts, fields...)
+}
+
+// UpdateCtx prints an update message through the printer stored in ctx,
+// with the fields of ctx.
+func UpdateCtx(ctx context.Context, text string, opts *Options, fields ...Field) {
+	FromContext(ctx).UpdateCtx(ctx, text, opts, fields...)
+}
+
+// InstallCtx prints an install message through the printer stored in ctx,
+// with the fields of ctx.
+func InstallCtx(ctx context.Context, text string, opts *Options, fields ...Field) {
+	FromContext(ctx).InstallCtx(ctx, text, opts, fields...)
+}
+
+// UpgradeCtx prints an upgrade message through the printer stored in ctx,
+// with the fields of ctx.
+func UpgradeCtx(ctx context.Context, text string, opts *Options, fields ...Field) {
+	FromContext(ctx).UpgradeCtx(ctx, text, opts, fields...)
+}
+
+// EditCtx prints an edit message through the printer stored in ctx,
+// with the fields of ctx.
+func EditCtx(ctx context.Context, text string, opts *Options, fields ...Field) {
+	FromContext(ctx).EditCtx(ctx, text, opts, fields...)
+}
+
+// NewCtx prints a new message through the printer stored in ctx,
+// with the fields of ctx.
+func NewCtx(ctx context.Context, text string, opts *Options, fields ...Field) {
+	FromContext(ctx).NewCtx(ctx, text, opts, fields...)
+}
+
+// DownloadCtx prints a download message through the printer stored in ctx,
+// with the fields of ctx.
+func DownloadCtx(ctx context.Context, text string, opts *Options, fields ...Field) {
+	FromContext(ctx).DownloadCtx(ctx, text, opts, fields...)
+}
+
+// UploadCtx prints an upload message through the printer stored in ctx,
+// with the fields of ctx.
+func UploadCtx(ctx context.Context, text string, opts *Options, fields ...Field) {
+	FromContext(ctx).UploadCtx(ctx, text, opts, fields...)
+}
+
+// SyncCtx prints a sync message through the printer stored in ctx,
+// with the fields of ctx.
+func SyncCtx(ctx context.Context, text string, opts *Options, fields ...Field) {
+	FromContext(ctx).SyncCtx(ctx, text, opts, fields...)
+}
+
+// SearchCtx prints a search message through the printer stored in ctx,
+// with the fields of ctx.
+func SearchCtx(ctx context.Context, text string, opts *Options, fields ...Field) {
+	FromContext(ctx).SearchCtx(ctx, text, opts, fields...)
+}
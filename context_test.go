@@ -0,0 +1,82 @@
+package utify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/jsas4coding/utify/pkg/colors"
+	"github.com/jsas4coding/utify/pkg/layout"
+	"github.com/jsas4coding/utify/pkg/logger"
+)
+
+func TestContextFunctions(t *testing.T) {
+	var out, logBuf bytes.Buffer
+	p := NewPrinter(WithOutput(&out), WithErrorOutput(&out), WithColorProfile(colors.ProfileNone),
+		WithLogger(logger.NewWriter(&logBuf)), WithContextFields(true))
+
+	ctx := WithPrinter(context.Background(), p.With(WithScope("api")))
+	ctx = WithRequestID(ctx, "req-1")
+	ctx = WithTrace(ctx, "trace-1", "span-1")
+	ctx = WithFields(ctx, F("user", "ana"))
+
+	InfoCtx(ctx, "handled", OptionsDefault(), F("status", 200))
+
+	if !strings.Contains(out.String(), "user=ana request_id=req-1 trace_id=trace-1 span_id=span-1 status=200") {
+		t.Errorf("Expected context fields on the console, got %q", out.String())
+	}
+	var entry map[string]any
+	if err := json.Unmarshal(logBuf.Bytes(), &entry); err != nil {
+		t.Fatalf("Invalid log entry %q: %v", logBuf.String(), err)
+	}
+	for k, v := range map[string]any{"request_id": "req-1", "trace_id": "trace-1", "span_id": "span-1",
+		"user": "ana", "status": float64(200)} {
+		if entry[k] != v {
+			t.Errorf("Expected %s to be %v, got %v", k, v, entry[k])
+		}
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	if FromContext(context.Background()) != DefaultPrinter() {
+		t.Error("Expected the default printer without a stored printer")
+	}
+	p := NewPrinter()
+	if FromContext(WithPrinter(context.Background(), p)) != p {
+		t.Error("Expected the stored printer")
+	}
+}
+
+func TestPrinterWith(t *testing.T) {
+	var out bytes.Buffer
+	p := NewPrinter(WithOutput(&out), WithColorProfile(colors.ProfileNone), WithLogger(logger.NewWriter(nil)),
+		WithLayout(layout.MustParse("[{scope}] {message}")))
+	scoped := p.With(WithScope("db"))
+
+	scoped.Info("scoped", OptionsDefault())
+	p.Info("plain", OptionsDefault())
+
+	if out.String() != "[db] scoped\n[] plain\n" {
+		t.Errorf("Expected only the derived printer to be scoped, got %q", out.String())
+	}
+	if scoped.ColorTable() != p.ColorTable() || scoped.Logger() != p.Logger() {
+		t.Error("Expected the derived printer to share the color table and logger")
+	}
+
+	out.Reset()
+	child := p.With(WithLayout(layout.MustParse("CHILD {message}")))
+	p.Info("parent", OptionsDefault())
+	child.Info("child", OptionsDefault())
+	if out.String() != "[] parent\nCHILD child\n" {
+		t.Errorf("Expected the derived layout to leave the parent unchanged, got %q", out.String())
+	}
+}
+
+func TestNopPrinterCtx(t *testing.T) {
+	text, err := NopPrinter{}.EchoCtx(context.Background(), MessageError, "dropped", OptionsDefault())
+	if text != "dropped" || err != ErrSilent {
+		t.Errorf("Expected the text and ErrSilent, got %q, %v", text, err)
+	}
+}
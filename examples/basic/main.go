@@ -8,6 +8,7 @@ import (
 
 func main() {
 	opts := utify.OptionsDefault()
+	utify.SetVerbosity(1) // Show debug messages
 
 	utify.Success("Operation completed successfully!", opts)
 	utify.Error("An error occurred!", opts)
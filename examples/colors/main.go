@@ -1,14 +1,24 @@
 package main
 
-import "github.com/jsas4coding/utify"
+import (
+	"fmt"
+
+	"github.com/jsas4coding/utify"
+)
 
 func main() {
-	// Set custom colors
-	utify.SetColorTable(map[string]string{
-		string(utify.MessageSuccess): "\033[95m", // Purple
-		string(utify.MessageError):   "\033[96m", // Light cyan
-		string(utify.MessageWarning): "\033[93m", // Bright yellow
+	// Set custom colors (ANSI sequences, names, hex, rgb() or 256-palette indexes)
+	err := utify.SetColorTable(map[string]string{
+		string(utify.MessageSuccess): "\033[95m",           // Purple
+		string(utify.MessageError):   "brightCyan",         // Light cyan
+		string(utify.MessageWarning): "#FFD700",            // Gold, downsampled on 16/256-color terminals
+		string(utify.MessageDebug):   "rgb(128, 128, 128)", // Gray
+		string(utify.MessageInfo):    "39",                 // Palette index 39 (deep sky blue)
 	})
+	if err != nil {
+		fmt.Println("invalid color table:", err)
+		return
+	}
 
 	opts := utify.OptionsDefault()
 
@@ -25,6 +25,7 @@ func main() {
 	// Test with icons enabled
 	fmt.Println("--- With Icons Enabled ---")
 	opts := utify.OptionsDefault().WithIcon()
+	utify.SetVerbosity(1) // Show debug messages
 
 	utify.Success("Operation completed successfully!", opts)
 	utify.Error("An error occurred!", opts)
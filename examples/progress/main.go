@@ -0,0 +1,59 @@
+package main
+
+import (
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jsas4coding/utify"
+)
+
+// slowReader returns one chunk per tick, like a slow network connection.
+type slowReader struct {
+	r io.Reader
+}
+
+func (s slowReader) Read(p []byte) (int, error) {
+	time.Sleep(20 * time.Millisecond)
+	return s.r.Read(p[:min(len(p), 64<<10)])
+}
+
+func main() {
+	opts := utify.OptionsDefault()
+
+	// A single bar fed by an io.Reader
+	size := int64(4 << 20)
+	bar := utify.NewProgress(utify.MessageDownload, "app.tar.gz", size)
+	_, _ = io.Copy(io.Discard, bar.Reader(slowReader{strings.NewReader(strings.Repeat("x", int(size)))}))
+	bar.Done()
+	bar.Wait()
+	utify.Success("Downloaded app.tar.gz", opts)
+
+	// Parallel uploads in a group
+	group := utify.NewProgressGroup(nil)
+	var wg sync.WaitGroup
+	for i, name := range []string{"logs.zip", "report.pdf", "backup.db"} {
+		b := group.Add(utify.MessageUpload, name, int64(50*(i+1))).WithBytes()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range b.Total() {
+				time.Sleep(10 * time.Millisecond)
+				b.Increment()
+			}
+			b.Done()
+		}()
+	}
+	wg.Wait()
+	group.Wait()
+
+	// An indeterminate bar while the amount of work is unknown
+	install := utify.NewProgress(utify.MessageInstall, "Resolving dependencies", 0)
+	for range 40 {
+		time.Sleep(25 * time.Millisecond)
+		install.Increment()
+	}
+	install.Done()
+	install.Wait()
+}
@@ -0,0 +1,29 @@
+package utify
+
+import (
+	"fmt"
+
+	"github.com/jsas4coding/utify/pkg/field"
+)
+
+// Field is a typed key-value pair attached to a message. The console shows
+// fields as key=value pairs and the JSON log as top-level properties.
+type Field = field.Field
+
+// Bytes is a byte count shown in human-readable units, e.g. "1.5 MiB".
+type Bytes = field.Bytes
+
+// F returns a Field. Durations, errors, Bytes and time.Time values get
+// type-aware formatting:
+//
+//	utify.Info("deployed", opts, utify.F("service", svc), utify.F("took", d))
+func F(key string, value any) Field {
+	return field.F(key, value)
+}
+
+// sprintf formats text with args, taking Field arguments out as fields, so
+// the formatted variants accept fields anywhere in args.
+func sprintf(text string, args []any) (string, []Field) {
+	args, fields := field.Split(args)
+	return fmt.Sprintf(text, args...), fields
+}
@@ -17,15 +17,23 @@ func CreateDataDir(t *testing.T) {
 }
 
 func CaptureOutput(f func()) string {
+	return captureFile(&os.Stdout, f)
+}
+
+func CaptureStderr(f func()) string {
+	return captureFile(&os.Stderr, f)
+}
+
+func captureFile(file **os.File, f func()) string {
 	var buf bytes.Buffer
-	old := os.Stdout
+	old := *file
 	r, w, _ := os.Pipe()
-	os.Stdout = w
+	*file = w
 
 	f()
 
 	_ = w.Close()
-	os.Stdout = old
+	*file = old
 	_, _ = buf.ReadFrom(r)
 	return buf.String()
 }
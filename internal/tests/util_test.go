@@ -3,6 +3,7 @@ package tests
 import (
 	"fmt"
 	"log"
+	"os"
 	"strings"
 	"testing"
 )
@@ -17,6 +18,16 @@ func TestCaptureOutput(t *testing.T) {
 	}
 }
 
+func TestCaptureStderr(t *testing.T) {
+	expected := "Hello, stderr!"
+	output := CaptureStderr(func() {
+		fmt.Fprint(os.Stderr, expected)
+	})
+	if !strings.Contains(output, expected) {
+		t.Errorf("expected stderr output to contain %q, got %q", expected, output)
+	}
+}
+
 func TestCaptureLogOutput(t *testing.T) {
 	expected := "Hello, log!"
 	output := CaptureLogOutput(func() {
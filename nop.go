@@ -0,0 +1,389 @@
+package utify
+
+import "github.com/jsas4coding/utify/pkg/messages"
+
+// NopPrinter is a Messenger that discards every message. Get methods still
+// return the text and ErrSilent for error types, so control flow matches a
+// real Printer.
+type NopPrinter struct{}
+
+var (
+	_ Messenger = (*Printer)(nil)
+	_ Messenger = NopPrinter{}
+)
+
+// Echo discards the message and returns its text.
+func (NopPrinter) Echo(msgType MessageType, text string, _ *Options, _ ...Field) (string, error) {
+	if messages.IsErrorType(msgType) {
+		return text, ErrSilent
+	}
+	return text, nil
+}
+
+// Success discards the message.
+func (NopPrinter) Success(string, *Options, ...Field) {}
+
+// Error discards the message.
+func (NopPrinter) Error(string, *Options, ...Field) {}
+
+// Warning discards the message.
+func (NopPrinter) Warning(string, *Options, ...Field) {}
+
+// Info discards the message.
+func (NopPrinter) Info(string, *Options, ...Field) {}
+
+// Debug discards the message.
+func (NopPrinter) Debug(string, *Options, ...Field) {}
+
+// Critical discards the message.
+func (NopPrinter) Critical(string, *Options, ...Field) {}
+
+// Delete discards the message.
+func (NopPrinter) Delete(string, *Options, ...Field) {}
+
+// Update discards the message.
+func (NopPrinter) Update(string, *Options, ...Field) {}
+
+// Install discards the message.
+func (NopPrinter) Install(string, *Options, ...Field) {}
+
+// Upgrade discards the message.
+func (NopPrinter) Upgrade(string, *Options, ...Field) {}
+
+// Edit discards the message.
+func (NopPrinter) Edit(string, *Options, ...Field) {}
+
+// New discards the message.
+func (NopPrinter) New(string, *Options, ...Field) {}
+
+// Download discards the message.
+func (NopPrinter) Download(string, *Options, ...Field) {}
+
+// Upload discards the message.
+func (NopPrinter) Upload(string, *Options, ...Field) {}
+
+// Sync discards the message.
+func (NopPrinter) Sync(string, *Options, ...Field) {}
+
+// Search discards the message.
+func (NopPrinter) Search(string, *Options, ...Field) {}
+
+// Successf discards the message.
+func (NopPrinter) Successf(string, *Options, ...any) {}
+
+// Errorf discards the message.
+func (NopPrinter) Errorf(string, *Options, ...any) {}
+
+// Warningf discards the message.
+func (NopPrinter) Warningf(string, *Options, ...any) {}
+
+// Infof discards the message.
+func (NopPrinter) Infof(string, *Options, ...any) {}
+
+// Debugf discards the message.
+func (NopPrinter) Debugf(string, *Options, ...any) {}
+
+// Criticalf discards the message.
+func (NopPrinter) Criticalf(string, *Options, ...any) {}
+
+// Deletef discards the message.
+func (NopPrinter) Deletef(string, *Options, ...any) {}
+
+// Updatef discards the message.
+func (NopPrinter) Updatef(string, *Options, ...any) {}
+
+// Installf discards the message.
+func (NopPrinter) Installf(string, *Options, ...any) {}
+
+// Upgradef discards the message.
+func (NopPrinter) Upgradef(string, *Options, ...any) {}
+
+// Editf discards the message.
+func (NopPrinter) Editf(string, *Options, ...any) {}
+
+// Newf discards the message.
+func (NopPrinter) Newf(string, *Options, ...any) {}
+
+// Downloadf discards the message.
+func (NopPrinter) Downloadf(string, *Options, ...any) {}
+
+// Uploadf discards the message.
+func (NopPrinter) Uploadf(string, *Options, ...any) {}
+
+// Syncf discards the message.
+func (NopPrinter) Syncf(string, *Options, ...any) {}
+
+// Searchf discards the message.
+func (NopPrinter) Searchf(string, *Options, ...any) {}
+
+// GetSuccess returns the message text without printing it.
+func (n NopPrinter) GetSuccess(text string, opts *Options, _ ...Field) (string, error) {
+	return n.Echo(MessageSuccess, text, opts)
+}
+
+// GetError returns the message text without printing it.
+func (n NopPrinter) GetError(text string, opts *Options, _ ...Field) (string, error) {
+	return n.Echo(MessageError, text, opts)
+}
+
+// GetWarning returns the message text without printing it.
+func (n NopPrinter) GetWarning(text string, opts *Options, _ ...Field) (string, error) {
+	return n.Echo(MessageWarning, text, opts)
+}
+
+// GetInfo returns the message text without printing it.
+func (n NopPrinter) GetInfo(text string, opts *Options, _ ...Field) (string, error) {
+	return n.Echo(MessageInfo, text, opts)
+}
+
+// GetDebug returns the message text without printing it.
+func (n NopPrinter) GetDebug(text string, opts *Options, _ ...Field) (string, error) {
+	return n.Echo(MessageDebug, text, opts)
+}
+
+// GetCritical returns the message text without printing it.
+func (n NopPrinter) GetCritical(text string, opts *Options, _ ...Field) (string, error) {
+	return n.Echo(MessageCritical, text, opts)
+}
+
+// GetDelete returns the message text without printing it.
+func (n NopPrinter) GetDelete(text string, opts *Options, _ ...Field) (string, error) {
+	return n.Echo(MessageDelete, text, opts)
+}
+
+// GetUpdate returns the message text without printing it.
+func (n NopPrinter) GetUpdate(text string, opts *Options, _ ...Field) (string, error) {
+	return n.Echo(MessageUpdate, text, opts)
+}
+
+// GetInstall returns the message text without printing it.
+func (n NopPrinter) GetInstall(text string, opts *Options, _ ...Field) (string, error) {
+	return n.Echo(MessageInstall, text, opts)
+}
+
+// GetUpgrade returns the message text without printing it.
+func (n NopPrinter) GetUpgrade(text string, opts *Options, _ ...Field) (string, error) {
+	return n.Echo(MessageUpgrade, text, opts)
+}
+
+// GetEdit returns the message text without printing it.
+func (n NopPrinter) GetEdit(text string, opts *Options, _ ...Field) (string, error) {
+	return n.Echo(MessageEdit, text, opts)
+}
+
+// GetNew returns the message text without printing it.
+func (n NopPrinter) GetNew(text string, opts *Options, _ ...Field) (string, error) {
+	return n.Echo(MessageNew, text, opts)
+}
+
+// GetDownload returns the message text without printing it.
+func (n NopPrinter) GetDownload(text string, opts *Options, _ ...Field) (string, error) {
+	return n.Echo(MessageDownload, text, opts)
+}
+
+// GetUpload returns the message text without printing it.
+func (n NopPrinter) GetUpload(text string, opts *Options, _ ...Field) (string, error) {
+	return n.Echo(MessageUpload, text, opts)
+}
+
+// GetSync returns the message text without printing it.
+func (n NopPrinter) GetSync(text string, opts *Options, _ ...Field) (string, error) {
+	return n.Echo(MessageSync, text, opts)
+}
+
+// GetSearch returns the message text without printing it.
+func (n NopPrinter) GetSearch(text string, opts *Options, _ ...Field) (string, error) {
+	return n.Echo(MessageSearch, text, opts)
+}
+
+// GetSuccessf returns the formatted message text without printing it.
+func (n NopPrinter) GetSuccessf(text string, opts *Options, args ...any) (string, error) {
+	msg, _ := sprintf(text, args)
+	return n.GetSuccess(msg, opts)
+}
+
+// GetErrorf returns the formatted message text without printing it.
+func (n NopPrinter) GetErrorf(text string, opts *Options, args ...any) (string, error) {
+	msg, _ := sprintf(text, args)
+	return n.GetError(msg, opts)
+}
+
+// GetWarningf returns the formatted message text without printing it.
+func (n NopPrinter) GetWarningf(text string, opts *Options, args ...any) (string, error) {
+	msg, _ := sprintf(text, args)
+	return n.GetWarning(msg, opts)
+}
+
+// GetInfof returns the formatted message text without printing it.
+func (n NopPrinter) GetInfof(text string, opts *Options, args ...any) (string, error) {
+	msg, _ := sprintf(text, args)
+	return n.GetInfo(msg, opts)
+}
+
+// GetDebugf returns the formatted message text without printing it.
+func (n NopPrinter) GetDebugf(text string, opts *Options, args ...any) (string, error) {
+	msg, _ := sprintf(text, args)
+	return n.GetDebug(msg, opts)
+}
+
+// GetCriticalf returns the formatted message text without printing it.
+func (n NopPrinter) GetCriticalf(text string, opts *Options, args ...any) (string, error) {
+	msg, _ := sprintf(text, args)
+	return n.GetCritical(msg, opts)
+}
+
+// GetDeletef returns the formatted message text without printing it.
+func (n NopPrinter) GetDeletef(text string, opts *Options, args ...any) (string, error) {
+	msg, _ := sprintf(text, args)
+	return n.GetDelete(msg, opts)
+}
+
+// GetUpdatef returns the formatted message text without printing it.
+func (n NopPrinter) GetUpdatef(text string, opts *Options, args ...any) (string, error) {
+	msg, _ := sprintf(text, args)
+	return n.GetUpdate(msg, opts)
+}
+
+// GetInstallf returns the formatted message text without printing it.
+func (n NopPrinter) GetInstallf(text string, opts *Options, args ...any) (string, error) {
+	msg, _ := sprintf(text, args)
+	return n.GetInstall(msg, opts)
+}
+
+// GetUpgradef returns the formatted message text without printing it.
+func (n NopPrinter) GetUpgradef(text string, opts *Options, args ...any) (string, error) {
+	msg, _ := sprintf(text, args)
+	return n.GetUpgrade(msg, opts)
+}
+
+// GetEditf returns the formatted message text without printing it.
+func (n NopPrinter) GetEditf(text string, opts *Options, args ...any) (string, error) {
+	msg, _ := sprintf(text, args)
+	return n.GetEdit(msg, opts)
+}
+
+// GetNewf returns the formatted message text without printing it.
+func (n NopPrinter) GetNewf(text string, opts *Options, args ...any) (string, error) {
+	msg, _ := sprintf(text, args)
+	return n.GetNew(msg, opts)
+}
+
+// GetDownloadf returns the formatted message text without printing it.
+func (n NopPrinter) GetDownloadf(text string, opts *Options, args ...any) (string, error) {
+	msg, _ := sprintf(text, args)
+	return n.GetDownload(msg, opts)
+}
+
+// GetUploadf returns the formatted message text without printing it.
+func (n NopPrinter) GetUploadf(text string, opts *Options, args ...any) (string, error) {
+	msg, _ := sprintf(text, args)
+	return n.GetUpload(msg, opts)
+}
+
+// GetSyncf returns the formatted message text without printing it.
+func (n NopPrinter) GetSyncf(text string, opts *Options, args ...any) (string, error) {
+	msg, _ := sprintf(text, args)
+	return n.GetSync(msg, opts)
+}
+
+// GetSearchf returns the formatted message text without printing it.
+func (n NopPrinter) GetSearchf(text string, opts *Options, args ...any) (string, error) {
+	msg, _ := sprintf(text, args)
+	return n.GetSearch(msg, opts)
+}
+
+// LogSuccess discards the message.
+func (NopPrinter) LogSuccess(string, ...Field) {}
+
+// LogError discards the message.
+func (NopPrinter) LogError(string, ...Field) {}
+
+// LogWarning discards the message.
+func (NopPrinter) LogWarning(string, ...Field) {}
+
+// LogInfo discards the message.
+func (NopPrinter) LogInfo(string, ...Field) {}
+
+// LogDebug discards the message.
+func (NopPrinter) LogDebug(string, ...Field) {}
+
+// LogCritical discards the message.
+func (NopPrinter) LogCritical(string, ...Field) {}
+
+// LogDelete discards the message.
+func (NopPrinter) LogDelete(string, ...Field) {}
+
+// LogUpdate discards the message.
+func (NopPrinter) LogUpdate(string, ...Field) {}
+
+// LogInstall discards the message.
+func (NopPrinter) LogInstall(string, ...Field) {}
+
+// LogUpgrade discards the message.
+func (NopPrinter) LogUpgrade(string, ...Field) {}
+
+// LogEdit discards the message.
+func (NopPrinter) LogEdit(string, ...Field) {}
+
+// LogNew discards the message.
+func (NopPrinter) LogNew(string, ...Field) {}
+
+// LogDownload discards the message.
+func (NopPrinter) LogDownload(string, ...Field) {}
+
+// LogUpload discards the message.
+func (NopPrinter) LogUpload(string, ...Field) {}
+
+// LogSync discards the message.
+func (NopPrinter) LogSync(string, ...Field) {}
+
+// LogSearch discards the message.
+func (NopPrinter) LogSearch(string, ...Field) {}
+
+// LogSuccessf discards the message.
+func (NopPrinter) LogSuccessf(string, ...any) {}
+
+// LogErrorf discards the message.
+func (NopPrinter) LogErrorf(string, ...any) {}
+
+// LogWarningf discards the message.
+func (NopPrinter) LogWarningf(string, ...any) {}
+
+// LogInfof discards the message.
+func (NopPrinter) LogInfof(string, ...any) {}
+
+// LogDebugf discards the message.
+func (NopPrinter) LogDebugf(string, ...any) {}
+
+// LogCriticalf discards the message.
+func (NopPrinter) LogCriticalf(string, ...any) {}
+
+// LogDeletef discards the message.
+func (NopPrinter) LogDeletef(string, ...any) {}
+
+// LogUpdatef discards the message.
+func (NopPrinter) LogUpdatef(string, ...any) {}
+
+// LogInstallf discards the message.
+func (NopPrinter) LogInstallf(string, ...any) {}
+
+// LogUpgradef discards the message.
+func (NopPrinter) LogUpgradef(string, ...any) {}
+
+// LogEditf discards the message.
+func (NopPrinter) LogEditf(string, ...any) {}
+
+// LogNewf discards the message.
+func (NopPrinter) LogNewf(string, ...any) {}
+
+// LogDownloadf discards the message.
+func (NopPrinter) LogDownloadf(string, ...any) {}
+
+// LogUploadf discards the message.
+func (NopPrinter) LogUploadf(string, ...any) {}
+
+// LogSyncf discards the message.
+func (NopPrinter) LogSyncf(string, ...any) {}
+
+// LogSearchf discards the message.
+func (NopPrinter) LogSearchf(string, ...any) {}
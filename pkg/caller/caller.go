@@ -0,0 +1,81 @@
+package caller
+
+import (
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Caller is the source location a message was produced at.
+type Caller struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Function string `json:"function"`
+}
+
+// String returns the short form of the location, the file with its parent
+// directory and the line, e.g. "server/handler.go:42".
+func (c Caller) String() string {
+	file := c.File
+	if i := strings.LastIndexByte(file, '/'); i >= 0 {
+		if j := strings.LastIndexByte(file[:i], '/'); j >= 0 {
+			file = file[j+1:]
+		}
+	}
+	return file + ":" + strconv.Itoa(c.Line)
+}
+
+// root is the source directory of the utify module, derived from the
+// location of this file.
+var root = func() string {
+	_, file, _, ok := runtime.Caller(0)
+	if !ok {
+		return ""
+	}
+	return filepath.ToSlash(filepath.Dir(filepath.Dir(filepath.Dir(file))))
+}()
+
+// Capture returns the caller of the first function outside utify and
+// log/slog on the stack, after skipping skip more frames, so that wrappers
+// around utify can report their own callers. It returns nil if the stack
+// is not deep enough.
+func Capture(skip int) *Caller {
+	var pcs [32]uintptr
+	n := runtime.Callers(2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+
+	external := false
+	for {
+		frame, more := frames.Next()
+		if !external && !internal(frame) {
+			external = true
+		}
+		if external {
+			if skip == 0 {
+				return &Caller{File: frame.File, Line: frame.Line, Function: frame.Function}
+			}
+			skip--
+		}
+		if !more {
+			return nil
+		}
+	}
+}
+
+// internal reports whether frame belongs to the utify library or log/slog.
+// Tests, examples and commands of the module count as callers.
+func internal(frame runtime.Frame) bool {
+	if strings.HasPrefix(frame.Function, "log/slog.") {
+		return true
+	}
+	if root == "" || strings.HasSuffix(frame.File, "_test.go") {
+		return false
+	}
+	file := filepath.ToSlash(frame.File)
+	if !strings.HasPrefix(file, root+"/") {
+		return false
+	}
+	rel := file[len(root)+1:]
+	return !strings.Contains(rel, "/") || strings.HasPrefix(rel, "pkg/")
+}
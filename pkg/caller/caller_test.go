@@ -0,0 +1,74 @@
+package caller
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestCapture(t *testing.T) {
+	c := Capture(0)
+	_, _, line, _ := runtime.Caller(0)
+	if c == nil {
+		t.Fatal("Expected a caller")
+	}
+	if !strings.HasSuffix(c.File, "caller_test.go") || c.Line != line-1 {
+		t.Errorf("Expected caller_test.go:%d, got %s:%d", line-1, c.File, c.Line)
+	}
+	if !strings.HasSuffix(c.Function, "caller.TestCapture") {
+		t.Errorf("Unexpected function %q", c.Function)
+	}
+}
+
+func helper() *Caller {
+	return Capture(1)
+}
+
+func TestCaptureSkip(t *testing.T) {
+	c := helper()
+	_, _, line, _ := runtime.Caller(0)
+	if c == nil || c.Line != line-1 || !strings.HasSuffix(c.Function, "TestCaptureSkip") {
+		t.Errorf("Expected skip to report the helper's caller, got %+v", c)
+	}
+}
+
+func TestCaptureTooDeep(t *testing.T) {
+	if c := Capture(1000); c != nil {
+		t.Errorf("Expected nil for a skip beyond the stack, got %+v", c)
+	}
+}
+
+func TestCallerString(t *testing.T) {
+	tests := []struct {
+		file     string
+		expected string
+	}{
+		{"/src/app/server/handler.go", "server/handler.go:42"},
+		{"handler.go", "handler.go:42"},
+		{"server/handler.go", "server/handler.go:42"},
+	}
+	for _, tt := range tests {
+		if got := (Caller{File: tt.file, Line: 42}).String(); got != tt.expected {
+			t.Errorf("String() for %q = %q, expected %q", tt.file, got, tt.expected)
+		}
+	}
+}
+
+func TestInternal(t *testing.T) {
+	tests := []struct {
+		frame    runtime.Frame
+		expected bool
+	}{
+		{runtime.Frame{File: root + "/utify.go"}, true},
+		{runtime.Frame{File: root + "/pkg/formatter/formatter.go"}, true},
+		{runtime.Frame{File: root + "/utify_test.go"}, false},
+		{runtime.Frame{File: root + "/examples/basic/main.go"}, false},
+		{runtime.Frame{File: "/src/app/main.go"}, false},
+		{runtime.Frame{File: "/go/src/log/slog/logger.go", Function: "log/slog.(*Logger).log"}, true},
+	}
+	for _, tt := range tests {
+		if got := internal(tt.frame); got != tt.expected {
+			t.Errorf("internal(%s) = %v, expected %v", tt.frame.File, got, tt.expected)
+		}
+	}
+}
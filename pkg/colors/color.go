@@ -0,0 +1,348 @@
+package colors
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidColor is returned when a color specification cannot be parsed.
+var ErrInvalidColor = errors.New("invalid color")
+
+type colorKind uint8
+
+const (
+	kindNone colorKind = iota
+	kindANSI
+	kindANSI256
+	kindRGB
+)
+
+// Color is a parsed color that can be rendered for any Profile. The zero
+// Color renders nothing.
+type Color struct {
+	kind    colorKind
+	index   uint8
+	r, g, b uint8
+}
+
+// ANSI returns one of the 16 basic colors; 0-7 are the normal colors and
+// 8-15 their bright variants.
+func ANSI(index uint8) Color {
+	return Color{kind: kindANSI, index: index % 16}
+}
+
+// ANSI256 returns a color from the 256-color palette.
+func ANSI256(index uint8) Color {
+	return Color{kind: kindANSI256, index: index}
+}
+
+// RGB returns a 24-bit color.
+func RGB(r, g, b uint8) Color {
+	return Color{kind: kindRGB, r: r, g: g, b: b}
+}
+
+// namedColors maps color names to the 16 basic colors.
+var namedColors = map[string]uint8{
+	"black": 0, "red": 1, "green": 2, "yellow": 3,
+	"blue": 4, "magenta": 5, "cyan": 6, "white": 7,
+	"gray": 8, "grey": 8, "brightblack": 8,
+	"brightred": 9, "brightgreen": 10, "brightyellow": 11, "brightblue": 12,
+	"brightmagenta": 13, "brightcyan": 14, "brightwhite": 15,
+	"lightred": 9, "lightgreen": 10, "lightyellow": 11, "lightblue": 12,
+	"lightmagenta": 13, "lightcyan": 14,
+}
+
+// ansiNames are the canonical names of the 16 basic colors.
+var ansiNames = [16]string{
+	"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white",
+	"gray", "brightred", "brightgreen", "brightyellow", "brightblue",
+	"brightmagenta", "brightcyan", "brightwhite",
+}
+
+// Parse parses a color specification. Accepted forms are hex ("#ff8800" or
+// "#f80"), "rgb(255, 136, 0)", a 256-palette index ("208"), a color name
+// ("red", "brightBlue", "light-blue") and a raw ANSI foreground sequence such
+// as Red or "\033[38;5;208m".
+func Parse(spec string) (Color, error) {
+	s := strings.TrimSpace(spec)
+	switch {
+	case s == "":
+		return Color{}, fmt.Errorf("%w: empty specification", ErrInvalidColor)
+	case strings.HasPrefix(s, "\033["):
+		return parseSequence(s)
+	case strings.HasPrefix(s, "#"):
+		return parseHex(spec, s[1:])
+	case strings.HasPrefix(strings.ToLower(s), "rgb(") && strings.HasSuffix(s, ")"):
+		return parseRGBFunc(spec, s[4:len(s)-1])
+	}
+
+	if n, err := strconv.Atoi(s); err == nil {
+		if n < 0 || n > 255 {
+			return Color{}, fmt.Errorf("%w: palette index %d out of range 0-255", ErrInvalidColor, n)
+		}
+		return ANSI256(uint8(n)), nil
+	}
+
+	name := strings.NewReplacer("-", "", "_", "", " ", "").Replace(strings.ToLower(s))
+	if index, exists := namedColors[name]; exists {
+		return ANSI(index), nil
+	}
+	return Color{}, fmt.Errorf("%w: %q", ErrInvalidColor, spec)
+}
+
+// MustParse is like Parse but panics if the specification is invalid.
+func MustParse(spec string) Color {
+	c, err := Parse(spec)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+func parseHex(spec, hex string) (Color, error) {
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) != 6 {
+		return Color{}, fmt.Errorf("%w: %q is not #rgb or #rrggbb", ErrInvalidColor, spec)
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return Color{}, fmt.Errorf("%w: %q is not a hex color", ErrInvalidColor, spec)
+	}
+	return RGB(uint8(v>>16), uint8(v>>8), uint8(v)), nil
+}
+
+func parseRGBFunc(spec, args string) (Color, error) {
+	parts := strings.Split(args, ",")
+	if len(parts) != 3 {
+		return Color{}, fmt.Errorf("%w: %q needs three components", ErrInvalidColor, spec)
+	}
+	var rgb [3]uint8
+	for i, part := range parts {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || n < 0 || n > 255 {
+			return Color{}, fmt.Errorf("%w: %q components must be 0-255", ErrInvalidColor, spec)
+		}
+		rgb[i] = uint8(n)
+	}
+	return RGB(rgb[0], rgb[1], rgb[2]), nil
+}
+
+// parseSequence accepts the foreground SGR forms produced by Sequence.
+func parseSequence(spec string) (Color, error) {
+	body, ok := strings.CutSuffix(strings.TrimPrefix(spec, "\033["), "m")
+	if !ok {
+		return Color{}, fmt.Errorf("%w: %q is not an SGR sequence", ErrInvalidColor, spec)
+	}
+
+	var codes []int
+	for _, part := range strings.Split(body, ";") {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 || n > 255 {
+			return Color{}, fmt.Errorf("%w: %q is not an SGR sequence", ErrInvalidColor, spec)
+		}
+		codes = append(codes, n)
+	}
+
+	switch {
+	case len(codes) == 1 && codes[0] >= 30 && codes[0] <= 37:
+		return ANSI(uint8(codes[0] - 30)), nil
+	case len(codes) == 1 && codes[0] >= 90 && codes[0] <= 97:
+		return ANSI(uint8(codes[0] - 90 + 8)), nil
+	case len(codes) == 3 && codes[0] == 38 && codes[1] == 5:
+		return ANSI256(uint8(codes[2])), nil
+	case len(codes) == 5 && codes[0] == 38 && codes[1] == 2:
+		return RGB(uint8(codes[2]), uint8(codes[3]), uint8(codes[4])), nil
+	}
+	return Color{}, fmt.Errorf("%w: %q is not a foreground color sequence", ErrInvalidColor, spec)
+}
+
+// MarshalText implements encoding.TextMarshaler using String.
+func (c Color) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using Parse. Empty text
+// yields the zero Color.
+func (c *Color) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*c = Color{}
+		return nil
+	}
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*c = parsed
+	return nil
+}
+
+// IsZero reports whether c is the zero Color.
+func (c Color) IsZero() bool {
+	return c.kind == kindNone
+}
+
+// String returns a specification that Parse accepts.
+func (c Color) String() string {
+	switch c.kind {
+	case kindANSI:
+		return ansiNames[c.index]
+	case kindANSI256:
+		return strconv.Itoa(int(c.index))
+	case kindRGB:
+		return fmt.Sprintf("#%02x%02x%02x", c.r, c.g, c.b)
+	default:
+		return ""
+	}
+}
+
+// Sequence returns the foreground escape sequence for c, downsampled to the
+// nearest color the profile can show. ProfileAuto renders at full fidelity.
+func (c Color) Sequence(profile Profile) string {
+	return c.sequence(profile, false)
+}
+
+// BackgroundSequence returns the background escape sequence for c.
+func (c Color) BackgroundSequence(profile Profile) string {
+	return c.sequence(profile, true)
+}
+
+func (c Color) sequence(profile Profile, background bool) string {
+	if c.kind == kindNone || profile == ProfileNone {
+		return ""
+	}
+	c = c.downsample(profile)
+
+	base := 38
+	if background {
+		base = 48
+	}
+	switch c.kind {
+	case kindANSI:
+		code := 30 + int(c.index)
+		if c.index >= 8 {
+			code = 90 + int(c.index) - 8
+		}
+		if background {
+			code += 10
+		}
+		return fmt.Sprintf("\033[%dm", code)
+	case kindANSI256:
+		return fmt.Sprintf("\033[%d;5;%dm", base, c.index)
+	default:
+		return fmt.Sprintf("\033[%d;2;%d;%d;%dm", base, c.r, c.g, c.b)
+	}
+}
+
+// downsample converts c to the richest form the profile supports.
+func (c Color) downsample(profile Profile) Color {
+	switch profile {
+	case ProfileANSI:
+		if c.kind == kindANSI {
+			return c
+		}
+		if c.kind == kindANSI256 && c.index < 16 {
+			return ANSI(c.index)
+		}
+		r, g, b := c.rgb()
+		return ANSI(nearestANSI(r, g, b))
+	case ProfileANSI256:
+		if c.kind == kindRGB {
+			return ANSI256(nearestANSI256(c.r, c.g, c.b))
+		}
+	}
+	return c
+}
+
+// rgb returns the 24-bit value of c.
+func (c Color) rgb() (r, g, b uint8) {
+	switch c.kind {
+	case kindANSI:
+		p := ansiPalette[c.index]
+		return p[0], p[1], p[2]
+	case kindANSI256:
+		return palette256(c.index)
+	default:
+		return c.r, c.g, c.b
+	}
+}
+
+// ansiPalette holds the xterm default values of the 16 basic colors.
+var ansiPalette = [16][3]uint8{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// cubeLevels are the channel values of the 6x6x6 color cube (indices 16-231).
+var cubeLevels = [6]uint8{0, 95, 135, 175, 215, 255}
+
+func palette256(index uint8) (r, g, b uint8) {
+	switch {
+	case index < 16:
+		p := ansiPalette[index]
+		return p[0], p[1], p[2]
+	case index < 232:
+		i := index - 16
+		return cubeLevels[i/36], cubeLevels[(i/6)%6], cubeLevels[i%6]
+	default:
+		v := 8 + (index-232)*10
+		return v, v, v
+	}
+}
+
+func nearestANSI(r, g, b uint8) uint8 {
+	best, bestDist := uint8(0), -1
+	for i, p := range ansiPalette {
+		if d := distance(r, g, b, p[0], p[1], p[2]); bestDist < 0 || d < bestDist {
+			best, bestDist = uint8(i), d
+		}
+	}
+	return best
+}
+
+func nearestANSI256(r, g, b uint8) uint8 {
+	ci := [3]int{nearestLevel(r), nearestLevel(g), nearestLevel(b)}
+	cube := uint8(16 + 36*ci[0] + 6*ci[1] + ci[2])
+	cr, cg, cb := palette256(cube)
+
+	avg := (int(r) + int(g) + int(b)) / 3
+	grayIndex := 23
+	if avg < 8 {
+		grayIndex = 0
+	} else if avg < 238 {
+		grayIndex = (avg - 8 + 5) / 10
+	}
+	gray := uint8(232 + grayIndex)
+	gr, gg, gb := palette256(gray)
+
+	if distance(r, g, b, gr, gg, gb) < distance(r, g, b, cr, cg, cb) {
+		return gray
+	}
+	return cube
+}
+
+func nearestLevel(v uint8) int {
+	best, bestDist := 0, 256
+	for i, level := range cubeLevels {
+		d := int(v) - int(level)
+		if d < 0 {
+			d = -d
+		}
+		if d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return best
+}
+
+// distance is a perceptually weighted squared distance ("redmean").
+func distance(r1, g1, b1, r2, g2, b2 uint8) int {
+	rMean := (int(r1) + int(r2)) / 2
+	dr, dg, db := int(r1)-int(r2), int(g1)-int(g2), int(b1)-int(b2)
+	return ((512+rMean)*dr*dr)>>8 + 4*dg*dg + ((767-rMean)*db*db)>>8
+}
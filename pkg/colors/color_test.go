@@ -0,0 +1,140 @@
+package colors
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		spec     string
+		expected Color
+	}{
+		{"#ff8800", RGB(255, 136, 0)},
+		{"#F80", RGB(255, 136, 0)},
+		{"rgb(10, 20, 30)", RGB(10, 20, 30)},
+		{"RGB(0,0,0)", RGB(0, 0, 0)},
+		{"208", ANSI256(208)},
+		{"red", ANSI(1)},
+		{"Red", ANSI(1)},
+		{"bright-blue", ANSI(12)},
+		{"light_blue", ANSI(12)},
+		{"gray", ANSI(8)},
+		{Red, ANSI(1)},
+		{White, ANSI(15)},
+		{LightBlue, ANSI(12)},
+		{"\033[38;5;196m", ANSI256(196)},
+		{"\033[38;2;1;2;3m", RGB(1, 2, 3)},
+		{" \033[31m\n", ANSI(1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := Parse(tt.spec)
+			if err != nil {
+				t.Fatalf("Parse(%q) returned error: %v", tt.spec, err)
+			}
+			if got != tt.expected {
+				t.Errorf("Parse(%q) = %v, expected %v", tt.spec, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	specs := []string{
+		"", "#12", "#gggggg", "rgb(1,2)", "rgb(1,2,300)", "256", "-1",
+		"notacolor", "\033[1m", "\033[41m", "\033[31",
+	}
+
+	for _, spec := range specs {
+		if _, err := Parse(spec); !errors.Is(err, ErrInvalidColor) {
+			t.Errorf("Parse(%q) should fail with ErrInvalidColor, got %v", spec, err)
+		}
+	}
+}
+
+func TestMustParsePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("MustParse should panic on an invalid specification")
+		}
+	}()
+	MustParse("nope")
+}
+
+func TestSequence(t *testing.T) {
+	tests := []struct {
+		name     string
+		color    Color
+		profile  Profile
+		expected string
+	}{
+		{"ANSI red", ANSI(1), ProfileANSI, Red},
+		{"ANSI bright", ANSI(12), ProfileTrueColor, LightBlue},
+		{"256 in 256", ANSI256(208), ProfileANSI256, "\033[38;5;208m"},
+		{"RGB in truecolor", RGB(255, 136, 0), ProfileTrueColor, "\033[38;2;255;136;0m"},
+		{"RGB in auto", RGB(1, 2, 3), ProfileAuto, "\033[38;2;1;2;3m"},
+		{"RGB to 256", RGB(255, 0, 0), ProfileANSI256, "\033[38;5;196m"},
+		{"RGB gray to 256", RGB(128, 128, 128), ProfileANSI256, "\033[38;5;244m"},
+		{"RGB to 16", RGB(250, 10, 10), ProfileANSI, "\033[91m"},
+		{"256 to 16", ANSI256(21), ProfileANSI, Blue},
+		{"256 low index to 16", ANSI256(3), ProfileANSI, Yellow},
+		{"none profile", RGB(255, 0, 0), ProfileNone, ""},
+		{"zero color", Color{}, ProfileTrueColor, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.color.Sequence(tt.profile); got != tt.expected {
+				t.Errorf("Sequence(%s) = %q, expected %q", tt.profile, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBackgroundSequence(t *testing.T) {
+	if got := ANSI(1).BackgroundSequence(ProfileANSI); got != "\033[41m" {
+		t.Errorf("Expected red background, got %q", got)
+	}
+	if got := ANSI(9).BackgroundSequence(ProfileANSI); got != "\033[101m" {
+		t.Errorf("Expected bright red background, got %q", got)
+	}
+	if got := RGB(1, 2, 3).BackgroundSequence(ProfileTrueColor); got != "\033[48;2;1;2;3m" {
+		t.Errorf("Expected truecolor background, got %q", got)
+	}
+	if got := ANSI256(42).BackgroundSequence(ProfileANSI256); got != "\033[48;5;42m" {
+		t.Errorf("Expected 256-color background, got %q", got)
+	}
+}
+
+func TestColorStringRoundTrip(t *testing.T) {
+	for _, c := range []Color{ANSI(3), ANSI(11), ANSI256(100), RGB(1, 2, 3)} {
+		parsed, err := Parse(c.String())
+		if err != nil {
+			t.Fatalf("Parse(%q) returned error: %v", c.String(), err)
+		}
+		if parsed != c {
+			t.Errorf("Round trip of %q produced %v", c.String(), parsed)
+		}
+	}
+	if !(Color{}).IsZero() || RGB(0, 0, 0).IsZero() {
+		t.Error("IsZero should only be true for the zero Color")
+	}
+}
+
+func TestTableRejectsInvalidColors(t *testing.T) {
+	table := NewTable()
+	err := table.Set(map[string]string{"success": "#00ff00", "error": "bogus"})
+	if !errors.Is(err, ErrInvalidColor) {
+		t.Fatalf("Expected ErrInvalidColor, got %v", err)
+	}
+	if _, exists := table.Lookup("success"); exists {
+		t.Error("A rejected table should not be partially applied")
+	}
+
+	table.SetColor("info", RGB(0, 0, 255))
+	if got, _ := table.Get("info"); got != "\033[38;2;0;0;255m" {
+		t.Errorf("Expected Get to render at full fidelity, got %q", got)
+	}
+}
@@ -1,5 +1,10 @@
 package colors
 
+import (
+	"fmt"
+	"sync"
+)
+
 const (
 	Red       = "\033[31m"
 	Green     = "\033[32m"
@@ -13,21 +18,122 @@ const (
 	Bold      = "\033[1m"
 	Italic    = "\033[3m"
 	Reset     = "\033[0m"
+
+	Dim           = "\033[2m"
+	Underline     = "\033[4m"
+	Reverse       = "\033[7m"
+	Strikethrough = "\033[9m"
 )
 
-var userColors = map[string]string{}
+// Table holds user-defined color overrides keyed by message type name. It
+// is safe for concurrent use.
+type Table struct {
+	mu     sync.RWMutex
+	colors map[string]Color
+}
+
+// NewTable returns an empty color table.
+func NewTable() *Table {
+	return &Table{colors: map[string]Color{}}
+}
 
-func GetUserColor(key string) (string, bool) {
-	color, exists := userColors[key]
+// Get returns the escape sequence registered for key, if any, at full
+// fidelity. Use Lookup to render it for a specific profile.
+func (t *Table) Get(key string) (string, bool) {
+	color, exists := t.Lookup(key)
+	return color.Sequence(ProfileTrueColor), exists
+}
+
+// Lookup returns the color registered for key, if any.
+func (t *Table) Lookup(key string) (Color, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	color, exists := t.colors[key]
 	return color, exists
 }
 
-func SetColorTable(newColors map[string]string) {
+// Set parses newColors and merges them into the table, replacing existing
+// keys. If any specification is invalid nothing is changed and an error
+// wrapping ErrInvalidColor is returned.
+func (t *Table) Set(newColors map[string]string) error {
+	parsed := make(map[string]Color, len(newColors))
 	for k, v := range newColors {
-		userColors[k] = v
+		color, err := Parse(v)
+		if err != nil {
+			return fmt.Errorf("color for %q: %w", k, err)
+		}
+		parsed[k] = color
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for k, v := range parsed {
+		t.colors[k] = v
 	}
+	return nil
+}
+
+// SetColor registers an already parsed color for key.
+func (t *Table) SetColor(key string, color Color) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.colors[key] = color
+}
+
+// Clear removes every override from the table.
+func (t *Table) Clear() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.colors = make(map[string]Color)
+}
+
+var userColors = NewTable()
+
+// DefaultTable returns the package-level color table used by SetColorTable.
+func DefaultTable() *Table {
+	return userColors
+}
+
+func GetUserColor(key string) (string, bool) {
+	return userColors.Get(key)
+}
+
+// SetColorTable merges newColors into the default table. See Table.Set.
+func SetColorTable(newColors map[string]string) error {
+	return userColors.Set(newColors)
 }
 
 func ClearUserColors() {
-	userColors = make(map[string]string)
+	userColors.Clear()
+}
+
+// Profile describes how many colors an output can render.
+type Profile int
+
+const (
+	// ProfileAuto detects the profile from the output and environment.
+	ProfileAuto Profile = iota
+	// ProfileNone disables all escape sequences.
+	ProfileNone
+	// ProfileANSI renders the 16 basic ANSI colors.
+	ProfileANSI
+	// ProfileANSI256 renders the 256-color palette.
+	ProfileANSI256
+	// ProfileTrueColor renders 24-bit colors.
+	ProfileTrueColor
+)
+
+// String returns the profile name.
+func (p Profile) String() string {
+	switch p {
+	case ProfileNone:
+		return "none"
+	case ProfileANSI:
+		return "ansi"
+	case ProfileANSI256:
+		return "ansi256"
+	case ProfileTrueColor:
+		return "truecolor"
+	default:
+		return "auto"
+	}
 }
@@ -1,6 +1,7 @@
 package colors
 
 import (
+	"sync"
 	"testing"
 )
 
@@ -12,7 +13,9 @@ func TestSetColorTable(t *testing.T) {
 		"error":   "\033[96m", // Cyan
 	}
 
-	SetColorTable(customColors)
+	if err := SetColorTable(customColors); err != nil {
+		t.Fatalf("SetColorTable failed: %v", err)
+	}
 
 	color, exists := GetUserColor("success")
 	if !exists {
@@ -41,7 +44,9 @@ func TestGetUserColorNotExists(t *testing.T) {
 }
 
 func TestClearUserColors(t *testing.T) {
-	SetColorTable(map[string]string{"test": "value"})
+	if err := SetColorTable(map[string]string{"test": "red"}); err != nil {
+		t.Fatalf("SetColorTable failed: %v", err)
+	}
 	ClearUserColors()
 
 	_, exists := GetUserColor("test")
@@ -49,3 +54,61 @@ func TestClearUserColors(t *testing.T) {
 		t.Error("Expected user colors to be cleared")
 	}
 }
+
+func TestTableIsIndependentFromDefault(t *testing.T) {
+	ClearUserColors()
+	table := NewTable()
+	if err := table.Set(map[string]string{"success": "\033[95m"}); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	if _, exists := GetUserColor("success"); exists {
+		t.Error("Expected instance table not to affect the default table")
+	}
+	if color, _ := table.Get("success"); color != "\033[95m" {
+		t.Errorf("Expected table color %q, got %q", "\033[95m", color)
+	}
+
+	table.Clear()
+	if _, exists := table.Get("success"); exists {
+		t.Error("Expected table to be cleared")
+	}
+}
+
+func TestProfileString(t *testing.T) {
+	expected := map[Profile]string{
+		ProfileAuto:      "auto",
+		ProfileNone:      "none",
+		ProfileANSI:      "ansi",
+		ProfileANSI256:   "ansi256",
+		ProfileTrueColor: "truecolor",
+	}
+	for profile, name := range expected {
+		if profile.String() != name {
+			t.Errorf("Expected profile name %q, got %q", name, profile.String())
+		}
+	}
+}
+
+func TestTableConcurrentUse(t *testing.T) {
+	table := NewTable()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_ = table.Set(map[string]string{"success": "#00ff00", "error": "red"})
+				table.Clear()
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_, _ = table.Lookup("success")
+				_, _ = table.Get("error")
+			}
+		}()
+	}
+	wg.Wait()
+}
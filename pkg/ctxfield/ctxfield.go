@@ -0,0 +1,168 @@
+package ctxfield
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/jsas4coding/utify/pkg/field"
+)
+
+// Keys of the fields written by the built-in extractors.
+const (
+	KeyRequestID = "request_id"
+	KeyTraceID   = "trace_id"
+	KeySpanID    = "span_id"
+)
+
+// Names of the built-in extractors.
+const (
+	ExtractorFields    = "fields"
+	ExtractorRequestID = "request_id"
+	ExtractorTrace     = "trace"
+)
+
+// Extractor returns the fields a context contributes to messages logged
+// with it, e.g. the IDs of the request being served.
+type Extractor func(ctx context.Context) []field.Field
+
+type namedExtractor struct {
+	name    string
+	extract Extractor
+}
+
+var (
+	mu         sync.RWMutex
+	extractors = defaultExtractors()
+)
+
+func defaultExtractors() []namedExtractor {
+	return []namedExtractor{
+		{ExtractorFields, contextFields},
+		{ExtractorRequestID, requestIDField},
+		{ExtractorTrace, traceFields},
+	}
+}
+
+// Register adds an extractor under name, replacing an extractor with the
+// same name in place. Extractors run in registration order, after the
+// built-in ones.
+func Register(name string, e Extractor) {
+	mu.Lock()
+	defer mu.Unlock()
+	// Extract iterates without the lock, so the list is copied on write.
+	list := slices.Clone(extractors)
+	for i, ne := range list {
+		if ne.name == name {
+			list[i].extract = e
+			extractors = list
+			return
+		}
+	}
+	extractors = append(list, namedExtractor{name, e})
+}
+
+// Unregister removes the extractor registered under name, including the
+// built-in ones, and reports whether it existed.
+func Unregister(name string) bool {
+	mu.Lock()
+	defer mu.Unlock()
+	for i, ne := range extractors {
+		if ne.name == name {
+			extractors = slices.Delete(slices.Clone(extractors), i, i+1)
+			return true
+		}
+	}
+	return false
+}
+
+// Reset restores the built-in extractors.
+func Reset() {
+	mu.Lock()
+	defer mu.Unlock()
+	extractors = defaultExtractors()
+}
+
+// Extract runs every extractor on ctx and returns their fields. A nil ctx
+// has no fields.
+func Extract(ctx context.Context) []field.Field {
+	if ctx == nil {
+		return nil
+	}
+	mu.RLock()
+	list := extractors
+	mu.RUnlock()
+
+	var fields []field.Field
+	for _, ne := range list {
+		fields = append(fields, ne.extract(ctx)...)
+	}
+	return fields
+}
+
+type fieldsKey struct{}
+type requestIDKey struct{}
+type traceKey struct{}
+
+type trace struct {
+	traceID, spanID string
+}
+
+// With returns a context carrying fields in addition to those of its
+// parent. They are logged with every message logged with the context. A nil
+// ctx is treated as context.Background().
+func With(ctx context.Context, fields ...field.Field) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	parent, _ := ctx.Value(fieldsKey{}).([]field.Field)
+	return context.WithValue(ctx, fieldsKey{}, append(slices.Clip(parent), fields...))
+}
+
+// WithRequestID returns a context carrying the ID of the request being
+// served.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID stored in ctx, if any.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// WithTrace returns a context carrying a trace and span ID, e.g. from the
+// traceparent header of an incoming request.
+func WithTrace(ctx context.Context, traceID, spanID string) context.Context {
+	return context.WithValue(ctx, traceKey{}, trace{traceID, spanID})
+}
+
+// Trace returns the trace and span ID stored in ctx, if any.
+func Trace(ctx context.Context) (traceID, spanID string) {
+	t, _ := ctx.Value(traceKey{}).(trace)
+	return t.traceID, t.spanID
+}
+
+func contextFields(ctx context.Context) []field.Field {
+	fields, _ := ctx.Value(fieldsKey{}).([]field.Field)
+	return fields
+}
+
+func requestIDField(ctx context.Context) []field.Field {
+	if id := RequestID(ctx); id != "" {
+		return []field.Field{field.F(KeyRequestID, id)}
+	}
+	return nil
+}
+
+func traceFields(ctx context.Context) []field.Field {
+	traceID, spanID := Trace(ctx)
+	var fields []field.Field
+	if traceID != "" {
+		fields = append(fields, field.F(KeyTraceID, traceID))
+	}
+	if spanID != "" {
+		fields = append(fields, field.F(KeySpanID, spanID))
+	}
+	return fields
+}
@@ -0,0 +1,121 @@
+package ctxfield
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/jsas4coding/utify/pkg/field"
+)
+
+func keys(fields []field.Field) []string {
+	var k []string
+	for _, f := range fields {
+		k = append(k, f.Key)
+	}
+	return k
+}
+
+func TestExtractBuiltins(t *testing.T) {
+	ctx := context.Background()
+	if fields := Extract(ctx); fields != nil {
+		t.Errorf("Expected no fields from an empty context, got %v", fields)
+	}
+	var nilCtx context.Context
+	if fields := Extract(nilCtx); fields != nil {
+		t.Errorf("Expected no fields from a nil context, got %v", fields)
+	}
+
+	ctx = With(ctx, field.F("user", "ana"))
+	ctx = WithRequestID(ctx, "req-1")
+	ctx = WithTrace(ctx, "trace-1", "span-1")
+	ctx = With(ctx, field.F("tenant", "acme"))
+
+	expected := []string{"user", "tenant", KeyRequestID, KeyTraceID, KeySpanID}
+	if got := keys(Extract(ctx)); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected keys %v, got %v", expected, got)
+	}
+	if RequestID(ctx) != "req-1" {
+		t.Errorf("Expected request ID req-1, got %q", RequestID(ctx))
+	}
+	if traceID, spanID := Trace(ctx); traceID != "trace-1" || spanID != "span-1" {
+		t.Errorf("Expected trace-1/span-1, got %s/%s", traceID, spanID)
+	}
+}
+
+func TestWithDoesNotShareParentFields(t *testing.T) {
+	parent := With(context.Background(), field.F("a", 1))
+	left := With(parent, field.F("b", 2))
+	right := With(parent, field.F("c", 3))
+
+	if got := keys(Extract(left)); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("Expected [a b], got %v", got)
+	}
+	if got := keys(Extract(right)); !reflect.DeepEqual(got, []string{"a", "c"}) {
+		t.Errorf("Expected [a c], got %v", got)
+	}
+}
+
+func TestWithNilContext(t *testing.T) {
+	var parent context.Context
+	ctx := With(parent, field.F("a", 1))
+	if got := keys(Extract(ctx)); !reflect.DeepEqual(got, []string{"a"}) {
+		t.Errorf("Expected [a], got %v", got)
+	}
+}
+
+type tenantKey struct{}
+
+func TestRegister(t *testing.T) {
+	defer Reset()
+
+	Register("tenant", func(ctx context.Context) []field.Field {
+		if v, ok := ctx.Value(tenantKey{}).(string); ok {
+			return []field.Field{field.F("tenant", v)}
+		}
+		return nil
+	})
+	ctx := context.WithValue(WithRequestID(context.Background(), "r"), tenantKey{}, "acme")
+	if got := keys(Extract(ctx)); !reflect.DeepEqual(got, []string{KeyRequestID, "tenant"}) {
+		t.Errorf("Expected [request_id tenant], got %v", got)
+	}
+
+	// Replacing a built-in keeps its position.
+	Register(ExtractorRequestID, func(ctx context.Context) []field.Field {
+		return []field.Field{field.F("req", RequestID(ctx))}
+	})
+	if got := keys(Extract(ctx)); !reflect.DeepEqual(got, []string{"req", "tenant"}) {
+		t.Errorf("Expected [req tenant], got %v", got)
+	}
+
+	if !Unregister("tenant") || Unregister("tenant") {
+		t.Error("Expected Unregister to report whether the extractor existed")
+	}
+	Reset()
+	if got := keys(Extract(ctx)); !reflect.DeepEqual(got, []string{KeyRequestID}) {
+		t.Errorf("Expected the built-ins after Reset, got %v", got)
+	}
+}
+
+func TestConcurrentRegisterAndExtract(t *testing.T) {
+	defer Reset()
+	ctx := WithRequestID(context.Background(), "r")
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			Register("extra", func(context.Context) []field.Field { return nil })
+			Unregister("extra")
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			_ = Extract(ctx)
+		}
+	}()
+	wg.Wait()
+}
@@ -0,0 +1,41 @@
+// Package duration formats durations for display with a precision that
+// depends on their magnitude, e.g. milliseconds for a step that took 1.2s
+// and seconds for one that took 3m5s.
+package duration
+
+import "time"
+
+// Step rounds durations whose magnitude is at least From to Precision.
+type Step struct {
+	From      time.Duration
+	Precision time.Duration
+}
+
+// Steps used across utify. Steps are ordered from the longest From.
+var (
+	// Readable rounds to seconds from a minute, to milliseconds from a
+	// second and to microseconds from a millisecond, and keeps shorter
+	// durations as they are. It is used for field values.
+	Readable = []Step{
+		{time.Minute, time.Second},
+		{time.Second, time.Millisecond},
+		{time.Millisecond, time.Microsecond},
+	}
+
+	// Seconds rounds every duration to seconds, for times that are redrawn
+	// often and should not flicker.
+	Seconds = []Step{{0, time.Second}}
+)
+
+// Format formats d like time.Duration.String, rounded to the precision of
+// the first step whose From the magnitude of d reaches. d is not rounded if
+// it is shorter than every From.
+func Format(d time.Duration, steps []Step) string {
+	abs := max(d, -d)
+	for _, s := range steps {
+		if abs >= s.From {
+			return d.Round(s.Precision).String()
+		}
+	}
+	return d.String()
+}
@@ -0,0 +1,29 @@
+package duration
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		d        time.Duration
+		steps    []Step
+		expected string
+	}{
+		{800 * time.Nanosecond, Readable, "800ns"},
+		{1234567 * time.Nanosecond, Readable, "1.235ms"},
+		{1540 * time.Millisecond, Readable, "1.54s"},
+		{185400 * time.Millisecond, Readable, "3m5s"},
+		{-90400 * time.Millisecond, Readable, "-1m30s"},
+		{0, Seconds, "0s"},
+		{1540 * time.Millisecond, Seconds, "2s"},
+		{80 * time.Minute, Seconds, "1h20m0s"},
+		{1540 * time.Millisecond, nil, "1.54s"},
+	}
+	for _, tt := range tests {
+		if s := Format(tt.d, tt.steps); s != tt.expected {
+			t.Errorf("Format(%v): expected %q, got %q", tt.d, tt.expected, s)
+		}
+	}
+}
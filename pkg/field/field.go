@@ -0,0 +1,140 @@
+package field
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/jsas4coding/utify/pkg/duration"
+)
+
+// Field is a typed key-value pair attached to a message.
+type Field struct {
+	Key   string
+	Value any
+}
+
+// F returns a Field.
+func F(key string, value any) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Bytes is a byte count rendered in human-readable units on the console and
+// as a plain number in logs.
+type Bytes int64
+
+var byteUnits = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+
+// String formats b with binary units, e.g. "1.5 MiB".
+func (b Bytes) String() string {
+	n := float64(b)
+	sign := ""
+	if n < 0 {
+		sign, n = "-", -n
+	}
+	unit := 0
+	for n >= 1024 && unit < len(byteUnits)-1 {
+		n /= 1024
+		unit++
+	}
+	if unit == 0 {
+		return fmt.Sprintf("%s%d B", sign, int64(n))
+	}
+	return sign + strconv.FormatFloat(n, 'f', 1, 64) + " " + byteUnits[unit]
+}
+
+// Format returns the console representation of a field value.
+func Format(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "<nil>"
+	case string:
+		return v
+	case error:
+		return v.Error()
+	case time.Duration:
+		return duration.Format(v, duration.Readable)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// JSONValue returns the value a field is logged as. Durations and errors
+// become strings, byte counts numbers and times RFC 3339 strings; values
+// that cannot be marshaled are logged using Format.
+func JSONValue(v any) any {
+	switch v := v.(type) {
+	case nil, string, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return v
+	case Bytes:
+		return int64(v)
+	case error:
+		return v.Error()
+	case time.Duration:
+		return v.String()
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case json.Marshaler:
+		return v
+	case fmt.Stringer:
+		return v.String()
+	}
+	if _, err := json.Marshal(v); err != nil {
+		return Format(v)
+	}
+	return v
+}
+
+// String renders the fields as space-separated key=value pairs, quoting
+// values that contain spaces, quotes or equals signs.
+func String(fields []Field) string {
+	var b strings.Builder
+	for i, f := range fields {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(f.Key)
+		b.WriteByte('=')
+		b.WriteString(Quote(Format(f.Value)))
+	}
+	return b.String()
+}
+
+// Quote quotes s if it is empty or contains spaces, quotes, equals signs or
+// control characters.
+func Quote(s string) string {
+	if s == "" || strings.ContainsAny(s, " =\"") || strings.ContainsFunc(s, unicode.IsControl) {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// Split separates Field values from format arguments.
+func Split(args []any) ([]any, []Field) {
+	if !slices.ContainsFunc(args, isField) {
+		return args, nil
+	}
+	var fields []Field
+	rest := make([]any, 0, len(args))
+	for _, arg := range args {
+		if f, ok := arg.(Field); ok {
+			fields = append(fields, f)
+			continue
+		}
+		rest = append(rest, arg)
+	}
+	return rest, fields
+}
+
+func isField(arg any) bool {
+	_, ok := arg.(Field)
+	return ok
+}
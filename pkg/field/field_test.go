@@ -0,0 +1,112 @@
+package field
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFormat(t *testing.T) {
+	ts := time.Date(2024, 5, 1, 14, 3, 9, 0, time.UTC)
+	tests := []struct {
+		name     string
+		value    any
+		expected string
+	}{
+		{"string", "api", "api"},
+		{"int", 42, "42"},
+		{"bool", true, "true"},
+		{"nil", nil, "<nil>"},
+		{"error", errors.New("connection refused"), "connection refused"},
+		{"seconds", 1234567891 * time.Nanosecond, "1.235s"},
+		{"minutes", 90*time.Second + 400*time.Millisecond, "1m30s"},
+		{"millis", 1500 * time.Microsecond, "1.5ms"},
+		{"nanos", 42 * time.Nanosecond, "42ns"},
+		{"time", ts, "2024-05-01T14:03:09Z"},
+		{"bytes", Bytes(512), "512 B"},
+		{"kibibytes", Bytes(1536), "1.5 KiB"},
+		{"mebibytes", Bytes(5 * 1024 * 1024), "5.0 MiB"},
+		{"negative bytes", Bytes(-2048), "-2.0 KiB"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Format(tt.value); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestJSONValue(t *testing.T) {
+	ts := time.Date(2024, 5, 1, 14, 3, 9, 500, time.UTC)
+	values := map[string]any{
+		"bytes":    Bytes(2048),
+		"duration": 1500 * time.Millisecond,
+		"error":    errors.New("boom"),
+		"time":     ts,
+		"int":      7,
+		"map":      map[string]int{"a": 1},
+		"func":     func() {},
+	}
+
+	converted := make(map[string]any, len(values))
+	for k, v := range values {
+		converted[k] = JSONValue(v)
+	}
+	data, err := json.Marshal(converted)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	expected := map[string]any{
+		"bytes":    float64(2048),
+		"duration": "1.5s",
+		"error":    "boom",
+		"time":     "2024-05-01T14:03:09.0000005Z",
+		"int":      float64(7),
+	}
+	for k, v := range expected {
+		if decoded[k] != v {
+			t.Errorf("Expected %s to be %v, got %v", k, v, decoded[k])
+		}
+	}
+	if m, ok := decoded["map"].(map[string]any); !ok || m["a"] != float64(1) {
+		t.Errorf("Expected map to be kept as an object, got %v", decoded["map"])
+	}
+	if _, ok := decoded["func"].(string); !ok {
+		t.Errorf("Expected unmarshalable values to be formatted, got %v", decoded["func"])
+	}
+}
+
+func TestString(t *testing.T) {
+	fields := []Field{F("service", "api"), F("note", "two words"), F("empty", ""), F("took", 2*time.Second)}
+	expected := `service=api note="two words" empty="" took=2s`
+	if got := String(fields); got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	args := []any{"a", F("k", 1), 2, F("j", 2)}
+	rest, fields := Split(args)
+	if len(rest) != 2 || rest[0] != "a" || rest[1] != 2 {
+		t.Errorf("Unexpected args %v", rest)
+	}
+	if len(fields) != 2 || fields[0].Key != "k" || fields[1].Key != "j" {
+		t.Errorf("Unexpected fields %v", fields)
+	}
+	if args[1] != F("k", 1) {
+		t.Error("Split should not modify its input")
+	}
+
+	plain := []any{1, 2}
+	if rest, fields := Split(plain); len(rest) != 2 || fields != nil {
+		t.Errorf("Expected args without fields to be returned unchanged, got %v %v", rest, fields)
+	}
+}
@@ -1,83 +1,643 @@
 package formatter
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"sync"
+	"time"
 
+	"github.com/jsas4coding/utify/pkg/caller"
 	"github.com/jsas4coding/utify/pkg/colors"
+	"github.com/jsas4coding/utify/pkg/ctxfield"
+	"github.com/jsas4coding/utify/pkg/field"
 	"github.com/jsas4coding/utify/pkg/icons"
+	"github.com/jsas4coding/utify/pkg/layout"
 	"github.com/jsas4coding/utify/pkg/logger"
 	"github.com/jsas4coding/utify/pkg/messages"
 	"github.com/jsas4coding/utify/pkg/options"
+	"github.com/jsas4coding/utify/pkg/redact"
+	"github.com/jsas4coding/utify/pkg/terminal"
+	"github.com/jsas4coding/utify/pkg/theme"
 )
 
 var ErrSilent = errors.New("silent error")
 
-func Echo(msgType messages.Type, text string, opts *options.Options) (string, error) {
-	// Build formatted message
-	message := buildFormattedMessage(msgType, text, opts)
+// Formatter renders messages and writes them to its outputs. Nil fields fall
+// back to the package-level defaults (os.Stdout, os.Stderr, the default
+// routing table, severity thresholds, layouts, caller capture, theme, color
+// table, icon set, logger and redactor), so the zero value behaves like
+// Echo. A zero Profile uses the package-level profile, which by default
+// detects color support separately for each output. Scope is shown by
+// layouts with a scope token unless a call sets its own.
+type Formatter struct {
+	Output      io.Writer
+	ErrorOutput io.Writer
+	Profile     colors.Profile
+	Routing     options.Routing
+	Levels      *options.Levels
+	Layouts     layout.Layouts
+	Callers     *options.Callers
+	// ContextOnConsole shows the fields of EchoContext's context on the
+	// console as well as in the log. Nil uses the package-level setting.
+	ContextOnConsole *bool
+	Scope            string
+	Theme            *theme.Theme
+	Colors           *colors.Table
+	Icons            *icons.Set
+	Logger           *logger.Logger
+	// Redactor masks secrets in console output. Log entries are masked by
+	// the logger's redactor.
+	Redactor *redact.Redactor
+}
+
+var std = &Formatter{}
+
+// The package-level defaults are guarded by mu, so they can be changed
+// while other goroutines print. Console writes are serialized by writeMu so
+// that concurrent lines never interleave.
+var (
+	mu           sync.RWMutex
+	routing      = options.DefaultRouting()
+	levels       = options.DefaultLevels()
+	layouts      = layout.Layouts{}
+	callers      options.Callers
+	ctxOnConsole bool
+	profile      colors.Profile
+	currentTheme *theme.Theme
+	start        = time.Now()
+
+	writeMu sync.Mutex
+)
+
+func init() {
+	levels = levelsFromLogger(levels, logger.Default())
+}
+
+// levelsFromLogger returns l with the log threshold of lg, which applies
+// logger.EnvLogLevel, if it has one.
+func levelsFromLogger(l options.Levels, lg *logger.Logger) options.Levels {
+	if level, ok := lg.GetLevel(); ok {
+		l.Log = level
+	}
+	return l
+}
+
+// Default returns the package-level Formatter used by Echo.
+func Default() *Formatter {
+	return std
+}
+
+// SetRouting replaces the package-level routing table used by formatters
+// without their own table.
+func SetRouting(r options.Routing) {
+	r = r.Clone()
+	mu.Lock()
+	defer mu.Unlock()
+	routing = r
+}
+
+// GetRouting returns a copy of the package-level routing table.
+func GetRouting() options.Routing {
+	mu.RLock()
+	defer mu.RUnlock()
+	return routing.Clone()
+}
+
+// SetLevels sets the package-level severity thresholds used by formatters
+// without their own.
+func SetLevels(l options.Levels) {
+	mu.Lock()
+	defer mu.Unlock()
+	levels = l
+}
+
+// GetLevels returns the package-level severity thresholds.
+func GetLevels() options.Levels {
+	mu.RLock()
+	defer mu.RUnlock()
+	return levels
+}
+
+// SetConsoleLevel sets the package-level console threshold.
+func SetConsoleLevel(s messages.Severity) {
+	mu.Lock()
+	defer mu.Unlock()
+	levels.Console = s
+}
+
+// SetLogLevel sets the package-level log threshold.
+func SetLogLevel(s messages.Severity) {
+	mu.Lock()
+	defer mu.Unlock()
+	levels.Log = s
+}
+
+// SetLayouts replaces the package-level layout table used by formatters
+// without their own.
+func SetLayouts(l layout.Layouts) {
+	l = l.Clone()
+	mu.Lock()
+	defer mu.Unlock()
+	layouts = l
+}
+
+// SetLayout sets the package-level layout of msgType; messages.Default sets
+// the layout of types without their own.
+func SetLayout(msgType messages.Type, l *layout.Layout) {
+	mu.Lock()
+	defer mu.Unlock()
+	layouts[msgType] = l
+}
+
+// GetLayouts returns a copy of the package-level layout table.
+func GetLayouts() layout.Layouts {
+	mu.RLock()
+	defer mu.RUnlock()
+	return layouts.Clone()
+}
+
+// SetCallers sets the package-level caller capture used by formatters
+// without their own.
+func SetCallers(c options.Callers) {
+	c = c.Clone()
+	mu.Lock()
+	defer mu.Unlock()
+	callers = c
+}
+
+// GetCallers returns the package-level caller capture.
+func GetCallers() options.Callers {
+	mu.RLock()
+	defer mu.RUnlock()
+	return callers.Clone()
+}
+
+// SetContextOnConsole sets whether formatters without their own setting
+// show context fields on the console. They are always logged.
+func SetContextOnConsole(show bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	ctxOnConsole = show
+}
+
+// GetContextOnConsole reports whether context fields are shown on the
+// console by default.
+func GetContextOnConsole() bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return ctxOnConsole
+}
+
+// SetProfile sets the package-level color profile used by formatters with
+// a zero Profile. colors.ProfileAuto restores detection per output.
+func SetProfile(p colors.Profile) {
+	mu.Lock()
+	defer mu.Unlock()
+	profile = p
+}
+
+// GetProfile returns the package-level color profile.
+func GetProfile() colors.Profile {
+	mu.RLock()
+	defer mu.RUnlock()
+	return profile
+}
+
+func Echo(msgType messages.Type, text string, opts *options.Options, fields ...field.Field) (string, error) {
+	return std.Echo(msgType, text, opts, fields...)
+}
+
+// SetTheme sets the package-level theme used by formatters without their
+// own theme. A nil theme restores the built-in look.
+func SetTheme(t *theme.Theme) {
+	mu.Lock()
+	defer mu.Unlock()
+	currentTheme = t
+}
+
+// GetTheme returns the package-level theme, or nil if none is set.
+func GetTheme() *theme.Theme {
+	mu.RLock()
+	defer mu.RUnlock()
+	return currentTheme
+}
+
+// Echo formats and prints a message with its fields, logs it and runs the
+// callback or exit behaviour requested by opts. Messages below the console
+// or log threshold are not printed or logged; callbacks and exits are not
+// affected.
+func (f *Formatter) Echo(msgType messages.Type, text string, opts *options.Options,
+	fields ...field.Field) (string, error) {
+	return f.echo(time.Time{}, msgType, text, opts, fields, nil)
+}
+
+// EchoContext is like Echo but adds the fields extracted from ctx, such as
+// request and trace IDs. They are written to the log, and shown on the
+// console if ContextOnConsole is set.
+func (f *Formatter) EchoContext(ctx context.Context, msgType messages.Type, text string, opts *options.Options,
+	fields ...field.Field) (string, error) {
+	return f.echo(time.Time{}, msgType, text, opts, fields, ctxfield.Extract(ctx))
+}
+
+// EchoContextAt is like EchoContext for a message created at t, e.g. a
+// record of another logging library. A zero t is the current time.
+func (f *Formatter) EchoContextAt(ctx context.Context, t time.Time, msgType messages.Type, text string,
+	opts *options.Options, fields ...field.Field) (string, error) {
+	return f.echo(t, msgType, text, opts, fields, ctxfield.Extract(ctx))
+}
+
+// echo implements Echo for a message created at t, or now if t is zero.
+// ctxFields precede fields in the log, and on the console if context fields
+// are shown there.
+func (f *Formatter) echo(t time.Time, msgType messages.Type, text string, opts *options.Options,
+	fields, ctxFields []field.Field) (string, error) {
+	if t.IsZero() {
+		t = time.Now()
+	}
+	r := f.redactor()
+	text = r.String(text)
+	logFields, consoleFields := fields, fields
+	if len(ctxFields) > 0 {
+		logFields = append(ctxFields, fields...)
+		if f.contextOnConsole() {
+			consoleFields = logFields
+		}
+	}
+
+	route := f.route(msgType, opts)
+	thresholds := f.levels()
+	if !thresholds.PrintsToConsole(msgType) {
+		route &^= options.RouteStdout | options.RouteStderr
+	}
+	if !thresholds.WritesToLog(msgType) {
+		route &^= options.RouteLog
+	}
+
+	// Capture the caller only if it is shown or logged
+	var c *caller.Caller
+	cfg := f.callers()
+	inConsole := (opts.Caller || cfg.InConsole(msgType)) &&
+		(route.Has(options.RouteStdout) || route.Has(options.RouteStderr))
+	inLog := (opts.Caller || cfg.Log) && route.Has(options.RouteLog)
+	if inConsole || inLog {
+		c = caller.Capture(cfg.Skip + opts.CallerSkip)
+	}
+	var consoleCaller string
+	if inConsole && c != nil {
+		consoleCaller = c.String()
+	}
+	if route.Has(options.RouteStdout) || route.Has(options.RouteStderr) {
+		consoleFields = r.Fields(consoleFields)
+	}
 
 	// Output message and log
-	fmt.Println(message)
-	logger.LogMessage(msgType, text)
+	if route.Has(options.RouteStdout) {
+		f.printAt(f.output(), t, msgType, text, opts, consoleFields, consoleCaller)
+	}
+	if route.Has(options.RouteStderr) {
+		f.printAt(f.errorOutput(), t, msgType, text, opts, consoleFields, consoleCaller)
+	}
+	if route.Has(options.RouteLog) {
+		entry := logger.LogEntry{Time: t, Type: msgType, Message: text, Fields: logFields}
+		if inLog {
+			entry.Caller = c
+		}
+		f.logger().WriteEntry(entry)
+	}
 
 	// Handle callback or exit
-	handleCallbackOrExit(msgType, text, opts)
+	f.handleCallbackOrExit(msgType, text, opts, route)
 
 	// Return appropriate result
 	return handleReturnValue(msgType, text)
 }
 
-// buildFormattedMessage constructs the formatted message string
-func buildFormattedMessage(msgType messages.Type, text string, opts *options.Options) string {
-	color := getColorForMessage(msgType, opts)
-	style := getStyleForMessage(opts)
-	icon := getIconForMessage(msgType, opts)
+// Enabled reports whether a message of msgType meets the console or the log
+// threshold.
+func (f *Formatter) Enabled(msgType messages.Type) bool {
+	thresholds := f.levels()
+	return thresholds.PrintsToConsole(msgType) || thresholds.WritesToLog(msgType)
+}
+
+// ConsoleOutput returns the writer Echo prints msgType to, or nil if the
+// message is not routed to the console or is below the console threshold.
+// Stdout wins when a message is routed to both.
+func (f *Formatter) ConsoleOutput(msgType messages.Type, opts *options.Options) io.Writer {
+	if !f.levels().PrintsToConsole(msgType) {
+		return nil
+	}
+	route := f.route(msgType, opts)
+	switch {
+	case route.Has(options.RouteStdout):
+		return f.output()
+	case route.Has(options.RouteStderr):
+		return f.errorOutput()
+	}
+	return nil
+}
+
+// Log writes a message to the formatter's logger without printing it, if
+// it meets the log threshold.
+func (f *Formatter) Log(msgType messages.Type, text string, fields ...field.Field) {
+	if !f.levels().WritesToLog(msgType) {
+		return
+	}
+	entry := logger.LogEntry{Type: msgType, Message: text, Fields: fields}
+	if cfg := f.callers(); cfg.Log {
+		entry.Caller = caller.Capture(cfg.Skip)
+	}
+	f.logger().WriteEntry(entry)
+}
+
+// Replay prints a logged entry to the console the way it was printed, with
+// its own time and caller, e.g. entries read back with logger.NewReader.
+// Routing and the console threshold apply; the entry is not logged again
+// and triggers no callbacks or exit.
+func (f *Formatter) Replay(entry logger.LogEntry, opts *options.Options) {
+	if !f.levels().PrintsToConsole(entry.Type) {
+		return
+	}
+	t := entry.Time
+	if t.IsZero() {
+		t = time.Now()
+	}
+	var callerText string
+	if entry.Caller != nil {
+		callerText = entry.Caller.String()
+	}
+	r := f.redactor()
+	text, fields := r.String(entry.Message), r.Fields(entry.Fields)
+	route := f.route(entry.Type, opts)
+	if route.Has(options.RouteStdout) {
+		f.printAt(f.output(), t, entry.Type, text, opts, fields, callerText)
+	}
+	if route.Has(options.RouteStderr) {
+		f.printAt(f.errorOutput(), t, entry.Type, text, opts, fields, callerText)
+	}
+}
+
+// printAt formats a message written at t for the color profile of w and
+// writes it.
+func (f *Formatter) printAt(w io.Writer, t time.Time, msgType messages.Type, text string, opts *options.Options,
+	fields []field.Field, callerText string) {
+	message := f.buildFormattedMessage(t, msgType, text, fields, callerText, opts, f.profileFor(w, opts))
+	writeMu.Lock()
+	defer writeMu.Unlock()
+	_, _ = fmt.Fprintln(w, message)
+}
+
+// profileFor returns the color profile used for w. Options.ForceColor wins
+// over both detection and an explicit ProfileNone.
+func (f *Formatter) profileFor(w io.Writer, opts *options.Options) colors.Profile {
+	profile := f.Profile
+	if profile == colors.ProfileAuto {
+		profile = GetProfile()
+	}
+	if profile == colors.ProfileAuto {
+		profile = terminal.ColorProfile(w)
+	}
+	if opts.ForceColor && profile == colors.ProfileNone {
+		profile = max(terminal.EnvProfile(), colors.ProfileANSI)
+	}
+	return profile
+}
+
+// route returns the destinations for msgType, preferring the per-call route.
+func (f *Formatter) route(msgType messages.Type, opts *options.Options) options.Route {
+	if opts.Route != 0 {
+		return opts.Route
+	}
+	if f.Routing != nil {
+		return f.Routing.Lookup(msgType)
+	}
+	mu.RLock()
+	defer mu.RUnlock()
+	return routing.Lookup(msgType)
+}
+
+func (f *Formatter) levels() options.Levels {
+	if f.Levels != nil {
+		return *f.Levels
+	}
+	return GetLevels()
+}
+
+// callers returns the caller capture of the formatter. The package-level
+// configuration is returned without a copy; it is replaced, never modified.
+func (f *Formatter) callers() options.Callers {
+	if f.Callers != nil {
+		return *f.Callers
+	}
+	mu.RLock()
+	defer mu.RUnlock()
+	return callers
+}
+
+func (f *Formatter) redactor() *redact.Redactor {
+	if f.Redactor != nil {
+		return f.Redactor
+	}
+	return redact.Default()
+}
+
+func (f *Formatter) contextOnConsole() bool {
+	if f.ContextOnConsole != nil {
+		return *f.ContextOnConsole
+	}
+	return GetContextOnConsole()
+}
+
+func (f *Formatter) layout(msgType messages.Type) *layout.Layout {
+	if f.Layouts != nil {
+		return f.Layouts.Lookup(msgType)
+	}
+	mu.RLock()
+	defer mu.RUnlock()
+	return layouts.Lookup(msgType)
+}
+
+func (f *Formatter) scope(opts *options.Options) string {
+	if opts.Scope != "" {
+		return opts.Scope
+	}
+	return f.Scope
+}
+
+func (f *Formatter) output() io.Writer {
+	if f.Output != nil {
+		return f.Output
+	}
+	return os.Stdout
+}
+
+func (f *Formatter) errorOutput() io.Writer {
+	if f.ErrorOutput != nil {
+		return f.ErrorOutput
+	}
+	return os.Stderr
+}
 
-	return fmt.Sprintf("%s%s%s%s%s", style, color, icon, text, colors.Reset)
+func (f *Formatter) colorTable() *colors.Table {
+	if f.Colors != nil {
+		return f.Colors
+	}
+	return colors.DefaultTable()
 }
 
-// getColorForMessage returns the appropriate color based on options
-func getColorForMessage(msgType messages.Type, opts *options.Options) string {
+func (f *Formatter) iconSet() *icons.Set {
+	if f.Icons != nil {
+		return f.Icons
+	}
+	return icons.DefaultSet()
+}
+
+func (f *Formatter) theme() *theme.Theme {
+	if f.Theme != nil {
+		return f.Theme
+	}
+	return GetTheme()
+}
+
+// themeStyle returns the theme style for msgType, or the zero Style. A
+// registered type the theme does not list keeps its own color.
+func (f *Formatter) themeStyle(msgType messages.Type) (theme.Style, bool) {
+	t := f.theme()
+	if t == nil {
+		return theme.Style{}, false
+	}
+	style, _ := t.Style(msgType)
+	if _, listed := t.Styles[msgType]; !listed && !messages.IsBuiltin(msgType) {
+		if def, exists := messages.Lookup(msgType); exists && !def.Color.IsZero() {
+			style.Color = def.Color
+		}
+	}
+	return style, t.Monochrome
+}
+
+func (f *Formatter) logger() *logger.Logger {
+	if f.Logger != nil {
+		return f.Logger
+	}
+	return logger.Default()
+}
+
+// buildFormattedMessage renders the message with the layout for its type.
+// Outputs without color support get no escape sequences.
+func (f *Formatter) buildFormattedMessage(t time.Time, msgType messages.Type, text string, fields []field.Field,
+	callerText string, opts *options.Options, profile colors.Profile) string {
+	themeStyle, monochrome := f.themeStyle(msgType)
+	line := layout.Line{
+		Time:    t,
+		Elapsed: t.Sub(start),
+		Type:    msgType,
+		Label:   getLabelForMessage(msgType, themeStyle),
+		Scope:   f.scope(opts),
+		Icon:    f.getIconForMessage(msgType, opts, themeStyle),
+		Message: text,
+		Fields:  fields,
+		Caller:  callerText,
+	}
+	line.Style = f.lineStyle(msgType, opts, profile, themeStyle, monochrome)
+	return f.layout(msgType).Render(line, profile)
+}
+
+// Decoration returns the icon and the escape sequences Echo uses for msgType
+// on w, so that other renderers such as progress bars match its messages.
+// The sequences are empty when w has no color support.
+func (f *Formatter) Decoration(w io.Writer, msgType messages.Type, opts *options.Options) (icon, style string) {
+	themeStyle, monochrome := f.themeStyle(msgType)
+	icon = f.getIconForMessage(msgType, opts, themeStyle)
+	return icon, f.lineStyle(msgType, opts, f.profileFor(w, opts), themeStyle, monochrome)
+}
+
+// lineStyle returns the attributes and colors of a line for the profile.
+func (f *Formatter) lineStyle(msgType messages.Type, opts *options.Options, profile colors.Profile,
+	themeStyle theme.Style, monochrome bool) string {
+	if profile == colors.ProfileNone {
+		return ""
+	}
+	style := getStyleForMessage(opts, themeStyle)
+	if !monochrome {
+		style += f.getColorForMessage(msgType, opts, profile, themeStyle)
+	}
+	return style
+}
+
+// getColorForMessage returns the foreground and background sequences for the
+// profile. Color table overrides win over the theme, which wins over the
+// message type's default color.
+func (f *Formatter) getColorForMessage(msgType messages.Type, opts *options.Options, profile colors.Profile,
+	themeStyle theme.Style) string {
 	if opts.NoColor {
 		return ""
 	}
-	return messages.GetColor(msgType)
+	background := themeStyle.Background.BackgroundSequence(profile)
+	if color, exists := f.colorTable().Lookup(string(msgType)); exists {
+		return color.Sequence(profile) + background
+	}
+	if !themeStyle.Color.IsZero() {
+		return themeStyle.Color.Sequence(profile) + background
+	}
+	return messages.ColorFrom(f.colorTable(), msgType).Sequence(profile) + background
 }
 
-// getStyleForMessage returns the appropriate style based on options
-func getStyleForMessage(opts *options.Options) string {
+// getStyleForMessage returns the text attributes from options and theme
+func getStyleForMessage(opts *options.Options, themeStyle theme.Style) string {
 	if opts.NoStyle {
 		return ""
 	}
-	style := ""
-	if opts.Bold {
+	style := themeStyle.Attributes()
+	if opts.Bold && !themeStyle.Bold {
 		style += colors.Bold
 	}
-	if opts.Italic {
+	if opts.Italic && !themeStyle.Italic {
 		style += colors.Italic
 	}
 	return style
 }
 
-// getIconForMessage returns the appropriate icon based on options
-func getIconForMessage(msgType messages.Type, opts *options.Options) string {
+// getIconForMessage returns the appropriate icon based on options, preferring
+// the theme's icon for the active icon type
+func (f *Formatter) getIconForMessage(msgType messages.Type, opts *options.Options, themeStyle theme.Style) string {
 	if !opts.ShowIcons || opts.NoIcon {
 		return ""
 	}
-	icon := icons.GetIcon(msgType)
-	if icon != "" {
-		icon += " " // Add space after icon
+	set := f.iconSet()
+	icon := set.Icon(msgType)
+	switch {
+	case set.Type() == icons.NerdFontIcons && themeStyle.NerdIcon != "":
+		icon = themeStyle.NerdIcon
+	case set.Type() != icons.NoIcons && themeStyle.Icon != "":
+		icon = themeStyle.Icon
 	}
 	return icon
 }
 
-// handleCallbackOrExit handles callback execution or program exit
-func handleCallbackOrExit(msgType messages.Type, text string, opts *options.Options) {
+// getLabelForMessage returns the theme label, or the label of the type's
+// definition
+func getLabelForMessage(msgType messages.Type, themeStyle theme.Style) string {
+	if themeStyle.Label != "" {
+		return themeStyle.Label
+	}
+	def, _ := messages.Lookup(msgType)
+	return def.Label
+}
+
+// handleCallbackOrExit handles callback execution or program exit. The
+// logger is closed before exiting so that queued entries are not lost.
+func (f *Formatter) handleCallbackOrExit(msgType messages.Type, text string, opts *options.Options, route options.Route) {
 	if opts.Callback != nil {
-		opts.Callback(msgType, text)
+		if route.Has(options.RouteCallback) {
+			opts.Callback(msgType, text)
+		}
 	} else if opts.Exit && messages.IsErrorType(msgType) {
+		f.logger().Close()
+		if l := logger.Default(); l != f.logger() {
+			l.Close()
+		}
 		os.Exit(1)
 	}
 }
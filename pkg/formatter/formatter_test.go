@@ -1,16 +1,49 @@
 package formatter
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	testutil "github.com/jsas4coding/utify/internal/tests"
+	"github.com/jsas4coding/utify/pkg/caller"
 	"github.com/jsas4coding/utify/pkg/colors"
+	"github.com/jsas4coding/utify/pkg/ctxfield"
+	"github.com/jsas4coding/utify/pkg/field"
+	"github.com/jsas4coding/utify/pkg/icons"
+	"github.com/jsas4coding/utify/pkg/layout"
+	"github.com/jsas4coding/utify/pkg/logger"
 	"github.com/jsas4coding/utify/pkg/messages"
 	"github.com/jsas4coding/utify/pkg/options"
+	"github.com/jsas4coding/utify/pkg/redact"
+	"github.com/jsas4coding/utify/pkg/theme"
 )
 
+// TestMain keeps the default log file, and those of subprocesses, in a
+// temporary state directory instead of the user's.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "utify-state")
+	if err != nil {
+		panic(err)
+	}
+	_ = os.Setenv("XDG_STATE_HOME", dir)
+	_ = logger.Default().SetLogTarget(filepath.Join(dir, "test.log"))
+	code := m.Run()
+	_ = os.RemoveAll(dir)
+	os.Exit(code)
+}
+
 func TestEcho(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -18,22 +51,30 @@ func TestEcho(t *testing.T) {
 		text        string
 		opts        *options.Options
 		shouldError bool
+		toStderr    bool
+		hidden      bool
 	}{
-		{"Success", messages.Success, "Operation completed", options.Default(), false},
-		{"Error", messages.Error, "An error occurred", options.Default(), true},
-		{"Warning", messages.Warning, "This is a warning", options.Default(), false},
-		{"Info", messages.Info, "Just info", options.Default(), false},
-		{"Debug", messages.Debug, "Debugging", options.Default(), true},
-		{"Critical", messages.Critical, "Critical!", options.Default(), true},
+		{"Success", messages.Success, "Operation completed", options.Default(), false, false, false},
+		{"Error", messages.Error, "An error occurred", options.Default(), true, true, false},
+		{"Warning", messages.Warning, "This is a warning", options.Default(), false, true, false},
+		{"Info", messages.Info, "Just info", options.Default(), false, false, false},
+		{"Debug", messages.Debug, "Debugging", options.Default(), false, false, true},
+		{"Critical", messages.Critical, "Critical!", options.Default(), true, true, false},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			output := testutil.CaptureOutput(func() {
+			capture := testutil.CaptureOutput
+			if tt.toStderr {
+				capture = testutil.CaptureStderr
+			}
+			output := capture(func() {
 				_, _ = Echo(tt.msgType, tt.text, tt.opts)
 			})
 
-			if !strings.Contains(output, tt.text) {
+			if tt.hidden && output != "" {
+				t.Errorf("Expected %s to be below the default console level, got %q", tt.name, output)
+			} else if !tt.hidden && !strings.Contains(output, tt.text) {
 				t.Errorf("Expected output to contain %q, got %q", tt.text, output)
 			}
 
@@ -48,7 +89,7 @@ func TestEcho(t *testing.T) {
 }
 
 func TestEchoBold(t *testing.T) {
-	opts := options.Default().WithBold()
+	opts := options.Default().WithBold().WithColor()
 
 	output := testutil.CaptureOutput(func() {
 		_, _ = Echo(messages.Success, "Bold text", opts)
@@ -60,7 +101,7 @@ func TestEchoBold(t *testing.T) {
 }
 
 func TestEchoItalic(t *testing.T) {
-	opts := options.Default().WithItalic()
+	opts := options.Default().WithItalic().WithColor()
 
 	output := testutil.CaptureOutput(func() {
 		_, _ = Echo(messages.Success, "Italic text", opts)
@@ -128,3 +169,771 @@ func TestEchoLogsToFile(t *testing.T) {
 		t.Errorf("Expected output to contain %q, got %q", "Log test", output)
 	}
 }
+
+func TestFormatterOutput(t *testing.T) {
+	var out, logOut bytes.Buffer
+	table := colors.NewTable()
+	if err := table.Set(map[string]string{string(messages.Info): "\033[95m"}); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	f := &Formatter{
+		Output:  &out,
+		Profile: colors.ProfileANSI,
+		Colors:  table,
+		Icons:   icons.NewSet(icons.NoIcons),
+		Logger:  logger.NewWriter(&logOut),
+	}
+
+	captured := testutil.CaptureOutput(func() {
+		_, _ = f.Echo(messages.Info, "Instance text", options.Default().WithIcon())
+	})
+
+	if captured != "" {
+		t.Errorf("Expected nothing on stdout, got %q", captured)
+	}
+	if !strings.Contains(out.String(), "\033[95mInstance text") {
+		t.Errorf("Expected formatter to use its own color table, got %q", out.String())
+	}
+	if !strings.Contains(logOut.String(), "Instance text") {
+		t.Errorf("Expected formatter to log to its own logger, got %q", logOut.String())
+	}
+
+	f.Log(messages.Warning, "Log only text")
+	if strings.Contains(out.String(), "Log only text") {
+		t.Error("Log should not print to output")
+	}
+	if !strings.Contains(logOut.String(), "Log only text") {
+		t.Error("Log should write to the logger")
+	}
+}
+
+func TestEchoRouting(t *testing.T) {
+	var out, errOut, logOut bytes.Buffer
+	var called int
+
+	f := &Formatter{
+		Output:      &out,
+		ErrorOutput: &errOut,
+		Routing: options.Routing{
+			messages.Info:  options.RouteStderr,
+			messages.Debug: options.RouteLog | options.RouteCallback,
+		},
+		Logger: logger.NewWriter(&logOut),
+	}
+	opts := options.Default().WithCallback(func(messages.Type, string) { called++ })
+
+	_, _ = f.Echo(messages.Info, "info to stderr", opts)
+	_, _ = f.Echo(messages.Debug, "debug to log", opts)
+	_, _ = f.Echo(messages.Success, "success default", opts)
+
+	if !strings.Contains(errOut.String(), "info to stderr") || strings.Contains(out.String(), "info to stderr") {
+		t.Errorf("Expected info on stderr only, got stdout %q, stderr %q", out.String(), errOut.String())
+	}
+	if strings.Contains(logOut.String(), "info to stderr") {
+		t.Error("Expected info not to be logged")
+	}
+	if strings.Contains(out.String()+errOut.String(), "debug to log") || !strings.Contains(logOut.String(), "debug to log") {
+		t.Errorf("Expected debug in the log only, got log %q", logOut.String())
+	}
+	if !strings.Contains(out.String(), "success default") || !strings.Contains(logOut.String(), "success default") {
+		t.Error("Expected unrouted types to use the default route")
+	}
+	if called != 2 {
+		t.Errorf("Expected callback to run for 2 routed messages, got %d", called)
+	}
+}
+
+func TestEchoRouteOverride(t *testing.T) {
+	var out, errOut, logOut bytes.Buffer
+	f := &Formatter{Output: &out, ErrorOutput: &errOut, Logger: logger.NewWriter(&logOut)}
+
+	_, _ = f.Echo(messages.Error, "override", options.Default().WithRoute(options.RouteStdout))
+
+	if !strings.Contains(out.String(), "override") {
+		t.Errorf("Expected per-call route to print to stdout, got %q", out.String())
+	}
+	if errOut.Len() != 0 || logOut.Len() != 0 {
+		t.Errorf("Expected no stderr or log output, got stderr %q, log %q", errOut.String(), logOut.String())
+	}
+}
+
+func TestSetRouting(t *testing.T) {
+	original := GetRouting()
+	defer SetRouting(original)
+
+	SetRouting(options.Routing{messages.Success: options.RouteStderr})
+
+	output := testutil.CaptureStderr(func() {
+		_, _ = Echo(messages.Success, "Routed success", options.Default())
+	})
+	if !strings.Contains(output, "Routed success") {
+		t.Errorf("Expected success on stderr, got %q", output)
+	}
+
+	routes := GetRouting()
+	routes[messages.Success] = options.RouteStdout
+	if GetRouting().Lookup(messages.Success) != options.RouteStderr {
+		t.Error("GetRouting should return a copy")
+	}
+}
+
+func TestEchoColorDetection(t *testing.T) {
+	t.Setenv("FORCE_COLOR", "")
+	t.Setenv("CLICOLOR_FORCE", "")
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("GITHUB_ACTIONS", "")
+	t.Setenv("GITLAB_CI", "")
+
+	var out bytes.Buffer
+	f := &Formatter{Output: &out, Logger: logger.NewWriter(nil)}
+
+	_, _ = f.Echo(messages.Success, "Piped", options.Default().WithBold())
+	if strings.Contains(out.String(), "\033[") {
+		t.Errorf("Expected no escape sequences for non-terminal output, got %q", out.String())
+	}
+
+	out.Reset()
+	_, _ = f.Echo(messages.Success, "Forced", options.Default().WithBold().WithColor())
+	if !strings.Contains(out.String(), colors.Bold+colors.Green+"Forced") {
+		t.Errorf("Expected WithColor to override detection, got %q", out.String())
+	}
+
+	out.Reset()
+	f.Profile = colors.ProfileTrueColor
+	_, _ = f.Echo(messages.Success, "No color", options.Default().WithoutColor())
+	if strings.Contains(out.String(), colors.Green) {
+		t.Errorf("Expected WithoutColor to override the profile, got %q", out.String())
+	}
+}
+
+func TestEchoDownsamplesColors(t *testing.T) {
+	table := colors.NewTable()
+	if err := table.Set(map[string]string{string(messages.Info): "#ff0000"}); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	tests := []struct {
+		profile  colors.Profile
+		expected string
+	}{
+		{colors.ProfileTrueColor, "\033[38;2;255;0;0mHex"},
+		{colors.ProfileANSI256, "\033[38;5;196mHex"},
+		{colors.ProfileANSI, "\033[91mHex"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.profile.String(), func(t *testing.T) {
+			var out bytes.Buffer
+			f := &Formatter{Output: &out, Profile: tt.profile, Colors: table, Logger: logger.NewWriter(nil)}
+
+			_, _ = f.Echo(messages.Info, "Hex", options.Default())
+			if !strings.Contains(out.String(), tt.expected) {
+				t.Errorf("Expected %q in output, got %q", tt.expected, out.String())
+			}
+		})
+	}
+}
+
+func TestEchoTheme(t *testing.T) {
+	th := &theme.Theme{Name: "test", Styles: map[messages.Type]theme.Style{
+		messages.Success: {
+			Color:      colors.RGB(0, 255, 0),
+			Background: colors.ANSI(0),
+			Underline:  true,
+			Icon:       "+",
+			Label:      "OK",
+		},
+	}}
+
+	var out bytes.Buffer
+	f := &Formatter{Output: &out, Profile: colors.ProfileTrueColor, Theme: th,
+		Icons: icons.NewSet(icons.RegularIcons), Logger: logger.NewWriter(nil)}
+
+	_, _ = f.Echo(messages.Success, "Themed", options.Default().WithIcon())
+	expected := colors.Underline + "\033[38;2;0;255;0m\033[40m+ OK Themed" + colors.Reset
+	if !strings.Contains(out.String(), expected) {
+		t.Errorf("Expected %q, got %q", expected, out.String())
+	}
+
+	// Types without a theme style keep the built-in look
+	out.Reset()
+	_, _ = f.Echo(messages.Info, "Plain", options.Default().WithIcon())
+	if !strings.Contains(out.String(), colors.Cyan+f.Icons.Icon(messages.Info)+" Plain") {
+		t.Errorf("Expected default info color, got %q", out.String())
+	}
+
+	// Color table overrides win over the theme
+	table := colors.NewTable()
+	if err := table.Set(map[string]string{string(messages.Success): "red"}); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	f.Colors = table
+	out.Reset()
+	_, _ = f.Echo(messages.Success, "Override", options.Default())
+	if !strings.Contains(out.String(), colors.Red+"\033[40m") {
+		t.Errorf("Expected color table to win over theme, got %q", out.String())
+	}
+
+	// Plain outputs keep the icon and label
+	out.Reset()
+	f.Profile = colors.ProfileNone
+	_, _ = f.Echo(messages.Success, "Plain", options.Default().WithIcon())
+	if out.String() != "+ OK Plain\n" {
+		t.Errorf("Expected plain themed output, got %q", out.String())
+	}
+}
+
+func TestEchoMonochromeTheme(t *testing.T) {
+	th, err := theme.Builtin(theme.Monochrome)
+	if err != nil {
+		t.Fatalf("Builtin failed: %v", err)
+	}
+
+	var out bytes.Buffer
+	f := &Formatter{Output: &out, Profile: colors.ProfileTrueColor, Theme: th, Logger: logger.NewWriter(nil)}
+
+	_, _ = f.Echo(messages.Info, "Mono", options.Default())
+	if strings.Contains(out.String(), "\033[3") || strings.Contains(out.String(), "\033[9") {
+		t.Errorf("Expected no colors from a monochrome theme, got %q", out.String())
+	}
+	style, _ := th.Style(messages.Info)
+	if style.Label == "" || !strings.Contains(out.String(), style.Label+" Mono") {
+		t.Errorf("Expected monochrome label, got %q", out.String())
+	}
+}
+
+func TestEchoThemeNerdIcon(t *testing.T) {
+	th := &theme.Theme{Styles: map[messages.Type]theme.Style{
+		messages.Info: {Icon: "i", NerdIcon: "N"},
+	}}
+
+	var out bytes.Buffer
+	f := &Formatter{Output: &out, Profile: colors.ProfileNone, Theme: th, Logger: logger.NewWriter(nil)}
+
+	f.Icons = icons.NewSet(icons.NerdFontIcons)
+	_, _ = f.Echo(messages.Info, "msg", options.Default().WithIcon())
+	f.Icons = icons.NewSet(icons.RegularIcons)
+	_, _ = f.Echo(messages.Info, "msg", options.Default().WithIcon())
+	f.Icons = icons.NewSet(icons.NoIcons)
+	_, _ = f.Echo(messages.Info, "msg", options.Default().WithIcon())
+
+	if out.String() != "N msg\ni msg\nmsg\n" {
+		t.Errorf("Unexpected themed icons: %q", out.String())
+	}
+}
+
+func TestSetTheme(t *testing.T) {
+	defer SetTheme(nil)
+
+	th, _ := theme.Builtin(theme.Dracula)
+	SetTheme(th)
+	if GetTheme() != th {
+		t.Error("GetTheme should return the theme set with SetTheme")
+	}
+
+	var out bytes.Buffer
+	f := &Formatter{Output: &out, Profile: colors.ProfileTrueColor, Logger: logger.NewWriter(nil)}
+	_, _ = f.Echo(messages.Info, "Global", options.Default())
+
+	style, _ := th.Style(messages.Info)
+	if !strings.Contains(out.String(), style.Color.Sequence(colors.ProfileTrueColor)) {
+		t.Errorf("Expected package theme to apply, got %q", out.String())
+	}
+}
+
+func TestEchoRegisteredType(t *testing.T) {
+	const rollback messages.Type = "rollback"
+	defer messages.Unregister(rollback)
+
+	err := messages.Register(rollback, messages.Definition{
+		Color:   colors.RGB(255, 135, 0),
+		Icon:    "↩",
+		Label:   "ROLLBACK",
+		IsError: true,
+	})
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	var out, logOut bytes.Buffer
+	f := &Formatter{Output: &out, Profile: colors.ProfileTrueColor, Icons: icons.NewSet(icons.RegularIcons),
+		Logger: logger.NewWriter(&logOut)}
+
+	text, err := f.Echo(rollback, "Reverted", options.Default().WithIcon())
+	if text != "Reverted" || !errors.Is(err, ErrSilent) {
+		t.Errorf("Expected error type result, got %q, %v", text, err)
+	}
+	expected := "\033[38;2;255;135;0m↩ ROLLBACK Reverted" + colors.Reset
+	if !strings.Contains(out.String(), expected) {
+		t.Errorf("Expected %q, got %q", expected, out.String())
+	}
+	if !strings.Contains(logOut.String(), `"type":"rollback"`) {
+		t.Errorf("Expected registered type in log, got %q", logOut.String())
+	}
+
+	// Themes that do not list the type keep its own color
+	out.Reset()
+	f.Theme, _ = theme.Builtin(theme.Dracula)
+	_, _ = f.Echo(rollback, "Themed", options.Default())
+	if !strings.Contains(out.String(), "\033[38;2;255;135;0m") {
+		t.Errorf("Expected registered color under a theme, got %q", out.String())
+	}
+
+	// ...and themes that do list it win
+	out.Reset()
+	f.Theme = &theme.Theme{Styles: map[messages.Type]theme.Style{rollback: {Color: colors.ANSI(4), Label: "RB"}}}
+	_, _ = f.Echo(rollback, "Listed", options.Default())
+	if !strings.Contains(out.String(), colors.Blue+"RB Listed") {
+		t.Errorf("Expected theme style for listed type, got %q", out.String())
+	}
+}
+
+func TestEchoLevels(t *testing.T) {
+	var out, logOut bytes.Buffer
+	f := &Formatter{Output: &out, ErrorOutput: &out, Logger: logger.NewWriter(&logOut)}
+
+	var called int
+	opts := options.Default().WithCallback(func(messages.Type, string) { called++ })
+
+	f.Levels = &options.Levels{Console: messages.SeverityWarn, Log: messages.SeverityError}
+	_, _ = f.Echo(messages.Info, "info", opts)
+	_, _ = f.Echo(messages.Warning, "warning", opts)
+	_, _ = f.Echo(messages.Error, "error", opts)
+	f.Log(messages.Warning, "log-only warning")
+	f.Log(messages.Critical, "log-only critical")
+
+	if strings.Contains(out.String(), "info") || !strings.Contains(out.String(), "warning") ||
+		!strings.Contains(out.String(), "error") {
+		t.Errorf("Expected warnings and above on the console, got %q", out.String())
+	}
+	if strings.Contains(logOut.String(), "warning") || !strings.Contains(logOut.String(), `"message":"error"`) ||
+		!strings.Contains(logOut.String(), "log-only critical") {
+		t.Errorf("Expected errors and above in the log, got %q", logOut.String())
+	}
+	if called != 3 {
+		t.Errorf("Expected callbacks to ignore thresholds, got %d calls", called)
+	}
+}
+
+func TestSetLevels(t *testing.T) {
+	defer SetLevels(options.DefaultLevels())
+
+	var out, logOut bytes.Buffer
+	f := &Formatter{Output: &out, Logger: logger.NewWriter(&logOut)}
+
+	_, _ = f.Echo(messages.Debug, "hidden debug", options.Default())
+	if out.Len() != 0 || !strings.Contains(logOut.String(), "hidden debug") {
+		t.Errorf("Expected debug to be logged but not printed, got %q", out.String())
+	}
+
+	SetLevels(options.VerbosityLevels(1))
+	if GetLevels().Console != messages.SeverityDebug {
+		t.Errorf("Expected debug console level, got %s", GetLevels().Console)
+	}
+	_, _ = f.Echo(messages.Debug, "shown debug", options.Default())
+	if !strings.Contains(out.String(), "shown debug") {
+		t.Errorf("Expected debug to be printed in verbose mode, got %q", out.String())
+	}
+}
+
+func TestEchoLayouts(t *testing.T) {
+	var out bytes.Buffer
+	f := &Formatter{
+		Output:      &out,
+		ErrorOutput: &out,
+		Profile:     colors.ProfileNone,
+		Layouts: layout.Layouts{
+			messages.Default: layout.MustParse("{type}: {message}"),
+			messages.Error:   layout.MustParse("{type} {[scope]} {message}"),
+		},
+		Scope:  "api",
+		Logger: logger.NewWriter(nil),
+	}
+
+	_, _ = f.Echo(messages.Info, "started", options.Default())
+	_, _ = f.Echo(messages.Error, "failed", options.Default())
+	_, _ = f.Echo(messages.Error, "timeout", options.Default().WithScope("db"))
+
+	expected := "INFO: started\nERROR [api] failed\nERROR [db] timeout\n"
+	if out.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, out.String())
+	}
+}
+
+func TestEchoTimestampedLayout(t *testing.T) {
+	var out bytes.Buffer
+	timestamped, _ := layout.Preset(layout.Timestamped)
+	f := &Formatter{Output: &out, Profile: colors.ProfileNone,
+		Layouts: layout.Layouts{messages.Default: timestamped}, Logger: logger.NewWriter(nil)}
+
+	_, _ = f.Echo(messages.Info, "tick", options.Default())
+
+	stamp, rest, _ := strings.Cut(out.String(), " ")
+	if _, err := time.Parse(layout.DefaultTimeFormat, stamp); err != nil || rest != "tick\n" {
+		t.Errorf("Expected a timestamped line, got %q", out.String())
+	}
+}
+
+func TestSetLayouts(t *testing.T) {
+	defer SetLayouts(nil)
+
+	var out bytes.Buffer
+	f := &Formatter{Output: &out, Profile: colors.ProfileNone, Logger: logger.NewWriter(nil)}
+
+	SetLayouts(layout.Layouts{messages.Default: layout.MustParse("> {message}")})
+	_, _ = f.Echo(messages.Info, "global", options.Default())
+	if out.String() != "> global\n" {
+		t.Errorf("Expected package layout to apply, got %q", out.String())
+	}
+
+	layouts := GetLayouts()
+	delete(layouts, messages.Default)
+	if _, exists := GetLayouts()[messages.Default]; !exists {
+		t.Error("GetLayouts should return a copy")
+	}
+}
+
+func TestEchoFields(t *testing.T) {
+	var out, logOut bytes.Buffer
+	f := &Formatter{Output: &out, Profile: colors.ProfileNone, Logger: logger.NewWriter(&logOut)}
+
+	_, _ = f.Echo(messages.Info, "deployed", options.Default(),
+		field.F("service", "api"), field.F("took", 2*time.Second))
+
+	if out.String() != "deployed service=api took=2s\n" {
+		t.Errorf("Unexpected console output %q", out.String())
+	}
+	if !strings.Contains(logOut.String(), `"service":"api","took":"2s"`) {
+		t.Errorf("Expected fields as log properties, got %q", logOut.String())
+	}
+
+	logOut.Reset()
+	f.Log(messages.Info, "log only", field.F("n", 1))
+	if !strings.Contains(logOut.String(), `"n":1`) {
+		t.Errorf("Expected fields in log-only entries, got %q", logOut.String())
+	}
+}
+
+// TestExitFlushesAsyncLog runs itself in a subprocess that exits through
+// WithExit and checks that the queued entries reached the log file.
+func TestExitFlushesAsyncLog(t *testing.T) {
+	if target := os.Getenv("UTIFY_TEST_EXIT_LOG"); target != "" {
+		l, err := logger.New(target)
+		if err != nil {
+			os.Exit(2)
+		}
+		l.EnableAsync(logger.Async{FlushInterval: time.Hour})
+		f := &Formatter{Output: &bytes.Buffer{}, ErrorOutput: &bytes.Buffer{}, Logger: l}
+		for i := 0; i < 10; i++ {
+			_, _ = f.Echo(messages.Info, "queued", options.Default())
+		}
+		_, _ = f.Echo(messages.Error, "fatal", options.Default().WithExit())
+		os.Exit(3)
+	}
+
+	target := filepath.Join(t.TempDir(), "exit.log")
+	cmd := exec.Command(os.Args[0], "-test.run=^TestExitFlushesAsyncLog$")
+	cmd.Env = append(os.Environ(), "UTIFY_TEST_EXIT_LOG="+target)
+	err := cmd.Run()
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+		t.Fatalf("Expected exit code 1, got %v", err)
+	}
+	data, err := os.ReadFile(target)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if got := strings.Count(string(data), "\n"); got != 11 {
+		t.Errorf("Expected 11 entries before exit, got %d", got)
+	}
+}
+
+// slowWriter writes one byte at a time, so unsynchronized concurrent writes
+// interleave.
+type slowWriter struct {
+	buf []byte
+}
+
+func (w *slowWriter) Write(p []byte) (int, error) {
+	for _, b := range p {
+		w.buf = append(w.buf, b)
+		runtime.Gosched()
+	}
+	return len(p), nil
+}
+
+func TestConcurrentLinesDoNotInterleave(t *testing.T) {
+	w := &slowWriter{}
+	f := &Formatter{Output: w, Profile: colors.ProfileNone, Logger: logger.NewWriter(nil)}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				_, _ = f.Echo(messages.Info, strings.Repeat(string(rune('a'+g)), 20), options.Default())
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	lines := strings.Split(strings.TrimSuffix(string(w.buf), "\n"), "\n")
+	if len(lines) != 160 {
+		t.Fatalf("Expected 160 lines, got %d", len(lines))
+	}
+	for _, line := range lines {
+		text := strings.TrimSpace(line)
+		if len(text) < 20 || strings.Count(text, text[len(text)-1:]) != 20 {
+			t.Fatalf("Interleaved line %q", line)
+		}
+	}
+}
+
+func TestConcurrentEchoAndReconfigure(t *testing.T) {
+	defer SetRouting(options.DefaultRouting())
+	defer SetLevels(options.DefaultLevels())
+	defer SetLayouts(nil)
+	defer SetTheme(nil)
+	defer SetProfile(colors.ProfileAuto)
+
+	var buf bytes.Buffer
+	f := &Formatter{Output: &buf, ErrorOutput: &buf, Logger: logger.NewWriter(nil)}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				_, _ = f.Echo(messages.Warning, "message", options.Default(), field.F("i", i))
+			}
+		}()
+	}
+	for i := 0; i < 50; i++ {
+		SetRouting(options.DefaultRouting())
+		SetLevels(options.VerbosityLevels(i%3 - 1))
+		SetConsoleLevel(messages.SeverityTrace)
+		SetLayout(messages.Default, layout.MustParse("{icon} {message} {fields}"))
+		th, _ := theme.Builtin(theme.Names()[i%len(theme.Names())])
+		SetTheme(th)
+		SetProfile(colors.Profile(i % 5))
+		_ = GetLayouts()
+	}
+	wg.Wait()
+}
+
+func TestCallers(t *testing.T) {
+	var out, logBuf bytes.Buffer
+	verbose := options.VerbosityLevels(1)
+	f := &Formatter{
+		Output:  &out,
+		Profile: colors.ProfileNone,
+		Levels:  &verbose,
+		Callers: &options.Callers{Log: true, Console: []messages.Type{messages.Debug}},
+		Logger:  logger.NewWriter(&logBuf),
+	}
+
+	_, _ = f.Echo(messages.Debug, "debug", options.Default())
+	_, _, line, _ := runtime.Caller(0)
+	_, _ = f.Echo(messages.Info, "info", options.Default())
+
+	location := "formatter/formatter_test.go:" + strconv.Itoa(line-1)
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 || !strings.HasSuffix(lines[0], "debug "+location) {
+		t.Errorf("Expected the caller on the debug line, got %q", out.String())
+	}
+	if strings.Contains(lines[len(lines)-1], "formatter_test.go") {
+		t.Errorf("Expected no caller on the info line, got %q", lines[len(lines)-1])
+	}
+
+	var entry struct {
+		Caller struct {
+			File     string `json:"file"`
+			Line     int    `json:"line"`
+			Function string `json:"function"`
+		} `json:"caller"`
+	}
+	logLines := strings.Split(strings.TrimSpace(logBuf.String()), "\n")
+	if err := json.Unmarshal([]byte(logLines[1]), &entry); err != nil {
+		t.Fatalf("Invalid log entry: %v", err)
+	}
+	if !strings.HasSuffix(entry.Caller.File, "formatter_test.go") || entry.Caller.Line != line+1 ||
+		!strings.HasSuffix(entry.Caller.Function, "TestCallers") {
+		t.Errorf("Unexpected caller in log entry: %+v", entry.Caller)
+	}
+}
+
+func TestCallersOff(t *testing.T) {
+	var out, logBuf bytes.Buffer
+	f := &Formatter{Output: &out, Profile: colors.ProfileNone, Logger: logger.NewWriter(&logBuf)}
+
+	_, _ = f.Echo(messages.Info, "plain", options.Default())
+	if strings.Contains(out.String(), ".go:") || strings.Contains(logBuf.String(), "caller") {
+		t.Errorf("Expected no caller by default, got %q and %q", out.String(), logBuf.String())
+	}
+
+	out.Reset()
+	_, _ = f.Echo(messages.Info, "forced", options.Default().WithCaller())
+	if !strings.Contains(out.String(), "formatter_test.go:") || !strings.Contains(logBuf.String(), `"caller":{`) {
+		t.Errorf("Expected WithCaller to capture the caller, got %q and %q", out.String(), logBuf.String())
+	}
+}
+
+func logVia(f *Formatter, text string) {
+	_, _ = f.Echo(messages.Info, text, options.Default().WithCaller().WithCallerSkip(1))
+}
+
+func TestCallerSkip(t *testing.T) {
+	var out bytes.Buffer
+	f := &Formatter{Output: &out, Profile: colors.ProfileNone, Logger: logger.NewWriter(nil)}
+
+	logVia(f, "wrapped")
+	_, _, line, _ := runtime.Caller(0)
+	if !strings.HasSuffix(strings.TrimSpace(out.String()), "formatter_test.go:"+strconv.Itoa(line-1)) {
+		t.Errorf("Expected the wrapper's caller, got %q", out.String())
+	}
+}
+
+func TestEchoContext(t *testing.T) {
+	var out, logBuf bytes.Buffer
+	f := &Formatter{
+		Output:  &out,
+		Profile: colors.ProfileNone,
+		Logger:  logger.NewWriter(&logBuf),
+	}
+	ctx := ctxfield.WithRequestID(context.Background(), "req-1")
+
+	_, _ = f.EchoContext(ctx, messages.Info, "hidden", options.Default(), field.F("n", 1))
+	show := true
+	f.ContextOnConsole = &show
+	_, _ = f.EchoContext(ctx, messages.Info, "shown", options.Default(), field.F("n", 2))
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 || strings.Contains(lines[0], "req-1") || !strings.Contains(lines[0], "n=1") {
+		t.Errorf("Expected context fields off the console by default, got %q", out.String())
+	}
+	if !strings.Contains(lines[len(lines)-1], "request_id=req-1 n=2") {
+		t.Errorf("Expected context fields on the console, got %q", lines[len(lines)-1])
+	}
+
+	for i, l := range strings.Split(strings.TrimSpace(logBuf.String()), "\n") {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(l), &entry); err != nil {
+			t.Fatalf("Invalid log entry %q: %v", l, err)
+		}
+		if entry["request_id"] != "req-1" || entry["n"] != float64(i+1) {
+			t.Errorf("Expected request_id and n in log entry, got %v", entry)
+		}
+	}
+}
+
+func TestEchoRedactsSecrets(t *testing.T) {
+	var out, logBuf bytes.Buffer
+	f := &Formatter{
+		Output:  &out,
+		Profile: colors.ProfileNone,
+		Logger:  logger.NewWriter(&logBuf),
+	}
+
+	text, _ := f.Echo(messages.Info, "token=abc123 sent", options.Default(),
+		field.F("password", "hunter2"), field.F("key", redact.Secret("s3cr3t")))
+
+	for name, s := range map[string]string{"text": text, "console": out.String(), "log": logBuf.String()} {
+		if strings.Contains(s, "abc123") || strings.Contains(s, "hunter2") || strings.Contains(s, "s3cr3t") {
+			t.Errorf("Expected secrets masked in the %s, got %q", name, s)
+		}
+	}
+	if !strings.Contains(out.String(), "password="+redact.Mask) {
+		t.Errorf("Expected the password field masked, got %q", out.String())
+	}
+
+	off := redact.New()
+	off.SetEnabled(false)
+	out.Reset()
+	f.Redactor = off
+	_, _ = f.Echo(messages.Info, "token=abc123", options.Default())
+	if !strings.Contains(out.String(), "token=abc123") {
+		t.Errorf("Expected no redaction with a disabled redactor, got %q", out.String())
+	}
+}
+
+func TestLevelsFromLogger(t *testing.T) {
+	defaults := options.DefaultLevels()
+	lg := logger.NewWriter(nil)
+
+	if l := levelsFromLogger(defaults, lg); l != defaults {
+		t.Errorf("Expected the levels unchanged without a logger level, got %+v", l)
+	}
+	lg.SetLevel(messages.SeverityWarn)
+	if l := levelsFromLogger(defaults, lg); l.Log != messages.SeverityWarn || l.Console != defaults.Console {
+		t.Errorf("Expected a warn log threshold, got %+v", l)
+	}
+}
+
+func TestReplay(t *testing.T) {
+	var out, errOut, logBuf bytes.Buffer
+	f := &Formatter{
+		Output:      &out,
+		ErrorOutput: &errOut,
+		Profile:     colors.ProfileNone,
+		Layouts:     layout.Layouts{messages.Default: layout.MustParse("{timestamp} {message} {fields} {caller}")},
+		Logger:      logger.NewWriter(&logBuf),
+	}
+	at := time.Date(2024, 5, 1, 10, 30, 15, 0, time.Local)
+	calls := 0
+	opts := options.Default()
+	opts.Callback = func(messages.Type, string) { calls++ }
+
+	f.Replay(logger.LogEntry{Time: at, Type: messages.Info, Message: "replayed",
+		Fields: []field.Field{field.F("n", 1)}, Caller: &caller.Caller{File: "/src/app/main.go", Line: 7}}, opts)
+	f.Replay(logger.LogEntry{Time: at, Type: messages.Error, Message: "failed"}, opts)
+	f.Replay(logger.LogEntry{Time: at, Type: messages.Debug, Message: "hidden"}, opts)
+
+	if out.String() != "10:30:15 replayed n=1 app/main.go:7\n" {
+		t.Errorf("Unexpected replayed line %q", out.String())
+	}
+	if errOut.String() != "10:30:15 failed\n" {
+		t.Errorf("Expected the error routed to stderr, got %q", errOut.String())
+	}
+	if logBuf.Len() > 0 || calls > 0 {
+		t.Errorf("Expected no log entries or callbacks, got %q and %d calls", logBuf.String(), calls)
+	}
+}
+
+func TestDecoration(t *testing.T) {
+	f := &Formatter{Profile: colors.ProfileANSI, Icons: icons.NewSet(icons.RegularIcons)}
+	icon, style := f.Decoration(io.Discard, messages.Success, options.Default().WithIcon().WithBold())
+	if icon != icons.NewSet(icons.RegularIcons).Icon(messages.Success) {
+		t.Errorf("Unexpected icon %q", icon)
+	}
+	if style != colors.Bold+colors.Green {
+		t.Errorf("Expected bold green, got %q", style)
+	}
+
+	f.Profile = colors.ProfileNone
+	if icon, style := f.Decoration(io.Discard, messages.Success, options.Default()); icon != "" || style != "" {
+		t.Errorf("Expected no icon or style, got %q and %q", icon, style)
+	}
+}
+
+func TestConsoleOutput(t *testing.T) {
+	var out, errOut bytes.Buffer
+	f := &Formatter{Output: &out, ErrorOutput: &errOut}
+	opts := options.Default()
+
+	if w := f.ConsoleOutput(messages.Success, opts); w != &out {
+		t.Errorf("Expected stdout for success messages")
+	}
+	if w := f.ConsoleOutput(messages.Error, opts); w != &errOut {
+		t.Errorf("Expected stderr for error messages")
+	}
+	if w := f.ConsoleOutput(messages.Debug, opts); w != nil {
+		t.Errorf("Expected no output below the console threshold")
+	}
+	if w := f.ConsoleOutput(messages.Success, options.Default().WithRoute(options.RouteLog)); w != nil {
+		t.Errorf("Expected no output for messages routed to the log only")
+	}
+}
@@ -3,6 +3,8 @@ package icons
 import (
 	"os"
 	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/jsas4coding/utify/pkg/messages"
 )
@@ -76,8 +78,65 @@ var regularIcons = map[messages.Type]string{
 	messages.Default:    "●",    // bullet
 }
 
-var currentIconType IconType
-var detectedNerdFont bool
+// Set selects the icon set used to render message types. It is safe for
+// concurrent use.
+type Set struct {
+	mu       sync.RWMutex
+	iconType IconType
+}
+
+// NewSet returns an icon set that renders icons of the given type.
+func NewSet(iconType IconType) *Set {
+	return &Set{iconType: iconType}
+}
+
+// Type returns the icon type used by the set.
+func (s *Set) Type() IconType {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.iconType
+}
+
+// SetType changes the icon type used by the set.
+func (s *Set) SetType(iconType IconType) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.iconType = iconType
+}
+
+// Icon returns the icon for msgType in the set's icon type. Registered types
+// use the icons of their definition; a missing Nerd Font icon falls back to
+// the regular one.
+func (s *Set) Icon(msgType messages.Type) string {
+	switch s.Type() {
+	case NerdFontIcons:
+		if icon, exists := nerdFontIcons[msgType]; exists {
+			return icon
+		}
+		if def, exists := messages.Lookup(msgType); exists {
+			if def.NerdIcon != "" {
+				return def.NerdIcon
+			}
+			if def.Icon != "" {
+				return def.Icon
+			}
+		}
+		return nerdFontIcons[messages.Default]
+	case RegularIcons:
+		if icon, exists := regularIcons[msgType]; exists {
+			return icon
+		}
+		if def, exists := messages.Lookup(msgType); exists && def.Icon != "" {
+			return def.Icon
+		}
+		return regularIcons[messages.Default]
+	default:
+		return ""
+	}
+}
+
+var currentSet = &Set{}
+var detectedNerdFont atomic.Bool
 
 func init() {
 	Init()
@@ -85,14 +144,14 @@ func init() {
 
 // Init re-initializes the icon detection logic. This is useful for testing.
 func Init() {
-	detectedNerdFont = detectNerdFont()
+	detectedNerdFont.Store(detectNerdFont())
 	// Check if user explicitly wants Nerd Font icons
 	if os.Getenv("NERD_FONT_ENABLED") == "true" || os.Getenv("NERD_FONT_ENABLED") == "1" {
-		currentIconType = NerdFontIcons
+		currentSet.SetType(NerdFontIcons)
 	} else {
 		// Default to regular icons for safety - users can enable Nerd Fonts manually
 		// This ensures compatibility across different terminal environments
-		currentIconType = RegularIcons
+		currentSet.SetType(RegularIcons)
 	}
 }
 
@@ -166,37 +225,29 @@ func checkTerminalSpecificVariables() bool {
 		os.Getenv("ITERM_SESSION_ID") != ""
 }
 
+// DefaultSet returns the package-level icon set controlled by SetIconType.
+func DefaultSet() *Set {
+	return currentSet
+}
+
 // GetIcon returns the appropriate icon for a message type
 func GetIcon(msgType messages.Type) string {
-	switch currentIconType {
-	case NerdFontIcons:
-		if icon, exists := nerdFontIcons[msgType]; exists {
-			return icon
-		}
-		return nerdFontIcons[messages.Default]
-	case RegularIcons:
-		if icon, exists := regularIcons[msgType]; exists {
-			return icon
-		}
-		return regularIcons[messages.Default]
-	default:
-		return ""
-	}
+	return currentSet.Icon(msgType)
 }
 
 // SetIconType manually sets the icon type
 func SetIconType(iconType IconType) {
-	currentIconType = iconType
+	currentSet.SetType(iconType)
 }
 
 // GetIconType returns the current icon type
 func GetIconType() IconType {
-	return currentIconType
+	return currentSet.Type()
 }
 
 // IsNerdFontDetected returns whether Nerd Font was auto-detected
 func IsNerdFontDetected() bool {
-	return detectedNerdFont
+	return detectedNerdFont.Load()
 }
 
 // ForceNerdFont forces the use of Nerd Font icons
@@ -2,6 +2,7 @@ package icons
 
 import (
 	"os"
+	"sync"
 	"testing"
 
 	"github.com/jsas4coding/utify/pkg/messages"
@@ -130,3 +131,77 @@ func TestNerdFontDetectionEnvVar(t *testing.T) {
 		t.Error("NERD_FONT_ENABLED=false should not force Nerd Font icons")
 	}
 }
+
+func TestIconSetIsIndependentFromDefault(t *testing.T) {
+	original := GetIconType()
+	defer SetIconType(original)
+
+	SetIconType(RegularIcons)
+	set := NewSet(NoIcons)
+	if set.Icon(messages.Success) != "" {
+		t.Error("Expected empty icon for a NoIcons set")
+	}
+
+	set.SetType(NerdFontIcons)
+	if set.Type() != NerdFontIcons {
+		t.Error("Expected set type to be NerdFontIcons")
+	}
+	if GetIconType() != RegularIcons {
+		t.Error("Changing a set should not change the default icon type")
+	}
+	if DefaultSet().Type() != RegularIcons {
+		t.Error("Expected DefaultSet to reflect SetIconType")
+	}
+}
+
+func TestRegisteredTypeIcons(t *testing.T) {
+	const canary messages.Type = "canary"
+	const migrate messages.Type = "migrate"
+	defer messages.Unregister(canary)
+	defer messages.Unregister(migrate)
+
+	if err := messages.Register(canary, messages.Definition{Icon: "🐤", NerdIcon: "N"}); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if err := messages.Register(migrate, messages.Definition{Icon: "⇄"}); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	regular, nerd := NewSet(RegularIcons), NewSet(NerdFontIcons)
+	if icon := regular.Icon(canary); icon != "🐤" {
+		t.Errorf("Expected regular icon, got %q", icon)
+	}
+	if icon := nerd.Icon(canary); icon != "N" {
+		t.Errorf("Expected nerd icon, got %q", icon)
+	}
+	if icon := nerd.Icon(migrate); icon != "⇄" {
+		t.Errorf("Expected regular icon as nerd fallback, got %q", icon)
+	}
+	if icon := NewSet(NoIcons).Icon(canary); icon != "" {
+		t.Errorf("Expected no icon, got %q", icon)
+	}
+	if icon := regular.Icon("unknown"); icon != regular.Icon(messages.Default) {
+		t.Errorf("Expected default icon for unknown types, got %q", icon)
+	}
+}
+
+func TestSetConcurrentUse(t *testing.T) {
+	set := NewSet(RegularIcons)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				set.SetType(IconType(j % 3))
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_ = set.Icon(messages.Success)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,319 @@
+package layout
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/jsas4coding/utify/pkg/colors"
+	"github.com/jsas4coding/utify/pkg/duration"
+	"github.com/jsas4coding/utify/pkg/field"
+	"github.com/jsas4coding/utify/pkg/messages"
+)
+
+var (
+	// ErrInvalidLayout is returned when a layout template cannot be parsed.
+	ErrInvalidLayout = errors.New("invalid layout")
+
+	// ErrUnknownPreset is returned when a preset name does not exist.
+	ErrUnknownPreset = errors.New("unknown layout preset")
+)
+
+// Token names accepted in layout templates.
+const (
+	TokenTimestamp = "timestamp"
+	TokenElapsed   = "elapsed"
+	TokenType      = "type"
+	TokenLabel     = "label"
+	TokenScope     = "scope"
+	TokenIcon      = "icon"
+	TokenMessage   = "message"
+	TokenFields    = "fields"
+	TokenCaller    = "caller"
+)
+
+var tokens = map[string]bool{
+	TokenTimestamp: true, TokenElapsed: true, TokenType: true, TokenLabel: true,
+	TokenScope: true, TokenIcon: true, TokenMessage: true, TokenFields: true, TokenCaller: true,
+}
+
+// DefaultTimeFormat is the time format of the timestamp token.
+const DefaultTimeFormat = "15:04:05"
+
+// Line holds the values a layout is rendered from. Style is the escape
+// sequence of the message type, applied to the whole line; it is ignored
+// for ProfileNone.
+type Line struct {
+	Time    time.Time
+	Elapsed time.Duration
+	Type    messages.Type
+	Label   string
+	Scope   string
+	Icon    string
+	Message string
+	Fields  []field.Field
+	Caller  string
+	Style   string
+}
+
+// Layout is a parsed console line template. Templates mix literal text with
+// tokens such as "{icon} {message}". A token may carry literal affixes that
+// are only printed when the token has a value, and its own style after a
+// colon: "{[scope]:cyan,bold}". Styles are attribute names (bold, dim,
+// italic, underline, reverse, strikethrough) and colors in any form
+// colors.Parse accepts. An empty token also removes the space after it, so
+// optional tokens do not leave gaps. "{{" and "}}" produce literal braces.
+type Layout struct {
+	// TimeFormat is the time.Format layout of the timestamp token.
+	TimeFormat string
+
+	template string
+	segments []segment
+}
+
+type segment struct {
+	literal string
+	token   string
+	prefix  string
+	suffix  string
+	attrs   string
+	color   colors.Color
+}
+
+// Parse parses a layout template.
+func Parse(template string) (*Layout, error) {
+	l := &Layout{TimeFormat: DefaultTimeFormat, template: template}
+
+	var literal strings.Builder
+	for i := 0; i < len(template); i++ {
+		c := template[i]
+		switch {
+		case c == '{' && strings.HasPrefix(template[i:], "{{"):
+			literal.WriteByte('{')
+			i++
+		case c == '}' && strings.HasPrefix(template[i:], "}}"):
+			literal.WriteByte('}')
+			i++
+		case c == '}':
+			return nil, fmt.Errorf("%w: unexpected '}' in %q", ErrInvalidLayout, template)
+		case c == '{':
+			end := strings.IndexByte(template[i:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unclosed '{' in %q", ErrInvalidLayout, template)
+			}
+			seg, err := parseToken(template[i+1 : i+end])
+			if err != nil {
+				return nil, err
+			}
+			if literal.Len() > 0 {
+				l.segments = append(l.segments, segment{literal: literal.String()})
+				literal.Reset()
+			}
+			l.segments = append(l.segments, seg)
+			i += end
+		default:
+			literal.WriteByte(c)
+		}
+	}
+	if literal.Len() > 0 {
+		l.segments = append(l.segments, segment{literal: literal.String()})
+	}
+	return l, nil
+}
+
+// MustParse is like Parse but panics if the template is invalid.
+func MustParse(template string) *Layout {
+	l, err := Parse(template)
+	if err != nil {
+		panic(err)
+	}
+	return l
+}
+
+// parseToken parses the inside of a "{...}" token.
+func parseToken(spec string) (segment, error) {
+	spec, style, _ := strings.Cut(spec, ":")
+
+	start := strings.IndexFunc(spec, unicode.IsLetter)
+	if start < 0 {
+		return segment{}, fmt.Errorf("%w: empty token {%s}", ErrInvalidLayout, spec)
+	}
+	end := start + strings.IndexFunc(spec[start:], func(r rune) bool { return !unicode.IsLetter(r) })
+	if end < start {
+		end = len(spec)
+	}
+
+	seg := segment{token: strings.ToLower(spec[start:end]), prefix: spec[:start], suffix: spec[end:]}
+	if !tokens[seg.token] {
+		return segment{}, fmt.Errorf("%w: unknown token %q", ErrInvalidLayout, seg.token)
+	}
+
+	for _, item := range splitStyle(style) {
+		if attr, exists := attributes[strings.ToLower(item)]; exists {
+			seg.attrs += attr
+			continue
+		}
+		color, err := colors.Parse(item)
+		if err != nil {
+			return segment{}, fmt.Errorf("%w: token %q: %w", ErrInvalidLayout, seg.token, err)
+		}
+		seg.color = color
+	}
+	return seg, nil
+}
+
+var attributes = map[string]string{
+	"bold":          colors.Bold,
+	"dim":           colors.Dim,
+	"italic":        colors.Italic,
+	"underline":     colors.Underline,
+	"reverse":       colors.Reverse,
+	"strikethrough": colors.Strikethrough,
+}
+
+// splitStyle splits a style list on commas outside parentheses, so that
+// "rgb(1, 2, 3),bold" yields two items.
+func splitStyle(style string) []string {
+	var items []string
+	depth, start := 0, 0
+	for i, c := range style {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				items = append(items, style[start:i])
+				start = i + 1
+			}
+		}
+	}
+	items = append(items, style[start:])
+
+	trimmed := items[:0]
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			trimmed = append(trimmed, item)
+		}
+	}
+	return trimmed
+}
+
+// String returns the template the layout was parsed from.
+func (l *Layout) String() string {
+	return l.template
+}
+
+// Render renders line for the given color profile.
+func (l *Layout) Render(line Line, profile colors.Profile) string {
+	styled := profile != colors.ProfileNone
+
+	var b strings.Builder
+	if styled {
+		b.WriteString(line.Style)
+	}
+
+	// Literal text is held back until the next token with a value, so that
+	// trailing separators of empty tokens can be dropped.
+	var pending string
+	dropSpace := false
+	for _, seg := range l.segments {
+		if seg.token == "" {
+			text := seg.literal
+			if dropSpace {
+				text = strings.TrimPrefix(text, " ")
+				dropSpace = false
+			}
+			pending += text
+			continue
+		}
+
+		value := l.value(seg.token, line)
+		if value == "" {
+			dropSpace = true
+			continue
+		}
+		dropSpace = false
+
+		b.WriteString(pending)
+		pending = ""
+		tokenStyle := seg.attrs + seg.color.Sequence(profile)
+		switch {
+		case styled && seg.token == TokenFields:
+			base := line.Style
+			if tokenStyle != "" {
+				base = colors.Reset + tokenStyle
+				b.WriteString(base)
+			}
+			b.WriteString(seg.prefix + styledFields(line.Fields, base) + seg.suffix)
+			if tokenStyle != "" {
+				b.WriteString(colors.Reset + line.Style)
+			}
+		case styled && tokenStyle != "":
+			b.WriteString(colors.Reset + tokenStyle + seg.prefix + value + seg.suffix + colors.Reset + line.Style)
+		default:
+			b.WriteString(seg.prefix + value + seg.suffix)
+		}
+	}
+	b.WriteString(strings.TrimRight(pending, " "))
+
+	if styled {
+		b.WriteString(colors.Reset)
+	}
+	return b.String()
+}
+
+func (l *Layout) value(token string, line Line) string {
+	switch token {
+	case TokenTimestamp:
+		if line.Time.IsZero() {
+			return ""
+		}
+		return line.Time.Format(l.TimeFormat)
+	case TokenElapsed:
+		return FormatElapsed(line.Elapsed)
+	case TokenType:
+		return strings.ToUpper(string(line.Type))
+	case TokenLabel:
+		return line.Label
+	case TokenScope:
+		return line.Scope
+	case TokenIcon:
+		return line.Icon
+	case TokenMessage:
+		return line.Message
+	case TokenFields:
+		return field.String(line.Fields)
+	case TokenCaller:
+		return line.Caller
+	default:
+		return ""
+	}
+}
+
+// styledFields renders fields with dimmed keys. base restores the style of
+// the surrounding text after each key.
+func styledFields(fields []field.Field, base string) string {
+	var b strings.Builder
+	for i, f := range fields {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(colors.Dim + f.Key + "=" + colors.Reset + base)
+		b.WriteString(field.Quote(field.Format(f.Value)))
+	}
+	return b.String()
+}
+
+// elapsedSteps round the elapsed token to milliseconds below a minute and
+// to seconds above.
+var elapsedSteps = []duration.Step{{From: time.Minute, Precision: time.Second}, {Precision: time.Millisecond}}
+
+// FormatElapsed formats d for the elapsed token, rounded to milliseconds
+// below a minute and to seconds above.
+func FormatElapsed(d time.Duration) string {
+	return duration.Format(d, elapsedSteps)
+}
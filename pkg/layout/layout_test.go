@@ -0,0 +1,194 @@
+package layout
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jsas4coding/utify/pkg/colors"
+	"github.com/jsas4coding/utify/pkg/field"
+	"github.com/jsas4coding/utify/pkg/messages"
+)
+
+var testLine = Line{
+	Time:    time.Date(2024, 5, 1, 14, 3, 9, 0, time.UTC),
+	Elapsed: 1234567 * time.Microsecond,
+	Type:    messages.Success,
+	Label:   "OK",
+	Scope:   "db",
+	Icon:    "✓",
+	Message: "migrated",
+	Fields:  []field.Field{field.F("tables", 3)},
+	Caller:  "main.go:42",
+}
+
+func TestRenderTokens(t *testing.T) {
+	tests := []struct {
+		template string
+		expected string
+	}{
+		{"{timestamp}", "14:03:09"},
+		{"{elapsed}", "1.235s"},
+		{"{type}", "SUCCESS"},
+		{"{label}", "OK"},
+		{"{scope}", "db"},
+		{"{icon}", "✓"},
+		{"{message}", "migrated"},
+		{"{fields}", "tables=3"},
+		{"{caller}", "main.go:42"},
+		{"{[scope]} {message}", "[db] migrated"},
+		{"{{literal}} {message}", "{literal} migrated"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			l, err := Parse(tt.template)
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			if got := l.Render(testLine, colors.ProfileNone); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRenderDropsEmptyTokens(t *testing.T) {
+	l := MustParse("{timestamp} {icon} {label} {[scope]} {message} {fields} {caller}")
+	line := Line{Message: "hello"}
+
+	if got := l.Render(line, colors.ProfileNone); got != "hello" {
+		t.Errorf("Expected empty tokens and their spaces to be dropped, got %q", got)
+	}
+
+	line.Icon, line.Fields = "i", []field.Field{field.F("k", "v")}
+	if got := l.Render(line, colors.ProfileNone); got != "i hello k=v" {
+		t.Errorf("Unexpected output %q", got)
+	}
+}
+
+func TestRenderStyles(t *testing.T) {
+	l := MustParse("{timestamp:dim} {message} {[scope]:rgb(0, 0, 255),bold}")
+	line := testLine
+	line.Style = colors.Green
+
+	expected := colors.Green +
+		colors.Reset + colors.Dim + "14:03:09" + colors.Reset + colors.Green +
+		" migrated " +
+		colors.Reset + colors.Bold + "\033[38;2;0;0;255m[db]" + colors.Reset + colors.Green +
+		colors.Reset
+	if got := l.Render(line, colors.ProfileTrueColor); got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+
+	if got := l.Render(line, colors.ProfileNone); got != "14:03:09 migrated [db]" {
+		t.Errorf("Expected no escapes for ProfileNone, got %q", got)
+	}
+}
+
+func TestTimeFormat(t *testing.T) {
+	l := MustParse("{timestamp} {message}")
+	l.TimeFormat = time.RFC3339
+	if got := l.Render(testLine, colors.ProfileNone); got != "2024-05-01T14:03:09Z migrated" {
+		t.Errorf("Unexpected output %q", got)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	invalid := []string{
+		"{message",
+		"message}",
+		"{nope}",
+		"{}",
+		"{message:notacolor}",
+	}
+	for _, template := range invalid {
+		if _, err := Parse(template); !errors.Is(err, ErrInvalidLayout) {
+			t.Errorf("Parse(%q): expected ErrInvalidLayout, got %v", template, err)
+		}
+	}
+}
+
+func TestPresets(t *testing.T) {
+	for _, name := range Presets() {
+		l, err := Preset(name)
+		if err != nil {
+			t.Fatalf("Preset(%q) failed: %v", name, err)
+		}
+		if l.Render(testLine, colors.ProfileNone) == "" {
+			t.Errorf("Preset %q rendered nothing", name)
+		}
+	}
+
+	compact, _ := Preset(Compact)
+	if got := compact.Render(testLine, colors.ProfileNone); got != "✓ migrated" {
+		t.Errorf("Unexpected compact output %q", got)
+	}
+
+	if _, err := Preset("nope"); !errors.Is(err, ErrUnknownPreset) {
+		t.Errorf("Expected ErrUnknownPreset, got %v", err)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	l, err := Resolve(Timestamped)
+	if err != nil || l.String() != presets[Timestamped] {
+		t.Errorf("Expected the timestamped preset, got %v, %v", l, err)
+	}
+	l, err = Resolve("{type}: {message}")
+	if err != nil || l.Render(testLine, colors.ProfileNone) != "SUCCESS: migrated" {
+		t.Errorf("Expected a parsed template, got %v", err)
+	}
+}
+
+func TestLayoutsLookup(t *testing.T) {
+	compact, _ := Preset(Compact)
+	labeled, _ := Preset(Labeled)
+
+	layouts := Layouts{}
+	if layouts.Lookup(messages.Info) != defaultLayout {
+		t.Error("Expected the default layout for an empty table")
+	}
+
+	layouts[messages.Default] = compact
+	layouts[messages.Error] = labeled
+	if layouts.Lookup(messages.Info) != compact || layouts.Lookup(messages.Error) != labeled {
+		t.Error("Expected per-type layouts with a default fallback")
+	}
+
+	clone := layouts.Clone()
+	delete(clone, messages.Error)
+	if layouts.Lookup(messages.Error) != labeled {
+		t.Error("Modifying a clone should not affect the original")
+	}
+}
+
+func TestRenderStyledFields(t *testing.T) {
+	l := MustParse("{message} {fields}")
+	line := Line{Message: "deployed", Style: colors.Green,
+		Fields: []field.Field{field.F("service", "api"), field.F("note", "two words")}}
+
+	expected := colors.Green + "deployed " +
+		colors.Dim + "service=" + colors.Reset + colors.Green + "api " +
+		colors.Dim + "note=" + colors.Reset + colors.Green + `"two words"` +
+		colors.Reset
+	if got := l.Render(line, colors.ProfileANSI); got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+	if got := l.Render(line, colors.ProfileNone); got != `deployed service=api note="two words"` {
+		t.Errorf("Unexpected plain output %q", got)
+	}
+}
+
+func TestFormatElapsed(t *testing.T) {
+	tests := map[time.Duration]string{
+		1500 * time.Microsecond:    "2ms",
+		1234567 * time.Microsecond: "1.235s",
+		185400 * time.Millisecond:  "3m5s",
+	}
+	for d, expected := range tests {
+		if s := FormatElapsed(d); s != expected {
+			t.Errorf("FormatElapsed(%v): expected %q, got %q", d, expected, s)
+		}
+	}
+}
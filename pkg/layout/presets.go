@@ -0,0 +1,79 @@
+package layout
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/jsas4coding/utify/pkg/messages"
+)
+
+// Names of the preset layouts.
+const (
+	Default     = "default"
+	Compact     = "compact"
+	Labeled     = "labeled"
+	Timestamped = "timestamped"
+)
+
+var presets = map[string]string{
+	Default:     "{icon} {label} {[scope]} {message} {fields} {caller:dim}",
+	Compact:     "{icon} {message}",
+	Labeled:     "{type:bold} {[scope]} {icon} {label} {message} {fields} {caller:dim}",
+	Timestamped: "{timestamp:dim} {icon} {label} {[scope]} {message} {fields} {caller:dim}",
+}
+
+// Preset returns a new copy of a preset layout.
+func Preset(name string) (*Layout, error) {
+	template, exists := presets[name]
+	if !exists {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownPreset, name)
+	}
+	return MustParse(template), nil
+}
+
+// Resolve returns the preset named spec, or parses spec as a template if it
+// is not a preset name.
+func Resolve(spec string) (*Layout, error) {
+	if _, exists := presets[spec]; exists {
+		return Preset(spec)
+	}
+	return Parse(spec)
+}
+
+// Presets returns the names of the preset layouts in sorted order.
+func Presets() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var defaultLayout = MustParse(presets[Default])
+
+// Layouts maps message types to layouts. The messages.Default entry applies
+// to every type without its own entry.
+type Layouts map[messages.Type]*Layout
+
+// Lookup returns the layout for msgType, falling back to the messages.Default
+// entry and then to the default preset.
+func (l Layouts) Lookup(msgType messages.Type) *Layout {
+	if layout, exists := l[msgType]; exists && layout != nil {
+		return layout
+	}
+	if layout, exists := l[messages.Default]; exists && layout != nil {
+		return layout
+	}
+	return defaultLayout
+}
+
+// Clone returns a copy of the table. Layouts are immutable once parsed apart
+// from TimeFormat, so the entries are shared.
+func (l Layouts) Clone() Layouts {
+	clone := make(Layouts, len(l))
+	for k, v := range l {
+		clone[k] = v
+	}
+	return clone
+}
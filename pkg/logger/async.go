@@ -0,0 +1,188 @@
+package logger
+
+import (
+	"bytes"
+	"sync"
+	"time"
+)
+
+// OverflowPolicy decides what happens to an entry when the async queue is
+// full.
+type OverflowPolicy int
+
+const (
+	// Block waits until the queue has room.
+	Block OverflowPolicy = iota
+	// DropNewest discards the entry being logged.
+	DropNewest
+	// DropOldest discards the oldest queued entry to make room.
+	DropOldest
+)
+
+// Defaults for the zero Async fields.
+const (
+	DefaultQueueSize     = 1024
+	DefaultFlushInterval = time.Second
+)
+
+// batchSize is the amount of buffered file output that is written without
+// waiting for the flush interval.
+const batchSize = 64 << 10
+
+// Async configures asynchronous logging. Entries are queued and written by
+// a background goroutine; file output is batched and written at least every
+// FlushInterval. Zero fields use the defaults.
+type Async struct {
+	QueueSize     int
+	FlushInterval time.Duration
+	Policy        OverflowPolicy
+}
+
+// asyncQueue is the queue and background writer of an async Logger.
+type asyncQueue struct {
+	l       *Logger
+	policy  OverflowPolicy
+	entries chan LogEntry
+	flushes chan chan struct{}
+	done    chan struct{}
+
+	// mu guards closed; senders hold it for reading so that the channel
+	// is never closed under them.
+	mu     sync.RWMutex
+	closed bool
+
+	buf bytes.Buffer
+}
+
+func startAsync(l *Logger, a Async) *asyncQueue {
+	if a.QueueSize <= 0 {
+		a.QueueSize = DefaultQueueSize
+	}
+	if a.FlushInterval <= 0 {
+		a.FlushInterval = DefaultFlushInterval
+	}
+	q := &asyncQueue{
+		l:       l,
+		policy:  a.Policy,
+		entries: make(chan LogEntry, a.QueueSize),
+		flushes: make(chan chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go q.run(a.FlushInterval)
+	return q
+}
+
+// enqueue queues e according to the overflow policy. It reports false if
+// the queue was stopped and e must be written synchronously.
+func (q *asyncQueue) enqueue(e LogEntry) bool {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.closed {
+		return false
+	}
+
+	switch q.policy {
+	case DropNewest:
+		select {
+		case q.entries <- e:
+		default:
+			q.l.dropped.Add(1)
+		}
+	case DropOldest:
+		for {
+			select {
+			case q.entries <- e:
+				return true
+			default:
+			}
+			select {
+			case <-q.entries:
+				q.l.dropped.Add(1)
+			default:
+			}
+		}
+	default:
+		q.entries <- e
+	}
+	return true
+}
+
+// flush waits until every entry queued before the call is written.
+func (q *asyncQueue) flush() {
+	q.mu.RLock()
+	if q.closed {
+		q.mu.RUnlock()
+		return
+	}
+	ack := make(chan struct{})
+	q.flushes <- ack
+	q.mu.RUnlock()
+	<-ack
+}
+
+// stop writes the queued entries and stops the background writer.
+func (q *asyncQueue) stop() {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.entries)
+	}
+	q.mu.Unlock()
+	<-q.done
+}
+
+func (q *asyncQueue) run(interval time.Duration) {
+	defer close(q.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case e, ok := <-q.entries:
+			if !ok {
+				q.writeBuffered()
+				return
+			}
+			q.write(e)
+		case ack := <-q.flushes:
+			q.drain()
+			q.writeBuffered()
+			close(ack)
+		case <-ticker.C:
+			q.writeBuffered()
+		}
+	}
+}
+
+// drain writes the entries currently queued.
+func (q *asyncQueue) drain() {
+	for {
+		select {
+		case e, ok := <-q.entries:
+			if !ok {
+				return
+			}
+			q.write(e)
+		default:
+			return
+		}
+	}
+}
+
+// write sends e to the sinks and buffers its file output.
+func (q *asyncQueue) write(e LogEntry) {
+	q.l.writeSinks(e)
+	q.buf.Write(encodeLine(q.l.GetEncoder(), e))
+	q.buf.WriteByte('\n')
+	if q.buf.Len() >= batchSize {
+		q.writeBuffered()
+	}
+}
+
+func (q *asyncQueue) writeBuffered() {
+	if q.buf.Len() == 0 {
+		return
+	}
+	q.l.writeBatch(q.buf.Bytes())
+	q.buf.Reset()
+}
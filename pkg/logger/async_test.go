@@ -0,0 +1,163 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jsas4coding/utify/pkg/messages"
+)
+
+// blockingWriter blocks writes until release is closed.
+type blockingWriter struct {
+	release chan struct{}
+	mu      sync.Mutex
+	buf     bytes.Buffer
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	<-w.release
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *blockingWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String()
+}
+
+func TestAsyncFlush(t *testing.T) {
+	var buf syncBuffer
+	l := NewWriter(&buf)
+	l.EnableAsync(Async{FlushInterval: time.Hour})
+	defer l.Close()
+
+	for i := 0; i < 100; i++ {
+		l.LogMessage(messages.Info, fmt.Sprintf("entry %d", i))
+	}
+	l.Flush()
+
+	if got := strings.Count(buf.String(), "\n"); got != 100 {
+		t.Errorf("Expected 100 entries after Flush, got %d", got)
+	}
+	if !strings.Contains(buf.String(), `"message":"entry 99"`) {
+		t.Error("Expected entries to be written as JSON in order")
+	}
+}
+
+func TestAsyncFlushInterval(t *testing.T) {
+	var buf syncBuffer
+	l := NewWriter(&buf)
+	l.EnableAsync(Async{FlushInterval: 10 * time.Millisecond})
+	defer l.Close()
+
+	l.LogMessage(messages.Info, "eventually")
+	deadline := time.Now().Add(2 * time.Second)
+	for !strings.Contains(buf.String(), "eventually") {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the flush interval to write the entry")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestAsyncCloseDelivers(t *testing.T) {
+	mem := NewMemorySink(nil, messages.SeverityTrace)
+	l := NewWithSinks(mem)
+	l.EnableAsync(Async{QueueSize: 4})
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				l.LogMessage(messages.Info, "entry")
+			}
+		}()
+	}
+	wg.Wait()
+	l.Close()
+
+	if got := len(mem.Entries()); got != 200 {
+		t.Errorf("Expected Block to deliver all 200 entries, got %d", got)
+	}
+	if l.Dropped() != 0 || l.IsAsync() {
+		t.Error("Expected no drops and a synchronous logger after Close")
+	}
+}
+
+func TestAsyncDropPolicies(t *testing.T) {
+	tests := []struct {
+		policy   OverflowPolicy
+		expected []string
+	}{
+		{DropNewest, []string{"entry 0", "entry 1", "entry 2"}},
+		{DropOldest, []string{"entry 0", "entry 4", "entry 5"}},
+	}
+
+	for _, tt := range tests {
+		w := &blockingWriter{release: make(chan struct{})}
+		l := NewWithSinks(NewWriterSink(w, TextEncoder{}, messages.SeverityTrace))
+		l.EnableAsync(Async{QueueSize: 2, Policy: tt.policy})
+
+		// The writer takes the first entry and blocks on it, leaving room
+		// for two queued entries.
+		l.LogMessage(messages.Info, "entry 0")
+		time.Sleep(20 * time.Millisecond)
+		for i := 1; i < 6; i++ {
+			l.LogMessage(messages.Info, fmt.Sprintf("entry %d", i))
+		}
+		close(w.release)
+		l.Close()
+
+		lines := strings.Split(strings.TrimSpace(w.String()), "\n")
+		if len(lines) != len(tt.expected) {
+			t.Fatalf("Policy %d: expected %d entries, got %q", tt.policy, len(tt.expected), lines)
+		}
+		for i, line := range lines {
+			if !strings.HasSuffix(line, tt.expected[i]) {
+				t.Errorf("Policy %d: entry %d is %q, expected %q", tt.policy, i, line, tt.expected[i])
+			}
+		}
+		if l.Dropped() != 3 {
+			t.Errorf("Policy %d: expected 3 dropped entries, got %d", tt.policy, l.Dropped())
+		}
+	}
+}
+
+func TestAsyncAfterDisable(t *testing.T) {
+	var buf syncBuffer
+	l := NewWriter(&buf)
+	l.EnableAsync(Async{FlushInterval: time.Hour})
+	l.LogMessage(messages.Info, "queued")
+	l.DisableAsync()
+	l.LogMessage(messages.Info, "direct")
+
+	if got := strings.Count(buf.String(), "\n"); got != 2 {
+		t.Errorf("Expected both entries after DisableAsync, got %q", buf.String())
+	}
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
@@ -0,0 +1,453 @@
+package logger
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/jsas4coding/utify/pkg/caller"
+	"github.com/jsas4coding/utify/pkg/field"
+	"github.com/jsas4coding/utify/pkg/messages"
+)
+
+// ErrUnknownEncoder is returned by EncoderByName for unknown encoder names.
+var ErrUnknownEncoder = errors.New("unknown log encoder")
+
+// Encoder encodes a log entry as a single line without a trailing newline.
+type Encoder interface {
+	Encode(e LogEntry) ([]byte, error)
+}
+
+var encoders = map[string]func() Encoder{
+	"json":   func() Encoder { return JSONEncoder{} },
+	"logfmt": func() Encoder { return LogfmtEncoder{} },
+	"text":   func() Encoder { return TextEncoder{} },
+	"ecs":    func() Encoder { return ECSEncoder{} },
+	"gelf":   func() Encoder { return GELFEncoder{} },
+}
+
+// EncoderByName returns the encoder with the given name in its default
+// configuration: "json", "logfmt", "text", "ecs" or "gelf".
+func EncoderByName(name string) (Encoder, error) {
+	newEncoder, exists := encoders[strings.ToLower(name)]
+	if !exists {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownEncoder, name)
+	}
+	return newEncoder(), nil
+}
+
+// EncoderNames returns the names accepted by EncoderByName, sorted.
+func EncoderNames() []string {
+	names := make([]string, 0, len(encoders))
+	for name := range encoders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LevelFormat selects how encoders write the level of an entry.
+type LevelFormat int
+
+const (
+	// LevelType writes the upper-cased message type, e.g. "WARNING".
+	LevelType LevelFormat = iota
+	// LevelName writes the severity name, e.g. "warn".
+	LevelName
+	// LevelSyslog writes the numeric syslog severity, e.g. 4.
+	LevelSyslog
+	// LevelSlog writes the log/slog level name, e.g. "WARN".
+	LevelSlog
+)
+
+// format returns the level of e in format f.
+func (f LevelFormat) format(e LogEntry) any {
+	switch f {
+	case LevelName:
+		return messages.SeverityOf(e.Type).String()
+	case LevelSyslog:
+		return SyslogSeverity(messages.SeverityOf(e.Type))
+	case LevelSlog:
+		return SlogLevel(messages.SeverityOf(e.Type)).String()
+	default:
+		return e.Level
+	}
+}
+
+// SyslogSeverity returns the RFC 5424 severity of s, from 2 (critical) to
+// 7 (debug and trace).
+func SyslogSeverity(s messages.Severity) int {
+	switch {
+	case s >= messages.SeverityCritical:
+		return 2
+	case s >= messages.SeverityError:
+		return 3
+	case s >= messages.SeverityWarn:
+		return 4
+	case s >= messages.SeverityNotice:
+		return 5
+	case s >= messages.SeverityInfo:
+		return 6
+	default:
+		return 7
+	}
+}
+
+// SlogLevel returns the log/slog level of s: Debug, Info, Warn and Error map
+// to their slog counterparts, trace to Debug-4, notice to Info+2 and
+// critical to Error+4.
+func SlogLevel(s messages.Severity) slog.Level {
+	switch {
+	case s >= messages.SeverityCritical:
+		return slog.LevelError + 4
+	case s >= messages.SeverityError:
+		return slog.LevelError
+	case s >= messages.SeverityWarn:
+		return slog.LevelWarn
+	case s >= messages.SeverityNotice:
+		return slog.LevelInfo + 2
+	case s >= messages.SeverityInfo:
+		return slog.LevelInfo
+	case s >= messages.SeverityDebug:
+		return slog.LevelDebug
+	default:
+		return slog.LevelDebug - 4
+	}
+}
+
+// FieldNames names the fixed properties written by the JSON and logfmt
+// encoders. Empty names use the defaults ("timestamp", "level", "message",
+// "type", "binary" and "caller"); "-" omits the property.
+type FieldNames struct {
+	Timestamp string
+	Level     string
+	Message   string
+	Type      string
+	Binary    string
+	Caller    string
+}
+
+func (n FieldNames) withDefaults() FieldNames {
+	n.Timestamp = cmp.Or(n.Timestamp, "timestamp")
+	n.Level = cmp.Or(n.Level, "level")
+	n.Message = cmp.Or(n.Message, "message")
+	n.Type = cmp.Or(n.Type, "type")
+	n.Binary = cmp.Or(n.Binary, "binary")
+	n.Caller = cmp.Or(n.Caller, "caller")
+	return n
+}
+
+// entryTime returns the time of e, parsing Timestamp for entries built
+// without Time.
+func entryTime(e LogEntry) time.Time {
+	if !e.Time.IsZero() {
+		return e.Time
+	}
+	t, _ := time.Parse(time.RFC3339, e.Timestamp)
+	return t
+}
+
+// timestamp formats the time of e with layout, or returns e.Timestamp when
+// layout is empty.
+func timestamp(e LogEntry, layout string) string {
+	if layout == "" {
+		return e.Timestamp
+	}
+	return entryTime(e).Format(layout)
+}
+
+// fixedFields returns the fixed properties of e named by names, skipping
+// omitted ones. The caller, if captured, is written with callerValue.
+func fixedFields(e LogEntry, names FieldNames, level LevelFormat, timeFormat string,
+	callerValue func(*caller.Caller) any) []field.Field {
+	names = names.withDefaults()
+	all := []field.Field{
+		{Key: names.Timestamp, Value: timestamp(e, timeFormat)},
+		{Key: names.Level, Value: level.format(e)},
+		{Key: names.Message, Value: e.Message},
+		{Key: names.Type, Value: string(e.Type)},
+		{Key: names.Binary, Value: e.Binary},
+	}
+	if e.Caller != nil {
+		all = append(all, field.Field{Key: names.Caller, Value: callerValue(e.Caller)})
+	}
+	fixed := all[:0]
+	for _, f := range all {
+		if f.Key != "-" {
+			fixed = append(fixed, f)
+		}
+	}
+	return fixed
+}
+
+// appendFields appends fields after fixed, renaming the keys of fields that
+// collide with a fixed key with rename.
+func appendFields(fixed, fields []field.Field, rename func(string) string) []field.Field {
+	taken := make(map[string]bool, len(fixed))
+	for _, f := range fixed {
+		taken[f.Key] = true
+	}
+	all := append([]field.Field(nil), fixed...)
+	for _, f := range fields {
+		if taken[f.Key] {
+			f.Key = rename(f.Key)
+		}
+		all = append(all, f)
+	}
+	return all
+}
+
+func prefixFields(key string) string {
+	return "fields." + key
+}
+
+// callerObject writes a caller as a JSON object with file, line and
+// function.
+func callerObject(c *caller.Caller) any {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return c.String()
+	}
+	return json.RawMessage(data)
+}
+
+// callerString writes a caller in its short form.
+func callerString(c *caller.Caller) any {
+	return c.String()
+}
+
+// marshalObject encodes fields as a JSON object, keeping their order.
+func marshalObject(fields []field.Field) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, f := range fields {
+		k, err := json.Marshal(f.Key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(field.JSONValue(f.Value))
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// JSONEncoder encodes entries as JSON objects, the format of the log file.
+// The zero value writes the fixed properties under their default names and
+// the level as the upper-cased message type.
+type JSONEncoder struct {
+	Names FieldNames
+	Level LevelFormat
+	// TimeFormat is the time.Format layout of the timestamp. Empty uses
+	// RFC 3339.
+	TimeFormat string
+}
+
+// Encode implements Encoder.
+func (enc JSONEncoder) Encode(e LogEntry) ([]byte, error) {
+	fixed := fixedFields(e, enc.Names, enc.Level, enc.TimeFormat, callerObject)
+	return marshalObject(appendFields(fixed, e.Fields, prefixFields))
+}
+
+// LogfmtEncoder encodes entries as logfmt lines:
+//
+//	timestamp=2024-05-01T12:00:00Z level=ERROR message="upload failed" type=error binary=app file=report.pdf
+type LogfmtEncoder struct {
+	Names      FieldNames
+	Level      LevelFormat
+	TimeFormat string
+}
+
+// Encode implements Encoder.
+func (enc LogfmtEncoder) Encode(e LogEntry) ([]byte, error) {
+	fields := appendFields(fixedFields(e, enc.Names, enc.Level, enc.TimeFormat, callerString), e.Fields, prefixFields)
+	for i, f := range fields {
+		fields[i].Key = logfmtKey(f.Key)
+	}
+	return []byte(field.String(fields)), nil
+}
+
+// TextEncoder encodes entries as human-readable lines, followed by the
+// caller if it was captured:
+//
+//	2024-05-01T12:00:00Z ERROR    upload failed file=report.pdf (server/upload.go:42)
+type TextEncoder struct {
+	Level      LevelFormat
+	TimeFormat string
+}
+
+// Encode implements Encoder.
+func (enc TextEncoder) Encode(e LogEntry) ([]byte, error) {
+	level := fmt.Sprint(enc.Level.format(e))
+
+	var b strings.Builder
+	b.WriteString(timestamp(e, enc.TimeFormat))
+	b.WriteByte(' ')
+	b.WriteString(level)
+	if pad := 8 - len(level); pad > 0 {
+		b.WriteString(strings.Repeat(" ", pad))
+	}
+	b.WriteByte(' ')
+	b.WriteString(escapeControl(e.Message))
+	if len(e.Fields) > 0 {
+		b.WriteByte(' ')
+		b.WriteString(field.String(e.Fields))
+	}
+	if e.Caller != nil {
+		b.WriteString(" (" + e.Caller.String() + ")")
+	}
+	return []byte(b.String()), nil
+}
+
+// ECSVersion is the Elastic Common Schema version written by ECSEncoder.
+const ECSVersion = "8.11.0"
+
+// ECSEncoder encodes entries as Elastic Common Schema JSON documents. The
+// message type is written as event.action and the binary as process.name;
+// fields are written as top-level properties.
+type ECSEncoder struct {
+	// Level is the format of log.level. The zero value writes the
+	// severity name, e.g. "warn".
+	Level LevelFormat
+}
+
+// Encode implements Encoder.
+func (enc ECSEncoder) Encode(e LogEntry) ([]byte, error) {
+	level := enc.Level
+	if level == LevelType {
+		level = LevelName
+	}
+	fixed := []field.Field{
+		{Key: "@timestamp", Value: entryTime(e).UTC().Format("2006-01-02T15:04:05.000Z")},
+		{Key: "log.level", Value: level.format(e)},
+		{Key: "message", Value: e.Message},
+		{Key: "ecs.version", Value: ECSVersion},
+		{Key: "event.action", Value: string(e.Type)},
+		{Key: "process.name", Value: e.Binary},
+	}
+	if e.Caller != nil {
+		fixed = append(fixed,
+			field.Field{Key: "log.origin.file.name", Value: e.Caller.File},
+			field.Field{Key: "log.origin.file.line", Value: e.Caller.Line},
+			field.Field{Key: "log.origin.function", Value: e.Caller.Function},
+		)
+	}
+	return marshalObject(appendFields(fixed, e.Fields, func(key string) string {
+		return "labels." + key
+	}))
+}
+
+// GELFEncoder encodes entries as GELF 1.1 JSON messages for Graylog. The
+// level is the syslog severity, and the type, binary and fields are written
+// as additional fields prefixed with an underscore.
+type GELFEncoder struct {
+	// Host is the host field. Empty uses the host name.
+	Host string
+}
+
+// Encode implements Encoder.
+func (enc GELFEncoder) Encode(e LogEntry) ([]byte, error) {
+	host := enc.Host
+	if host == "" {
+		host = hostname()
+	}
+	ts := float64(entryTime(e).UnixMilli()) / 1000
+	fixed := []field.Field{
+		{Key: "version", Value: "1.1"},
+		{Key: "host", Value: host},
+		{Key: "short_message", Value: e.Message},
+		{Key: "timestamp", Value: math.Round(ts*1000) / 1000},
+		{Key: "level", Value: SyslogSeverity(messages.SeverityOf(e.Type))},
+		{Key: "_type", Value: string(e.Type)},
+		{Key: "_binary", Value: e.Binary},
+	}
+	if e.Caller != nil {
+		fixed = append(fixed,
+			field.Field{Key: "_file", Value: e.Caller.File},
+			field.Field{Key: "_line", Value: e.Caller.Line},
+			field.Field{Key: "_function", Value: e.Caller.Function},
+		)
+	}
+	fields := make([]field.Field, len(e.Fields))
+	for i, f := range e.Fields {
+		fields[i] = field.Field{Key: gelfKey(f.Key), Value: f.Value}
+	}
+	return marshalObject(appendFields(fixed, fields, func(key string) string {
+		return "_field" + key
+	}))
+}
+
+// hostname returns the host name, resolved once.
+var hostname = sync.OnceValue(func() string {
+	host, _ := os.Hostname()
+	return host
+})
+
+// gelfKey returns key as a GELF additional field name: prefixed with an
+// underscore, with characters outside [A-Za-z0-9_.-] replaced and the
+// reserved "_id" renamed.
+func gelfKey(key string) string {
+	key = "_" + strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '.', r == '-':
+			return r
+		default:
+			return '_'
+		}
+	}, key)
+	if key == "_id" {
+		return "_field_id"
+	}
+	return key
+}
+
+// logfmtKey returns key as a logfmt key, with spaces, quotes, equals signs
+// and control characters replaced.
+func logfmtKey(key string) string {
+	if key == "" {
+		return "_"
+	}
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '=' || r == '"' || unicode.IsControl(r) {
+			return '_'
+		}
+		return r
+	}, key)
+}
+
+// escapeControl escapes the control characters of s, so a message cannot
+// break a line or inject terminal sequences.
+func escapeControl(s string) string {
+	if !strings.ContainsFunc(s, unicode.IsControl) {
+		return s
+	}
+	var b strings.Builder
+	for _, r := range s {
+		if unicode.IsControl(r) {
+			q := strconv.QuoteRune(r)
+			b.WriteString(q[1 : len(q)-1])
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
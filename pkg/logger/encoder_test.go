@@ -0,0 +1,213 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jsas4coding/utify/pkg/caller"
+	"github.com/jsas4coding/utify/pkg/field"
+	"github.com/jsas4coding/utify/pkg/messages"
+)
+
+func testEntry() LogEntry {
+	t := time.Date(2024, 5, 1, 12, 0, 0, 250e6, time.UTC)
+	return LogEntry{
+		Timestamp: t.Format(time.RFC3339),
+		Level:     "WARNING",
+		Message:   "disk almost full",
+		Type:      messages.Warning,
+		Binary:    "app",
+		Fields:    []field.Field{field.F("free", field.Bytes(1536)), field.F("message", "dup"), field.F("path", "/var lib")},
+		Time:      t,
+	}
+}
+
+func TestJSONEncoder(t *testing.T) {
+	tests := []struct {
+		name     string
+		encoder  JSONEncoder
+		expected string
+	}{
+		{
+			"default",
+			JSONEncoder{},
+			`{"timestamp":"2024-05-01T12:00:00Z","level":"WARNING","message":"disk almost full","type":"warning","binary":"app","free":1536,"fields.message":"dup","path":"/var lib"}`,
+		},
+		{
+			"custom names and level",
+			JSONEncoder{
+				Names:      FieldNames{Timestamp: "ts", Message: "msg", Binary: "-"},
+				Level:      LevelSlog,
+				TimeFormat: time.RFC3339Nano,
+			},
+			`{"ts":"2024-05-01T12:00:00.25Z","level":"WARN","msg":"disk almost full","type":"warning","free":1536,"message":"dup","path":"/var lib"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.encoder.Encode(testEntry())
+			if err != nil {
+				t.Fatalf("Encode failed: %v", err)
+			}
+			if string(data) != tt.expected {
+				t.Errorf("Expected\n%s\ngot\n%s", tt.expected, data)
+			}
+		})
+	}
+}
+
+func TestLogfmtEncoder(t *testing.T) {
+	enc := LogfmtEncoder{Names: FieldNames{Timestamp: "ts", Message: "msg", Type: "-", Binary: "-"}, Level: LevelName}
+	data, err := enc.Encode(testEntry())
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	expected := `ts=2024-05-01T12:00:00Z level=warn msg="disk almost full" free="1.5 KiB" message=dup path="/var lib"`
+	if string(data) != expected {
+		t.Errorf("Expected\n%s\ngot\n%s", expected, data)
+	}
+}
+
+func TestTextEncoderLevelFormat(t *testing.T) {
+	data, _ := TextEncoder{Level: LevelSyslog, TimeFormat: "15:04:05"}.Encode(testEntry())
+	if !strings.HasPrefix(string(data), "12:00:00 4        disk almost full free=") {
+		t.Errorf("Unexpected text line %q", data)
+	}
+}
+
+func TestEncodersEscape(t *testing.T) {
+	e := testEntry()
+	e.Message = "line one\nline two\x1b[31m"
+	e.Fields = []field.Field{field.F("user name", "ana"), field.F("a=b\n", 1), field.F("", 2)}
+
+	data, _ := LogfmtEncoder{Names: FieldNames{Timestamp: "-", Level: "-", Message: "-", Type: "-", Binary: "-"}}.Encode(e)
+	if expected := "user_name=ana a_b_=1 _=2"; string(data) != expected {
+		t.Errorf("Expected logfmt %q, got %q", expected, data)
+	}
+	data, _ = TextEncoder{}.Encode(e)
+	if !strings.Contains(string(data), `line one\nline two\x1b[31m`) {
+		t.Errorf("Expected the control characters to be escaped, got %q", data)
+	}
+}
+
+func TestECSEncoder(t *testing.T) {
+	data, err := ECSEncoder{}.Encode(testEntry())
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("Invalid JSON %s: %v", data, err)
+	}
+	expected := map[string]any{
+		"@timestamp":     "2024-05-01T12:00:00.250Z",
+		"log.level":      "warn",
+		"message":        "disk almost full",
+		"ecs.version":    ECSVersion,
+		"event.action":   "warning",
+		"process.name":   "app",
+		"free":           float64(1536),
+		"labels.message": "dup",
+	}
+	for k, v := range expected {
+		if doc[k] != v {
+			t.Errorf("Expected %s=%v, got %v", k, v, doc[k])
+		}
+	}
+}
+
+func TestGELFEncoder(t *testing.T) {
+	e := testEntry()
+	e.Fields = append(e.Fields, field.F("id", 7), field.F("user name", "ana"))
+	data, err := GELFEncoder{Host: "web-1"}.Encode(e)
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	expected := `{"version":"1.1","host":"web-1","short_message":"disk almost full","timestamp":1714564800.25,"level":4,` +
+		`"_type":"warning","_binary":"app","_free":1536,"_message":"dup","_path":"/var lib","_field_id":7,"_user_name":"ana"}`
+	if string(data) != expected {
+		t.Errorf("Expected\n%s\ngot\n%s", expected, data)
+	}
+}
+
+func TestEncoderByName(t *testing.T) {
+	for _, name := range EncoderNames() {
+		enc, err := EncoderByName(strings.ToUpper(name))
+		if err != nil || enc == nil {
+			t.Errorf("EncoderByName(%q) failed: %v", name, err)
+		}
+	}
+	if _, err := EncoderByName("xml"); !errors.Is(err, ErrUnknownEncoder) {
+		t.Errorf("Expected ErrUnknownEncoder, got %v", err)
+	}
+}
+
+func TestLevelMappings(t *testing.T) {
+	tests := []struct {
+		severity messages.Severity
+		syslog   int
+		slog     slog.Level
+	}{
+		{messages.SeverityTrace, 7, slog.LevelDebug - 4},
+		{messages.SeverityDebug, 7, slog.LevelDebug},
+		{messages.SeverityInfo, 6, slog.LevelInfo},
+		{messages.SeverityNotice, 5, slog.LevelInfo + 2},
+		{messages.SeverityWarn, 4, slog.LevelWarn},
+		{messages.SeverityError, 3, slog.LevelError},
+		{messages.SeverityCritical, 2, slog.LevelError + 4},
+	}
+	for _, tt := range tests {
+		if got := SyslogSeverity(tt.severity); got != tt.syslog {
+			t.Errorf("SyslogSeverity(%s) = %d, expected %d", tt.severity, got, tt.syslog)
+		}
+		if got := SlogLevel(tt.severity); got != tt.slog {
+			t.Errorf("SlogLevel(%s) = %s, expected %s", tt.severity, got, tt.slog)
+		}
+	}
+}
+
+func TestLoggerEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewWriter(&buf)
+	l.SetEncoder(LogfmtEncoder{Level: LevelName})
+	l.LogMessage(messages.Error, "failed", field.F("code", 2))
+
+	if !strings.Contains(buf.String(), `level=error message=failed type=error`) || !strings.HasSuffix(buf.String(), "code=2\n") {
+		t.Errorf("Expected a logfmt line, got %q", buf.String())
+	}
+	l.SetEncoder(nil)
+	if _, ok := l.GetEncoder().(JSONEncoder); !ok {
+		t.Error("Expected SetEncoder(nil) to restore JSONEncoder")
+	}
+}
+
+func TestEncodersWithCaller(t *testing.T) {
+	e := testEntry()
+	e.Fields = nil
+	e.Caller = &caller.Caller{File: "/src/app/server/upload.go", Line: 42, Function: "main.upload"}
+
+	tests := []struct {
+		encoder  Encoder
+		contains string
+	}{
+		{JSONEncoder{}, `"binary":"app","caller":{"file":"/src/app/server/upload.go","line":42,"function":"main.upload"}}`},
+		{LogfmtEncoder{Names: FieldNames{Caller: "src"}}, ` binary=app src=server/upload.go:42`},
+		{TextEncoder{}, `disk almost full (server/upload.go:42)`},
+		{ECSEncoder{}, `"log.origin.file.name":"/src/app/server/upload.go","log.origin.file.line":42,"log.origin.function":"main.upload"`},
+		{GELFEncoder{Host: "h"}, `"_file":"/src/app/server/upload.go","_line":42,"_function":"main.upload"`},
+	}
+	for _, tt := range tests {
+		data, err := tt.encoder.Encode(e)
+		if err != nil {
+			t.Fatalf("%T: Encode failed: %v", tt.encoder, err)
+		}
+		if !strings.Contains(string(data), tt.contains) {
+			t.Errorf("%T: expected %s in %s", tt.encoder, tt.contains, data)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/jsas4coding/utify/pkg/messages"
+)
+
+// Environment variables configuring the default logger when the program
+// starts.
+const (
+	// EnvLogFile sets the log file, instead of DefaultTarget.
+	EnvLogFile = "UTIFY_LOG_FILE"
+	// EnvLogDisable turns logging off when set to a true value such as 1.
+	EnvLogDisable = "UTIFY_LOG_DISABLE"
+	// EnvLogFormat selects the encoder by name, see EncoderByName.
+	EnvLogFormat = "UTIFY_LOG_FORMAT"
+	// EnvLogLevel sets the minimum severity written to the log, e.g. warn,
+	// as SetLevel does. It takes precedence over the formatter thresholds.
+	EnvLogLevel = "UTIFY_LOG_LEVEL"
+)
+
+// DefaultTarget returns the default log file of app, following the XDG
+// base directory specification: $XDG_STATE_HOME/<app>/<app>.log, or
+// ~/.local/state/<app>/<app>.log if XDG_STATE_HOME is unset.
+func DefaultTarget(app string) (string, error) {
+	return defaultTarget(app, os.Getenv)
+}
+
+func defaultTarget(app string, getenv func(string) string) (string, error) {
+	dir := getenv("XDG_STATE_HOME")
+	// Relative paths are invalid and must be ignored
+	if !filepath.IsAbs(dir) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, app, app+".log"), nil
+}
+
+// configureFromEnv sets up the default logger. The default target is only
+// opened when the first entry is written, so programs that never log leave
+// no file behind. An explicit EnvLogFile is opened right away, falling back
+// to the default target. Invalid settings and the reason for a fallback,
+// including a default target failing when it is opened, are reported to
+// warn; the reason is also kept for Err.
+func (l *Logger) configureFromEnv(getenv func(string) string, warn io.Writer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.warn = warn
+	report := func(err error) {
+		_, _ = fmt.Fprintf(warn, "utify: %v\n", err)
+	}
+
+	if v := getenv(EnvLogLevel); v != "" {
+		if level, err := messages.ParseSeverity(v); err != nil {
+			report(fmt.Errorf("%s: %w", EnvLogLevel, err))
+		} else {
+			l.level = level
+			l.hasLevel = true
+		}
+	}
+
+	if v := getenv(EnvLogFormat); v != "" {
+		if enc, err := EncoderByName(v); err != nil {
+			report(fmt.Errorf("%s: %w", EnvLogFormat, err))
+		} else {
+			l.encoder = enc
+		}
+	}
+
+	l.enabled = true
+	if v := getenv(EnvLogDisable); v != "" {
+		if disable, err := strconv.ParseBool(v); err != nil {
+			report(fmt.Errorf("%s: invalid boolean %q", EnvLogDisable, v))
+		} else {
+			l.enabled = !disable
+		}
+	}
+
+	target, defErr := defaultTarget(l.binaryName, getenv)
+	file := getenv(EnvLogFile)
+	if file != "" && l.enabled {
+		f, err := l.open(file)
+		if err == nil {
+			l.logTarget = file
+			l.logFile = f
+			l.logger = log.New(f, "", 0)
+			return
+		}
+		if defErr != nil {
+			l.enabled = false
+			l.err = fmt.Errorf("%s: %w; logging disabled", EnvLogFile, err)
+		} else {
+			l.err = fmt.Errorf("%s: %w; falling back to '%s'", EnvLogFile, err, target)
+		}
+		report(l.err)
+	} else if defErr != nil && l.enabled {
+		l.enabled = false
+		l.err = fmt.Errorf("no default log location, logging disabled: %w", defErr)
+		report(l.err)
+	}
+
+	switch {
+	case file != "" && !l.enabled && l.err == nil:
+		// Disabled, but used if logging is enabled later
+		l.logTarget = file
+	case defErr == nil:
+		l.logTarget = target
+		l.pending = l.enabled
+	}
+}
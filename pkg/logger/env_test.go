@@ -0,0 +1,173 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jsas4coding/utify/pkg/messages"
+)
+
+func envFunc(env map[string]string) func(string) string {
+	return func(key string) string { return env[key] }
+}
+
+func TestDefaultTarget(t *testing.T) {
+	target, err := defaultTarget("app", envFunc(map[string]string{"XDG_STATE_HOME": "/state"}))
+	if err != nil || target != filepath.FromSlash("/state/app/app.log") {
+		t.Errorf("Expected /state/app/app.log, got %q, %v", target, err)
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skipf("No home directory: %v", err)
+	}
+	target, err = defaultTarget("app", envFunc(map[string]string{"XDG_STATE_HOME": "relative"}))
+	if expected := filepath.Join(home, ".local", "state", "app", "app.log"); err != nil || target != expected {
+		t.Errorf("Expected %s for a relative XDG_STATE_HOME, got %q, %v", expected, target, err)
+	}
+}
+
+func TestConfigureFromEnvOpensDefaultLazily(t *testing.T) {
+	state := t.TempDir()
+	l := &Logger{binaryName: "app"}
+	var warn bytes.Buffer
+	l.configureFromEnv(envFunc(map[string]string{"XDG_STATE_HOME": state}), &warn)
+	defer l.Close()
+
+	target := filepath.Join(state, "app", "app.log")
+	if l.GetLogTarget() != target || !l.IsEnabled() || l.Err() != nil || warn.Len() > 0 {
+		t.Fatalf("Unexpected configuration: target %q, enabled %v, err %v, warnings %q",
+			l.GetLogTarget(), l.IsEnabled(), l.Err(), warn.String())
+	}
+	if _, err := os.Stat(target); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Expected no log file before the first entry, got %v", err)
+	}
+
+	l.LogMessage(messages.Info, "first")
+	content, err := os.ReadFile(target)
+	if err != nil || !strings.Contains(string(content), "first") {
+		t.Errorf("Expected the entry in %s, got %q, %v", target, content, err)
+	}
+}
+
+func TestConfigureFromEnv(t *testing.T) {
+	dir := t.TempDir()
+	state := filepath.Join(dir, "state")
+	blocker := filepath.Join(dir, "file")
+	if err := os.WriteFile(blocker, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	custom := filepath.Join(dir, "custom", "app.log")
+
+	tests := []struct {
+		name     string
+		env      map[string]string
+		target   string
+		enabled  bool
+		encoder  Encoder
+		warning  string
+		errorMsg string
+	}{
+		{
+			name:    "file and format",
+			env:     map[string]string{EnvLogFile: custom, EnvLogFormat: "logfmt"},
+			target:  custom,
+			enabled: true,
+			encoder: LogfmtEncoder{},
+		},
+		{
+			name:     "unusable file falls back",
+			env:      map[string]string{EnvLogFile: filepath.Join(blocker, "app.log"), "XDG_STATE_HOME": state},
+			target:   filepath.Join(state, "app", "app.log"),
+			enabled:  true,
+			warning:  "falling back to",
+			errorMsg: EnvLogFile,
+		},
+		{
+			name:    "disabled",
+			env:     map[string]string{EnvLogDisable: "1", EnvLogFile: custom},
+			target:  custom,
+			enabled: false,
+		},
+		{
+			name:    "invalid values",
+			env:     map[string]string{EnvLogDisable: "maybe", EnvLogFormat: "xml", "XDG_STATE_HOME": state},
+			target:  filepath.Join(state, "app", "app.log"),
+			enabled: true,
+			warning: EnvLogFormat,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &Logger{binaryName: "app"}
+			var warn bytes.Buffer
+			l.configureFromEnv(envFunc(tt.env), &warn)
+			defer l.Close()
+
+			if l.GetLogTarget() != tt.target || l.IsEnabled() != tt.enabled {
+				t.Errorf("Expected target %q enabled %v, got %q %v", tt.target, tt.enabled, l.GetLogTarget(), l.IsEnabled())
+			}
+			if tt.encoder != nil && l.GetEncoder() != tt.encoder {
+				t.Errorf("Expected encoder %T, got %T", tt.encoder, l.GetEncoder())
+			}
+			if !strings.Contains(warn.String(), tt.warning) || (tt.warning == "") != (warn.Len() == 0) {
+				t.Errorf("Expected warning %q, got %q", tt.warning, warn.String())
+			}
+			if err := l.Err(); (tt.errorMsg == "") != (err == nil) || (err != nil && !strings.Contains(err.Error(), tt.errorMsg)) {
+				t.Errorf("Expected error %q, got %v", tt.errorMsg, err)
+			}
+		})
+	}
+}
+
+func TestLazyTargetFailureIsReported(t *testing.T) {
+	blocker := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(blocker, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	l := &Logger{binaryName: "app"}
+	var warn bytes.Buffer
+	l.configureFromEnv(envFunc(map[string]string{"XDG_STATE_HOME": blocker}), &warn)
+	if warn.Len() > 0 {
+		t.Fatalf("Expected no warning before the first entry, got %q", warn.String())
+	}
+
+	l.LogMessage(messages.Info, "dropped")
+	if l.IsEnabled() || l.Err() == nil || !strings.Contains(l.Err().Error(), "logging disabled") {
+		t.Errorf("Expected logging disabled with a reason, got enabled %v, err %v", l.IsEnabled(), l.Err())
+	}
+	if !strings.Contains(warn.String(), "logging disabled") {
+		t.Errorf("Expected the reason on the warning output, got %q", warn.String())
+	}
+}
+
+func TestEnvLogLevel(t *testing.T) {
+	target := filepath.Join(t.TempDir(), "app.log")
+	var sinkOut, warn bytes.Buffer
+	l := &Logger{binaryName: "app"}
+	l.configureFromEnv(envFunc(map[string]string{EnvLogLevel: "warning", EnvLogFile: target}), &warn)
+	l.AddSink(NewWriterSink(&sinkOut, nil, messages.SeverityTrace))
+
+	l.LogMessage(messages.Info, "skipped")
+	l.LogMessage(messages.Error, "kept")
+	l.Close()
+	if level, ok := l.GetLevel(); !ok || level != messages.SeverityWarn {
+		t.Errorf("Expected level warn, got %v, %v", level, ok)
+	}
+	file, _ := os.ReadFile(target)
+	for name, got := range map[string]string{"file": string(file), "sink": sinkOut.String()} {
+		if strings.Contains(got, "skipped") || !strings.Contains(got, "kept") {
+			t.Errorf("Expected the %s to get only entries of at least warn, got %q", name, got)
+		}
+	}
+
+	l = &Logger{binaryName: "app"}
+	l.configureFromEnv(envFunc(map[string]string{EnvLogLevel: "loud", EnvLogDisable: "1"}), &warn)
+	if _, ok := l.GetLevel(); ok || !strings.Contains(warn.String(), EnvLogLevel) {
+		t.Errorf("Expected an invalid level to be reported and ignored, got %q", warn.String())
+	}
+}
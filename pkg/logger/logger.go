@@ -1,15 +1,20 @@
 package logger
 
 import (
-	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/jsas4coding/utify/pkg/caller"
+	"github.com/jsas4coding/utify/pkg/field"
 	"github.com/jsas4coding/utify/pkg/messages"
+	"github.com/jsas4coding/utify/pkg/redact"
 )
 
 type LogEntry struct {
@@ -18,21 +23,102 @@ type LogEntry struct {
 	Message   string        `json:"message"`
 	Type      messages.Type `json:"type"`
 	Binary    string        `json:"binary"`
+	// Fields are written as top-level properties after the fixed ones. A
+	// field named like a fixed property is written as "fields.<key>".
+	Fields []field.Field `json:"-"`
+	// Time is the time of the entry at full precision, for encoders with
+	// their own time format.
+	Time time.Time `json:"-"`
+	// Caller is the source location of the message, if it was captured.
+	Caller *caller.Caller `json:"-"`
 }
 
-var (
-	logFile    *os.File
+// MarshalJSON encodes the entry with its fields as top-level properties.
+func (e LogEntry) MarshalJSON() ([]byte, error) {
+	return JSONEncoder{}.Encode(e)
+}
+
+// Logger writes structured JSON log entries to a file target or an
+// arbitrary writer, and fans them out to any added sinks. It is safe for
+// concurrent use, including reconfiguration while other goroutines log. The
+// package-level functions operate on a default Logger.
+type Logger struct {
+	// mu guards the target, the enabled state and the async queue.
+	mu         sync.RWMutex
+	logFile    io.WriteCloser
 	logger     *log.Logger
 	logTarget  string
+	rotation   Rotation
+	encoder    Encoder
 	binaryName string
-	enabled    = true
-)
+	enabled    bool
+	redactor   *redact.Redactor
+	// pending is set while the target is opened lazily, on the first entry.
+	pending bool
+	// err records why the logger is not writing to its configured target.
+	err error
+	// warn receives the reason a lazily opened target could not be used.
+	warn io.Writer
+	// level is the minimum severity written when hasLevel is set.
+	level    messages.Severity
+	hasLevel bool
+
+	sinksMu sync.RWMutex
+	sinks   []Sink
+
+	async   *asyncQueue
+	dropped atomic.Uint64
+}
+
+var std = &Logger{enabled: true}
 
 func init() {
-	binaryName = getBinaryName()
-	// Set a default log target, which is resilient
-	logTarget = fmt.Sprintf("/var/log/%s.log", binaryName)
-	initLogger()
+	std.binaryName = getBinaryName()
+	std.configureFromEnv(os.Getenv, os.Stderr)
+}
+
+// New returns a Logger writing to the given file target. Unlike the default
+// logger it has no fallback: if the target is not writable an error is returned.
+func New(target string) (*Logger, error) {
+	l := &Logger{binaryName: getBinaryName()}
+	if err := l.SetLogTarget(target); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// NewRotating returns a Logger writing to target and rotating it according
+// to r.
+func NewRotating(target string, r Rotation) (*Logger, error) {
+	l := &Logger{binaryName: getBinaryName(), rotation: r}
+	if err := l.SetLogTarget(target); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// NewWriter returns a Logger writing entries to w. A nil writer yields a
+// logger that discards everything.
+func NewWriter(w io.Writer) *Logger {
+	if w == nil {
+		return &Logger{binaryName: getBinaryName()}
+	}
+	return &Logger{
+		logger:     log.New(w, "", 0),
+		binaryName: getBinaryName(),
+		enabled:    true,
+	}
+}
+
+// NewWithSinks returns a Logger without a file target that writes entries
+// only to sinks.
+func NewWithSinks(sinks ...Sink) *Logger {
+	return &Logger{binaryName: getBinaryName(), enabled: true, sinks: sinks}
+}
+
+// Default returns the package-level Logger.
+func Default() *Logger {
+	return std
 }
 
 func getBinaryName() string {
@@ -42,118 +128,470 @@ func getBinaryName() string {
 	return "utify"
 }
 
-// initLogger provides a resilient startup logging mechanism.
-func initLogger() {
-	if !enabled {
+// initLogger opens the target of an enabled logger without an open file.
+// If the target cannot be used logging is disabled, and the reason is kept
+// for Err. l.mu must be held.
+func (l *Logger) initLogger() {
+	l.pending = false
+	if !l.enabled || l.logTarget == "" {
 		return
 	}
 
-	// Try to create log directory if it doesn't exist
-	logDir := filepath.Dir(logTarget)
-	if err := os.MkdirAll(logDir, 0755); err != nil {
-		// If we can't create the directory, fall back to current directory
-		logTarget = fmt.Sprintf("%s.log", binaryName)
-	}
-
-	var err error
-	logFile, err = os.OpenFile(logTarget, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	file, err := l.open(l.logTarget)
 	if err != nil {
-		// Try fallback location in current directory
-		fallbackTarget := fmt.Sprintf("%s.log", binaryName)
-		logFile, err = os.OpenFile(fallbackTarget, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
-		if err != nil {
-			// If we still can't open a log file, disable logging
-			enabled = false
-			logFile = nil
-			logger = nil
-			return
+		l.enabled = false
+		l.err = fmt.Errorf("logging disabled: %w", err)
+		if l.warn != nil {
+			_, _ = fmt.Fprintf(l.warn, "utify: %v\n", l.err)
 		}
-		logTarget = fallbackTarget
+		return
 	}
+	l.logFile = file
+	l.logger = log.New(l.logFile, "", 0)
+}
 
-	logger = log.New(logFile, "", 0)
+// openPending opens a lazily opened target.
+func (l *Logger) openPending() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.pending {
+		l.initLogger()
+	}
 }
 
 // SetLogTarget sets a new log file target. This is a strict function;
 // if the target is not writable, it will return an error.
-func SetLogTarget(target string) error {
-	if logFile != nil {
-		_ = logFile.Close()
-		logFile = nil
-		logger = nil
-	}
+func (l *Logger) SetLogTarget(target string) error {
+	l.Flush()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.setLogTarget(target)
+}
 
-	logDir := filepath.Dir(target)
-	if err := os.MkdirAll(logDir, 0755); err != nil {
-		enabled = false
-		return fmt.Errorf("failed to create log directory for target '%s': %w", target, err)
+// setLogTarget implements SetLogTarget. l.mu must be held.
+func (l *Logger) setLogTarget(target string) error {
+	if l.logFile != nil {
+		_ = l.logFile.Close()
+		l.logFile = nil
+		l.logger = nil
 	}
+	l.pending = false
 
-	newFile, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	newFile, err := l.open(target)
 	if err != nil {
-		enabled = false
-		// Attempt to restore default logger on failure
-		initLogger()
-		return fmt.Errorf("failed to set new log target '%s': %w", target, err)
+		l.enabled = false
+		l.err = err
+		return err
 	}
 
-	logFile = newFile
-	logger = log.New(logFile, "", 0)
-	logTarget = target
-	enabled = true
+	l.logFile = newFile
+	l.logger = log.New(l.logFile, "", 0)
+	l.logTarget = target
+	l.enabled = true
+	l.err = nil
 
 	return nil
 }
 
-func GetLogTarget() string {
-	return logTarget
+// open creates the directory of target and opens it. l.mu must be held.
+func (l *Logger) open(target string) (io.WriteCloser, error) {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory for target '%s': %w", target, err)
+	}
+	file, err := l.openTarget(target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set new log target '%s': %w", target, err)
+	}
+	return file, nil
 }
 
-func SetEnabled(enable bool) {
-	enabled = enable
-	if !enabled && logFile != nil {
-		_ = logFile.Close()
-		logFile = nil
-		logger = nil
-	} else if enabled && logFile == nil {
-		initLogger()
+// openTarget opens target for appending, through a RotatingFile if the
+// logger rotates its files. l.mu must be held.
+func (l *Logger) openTarget(target string) (io.WriteCloser, error) {
+	if l.rotation.IsZero() {
+		return os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
 	}
+	return OpenRotating(target, l.rotation)
 }
 
-func IsEnabled() bool {
-	return enabled
+// SetRotation sets how the log file is rotated and reopens the current
+// target with it. The zero Rotation disables rotation.
+func (l *Logger) SetRotation(r Rotation) error {
+	l.Flush()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rotation = r
+	if l.logFile == nil || l.logTarget == "" {
+		return nil
+	}
+	return l.setLogTarget(l.logTarget)
+}
+
+// GetRotation returns the rotation settings of the logger.
+func (l *Logger) GetRotation() Rotation {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.rotation
+}
+
+// SetEncoder sets how entries are encoded in the log file. Nil restores
+// JSONEncoder.
+func (l *Logger) SetEncoder(enc Encoder) {
+	l.Flush()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.encoder = enc
+}
+
+// GetEncoder returns the encoder of the log file.
+func (l *Logger) GetEncoder() Encoder {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.encoder == nil {
+		return JSONEncoder{}
+	}
+	return l.encoder
+}
+
+// SetRedactor sets the redactor masking secrets in entries before they
+// are written to the log file and the sinks. Nil uses redact.Default.
+func (l *Logger) SetRedactor(r *redact.Redactor) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.redactor = r
+}
+
+// Err returns why the logger is not writing to the target it was configured
+// with, e.g. because the default location could not be created or
+// UTIFY_LOG_FILE was not writable, or nil. A successful SetLogTarget clears
+// it.
+func (l *Logger) Err() error {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.err
+}
+
+// GetLogTarget returns the file target of the logger, or an empty string
+// for writer-backed loggers.
+func (l *Logger) GetLogTarget() string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.logTarget
+}
+
+// SetEnabled enables or disables the logger. Disabling a file-backed logger
+// closes its file; enabling it again reopens the target.
+func (l *Logger) SetEnabled(enable bool) {
+	l.Flush()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.enabled = enable
+	if !l.enabled && l.logFile != nil {
+		_ = l.logFile.Close()
+		l.logFile = nil
+		l.logger = nil
+	} else if l.enabled && l.logger == nil {
+		l.initLogger()
+	}
+}
+
+// SetLevel makes the logger skip entries, for its file and every sink,
+// whose type is less severe than level.
+func (l *Logger) SetLevel(level messages.Severity) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.level = level
+	l.hasLevel = true
+}
+
+// GetLevel returns the minimum severity set by SetLevel or EnvLogLevel,
+// and whether one is set.
+func (l *Logger) GetLevel() (messages.Severity, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.level, l.hasLevel
+}
+
+// IsEnabled reports whether the logger writes entries.
+func (l *Logger) IsEnabled() bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.enabled
+}
+
+// AddSink adds a sink receiving every entry the logger writes. The logger
+// closes it on Close.
+func (l *Logger) AddSink(s Sink) {
+	l.sinksMu.Lock()
+	defer l.sinksMu.Unlock()
+	l.sinks = append(l.sinks, s)
+}
+
+// RemoveSink removes s without closing it and reports whether it was added.
+func (l *Logger) RemoveSink(s Sink) bool {
+	l.sinksMu.Lock()
+	defer l.sinksMu.Unlock()
+	for i, sink := range l.sinks {
+		if sink == s {
+			l.sinks = append(l.sinks[:i:i], l.sinks[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Sinks returns the sinks of the logger.
+func (l *Logger) Sinks() []Sink {
+	l.sinksMu.RLock()
+	defer l.sinksMu.RUnlock()
+	return append([]Sink(nil), l.sinks...)
+}
+
+// EnableAsync makes LogMessage queue entries for a background writer
+// instead of writing them itself. Flush or Close must be called before the
+// program exits to deliver queued entries; utify's WithExit path does so.
+func (l *Logger) EnableAsync(a Async) {
+	l.DisableAsync()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.async = startAsync(l, a)
+}
+
+// DisableAsync writes the queued entries and switches back to synchronous
+// logging.
+func (l *Logger) DisableAsync() {
+	l.mu.Lock()
+	q := l.async
+	l.async = nil
+	l.mu.Unlock()
+
+	// The background writer takes l.mu to write, so it is stopped without
+	// holding the lock.
+	if q != nil {
+		q.stop()
+	}
+}
+
+// IsAsync reports whether the logger writes entries in the background.
+func (l *Logger) IsAsync() bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.async != nil
 }
 
-func LogMessage(msgType messages.Type, message string) {
-	if !enabled || logger == nil {
+// Flush waits until every entry logged before the call has been written.
+func (l *Logger) Flush() {
+	l.mu.RLock()
+	q := l.async
+	l.mu.RUnlock()
+	if q != nil {
+		q.flush()
+	}
+}
+
+// Dropped returns the number of entries discarded because the async queue
+// was full.
+func (l *Logger) Dropped() uint64 {
+	return l.dropped.Load()
+}
+
+// LogMessage writes a structured entry for message and its fields to the
+// log file and every sink.
+func (l *Logger) LogMessage(msgType messages.Type, message string, fields ...field.Field) {
+	l.WriteEntry(LogEntry{Message: message, Type: msgType, Fields: fields})
+}
+
+// WriteEntry writes e to the log file and every sink. An empty Time,
+// Timestamp, Level or Binary is filled in.
+func (l *Logger) WriteEntry(entry LogEntry) {
+	l.mu.RLock()
+	q := l.async
+	pending := l.pending
+	active := l.enabled && (l.logger != nil || pending || l.hasSinks())
+	active = active && (!l.hasLevel || messages.SeverityOf(entry.Type) >= l.level)
+	r := l.redactor
+	l.mu.RUnlock()
+	if !active {
 		return
 	}
+	if pending {
+		l.openPending()
+	}
 
-	entry := LogEntry{
-		Timestamp: time.Now().Format(time.RFC3339),
-		Level:     strings.ToUpper(string(msgType)),
-		Message:   message,
-		Type:      msgType,
-		Binary:    binaryName,
+	if r == nil {
+		r = redact.Default()
 	}
+	entry.Message = r.String(entry.Message)
+	entry.Fields = r.Fields(entry.Fields)
 
-	jsonData, err := json.Marshal(entry)
-	if err != nil {
-		logger.Printf("[%s] %s", entry.Level, message)
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	if entry.Timestamp == "" {
+		entry.Timestamp = entry.Time.Format(time.RFC3339)
+	}
+	if entry.Level == "" {
+		entry.Level = strings.ToUpper(string(entry.Type))
+	}
+	if entry.Binary == "" {
+		entry.Binary = l.binaryName
+	}
+	if q != nil && q.enqueue(entry) {
 		return
 	}
 
-	logger.Println(string(jsonData))
+	l.writeSinks(entry)
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.enabled && l.logger != nil {
+		l.logger.Println(string(encodeLine(l.encoder, entry)))
+	}
 }
 
-func LogOnly(msgType messages.Type, message string) {
-	LogMessage(msgType, message)
+func (l *Logger) hasSinks() bool {
+	l.sinksMu.RLock()
+	defer l.sinksMu.RUnlock()
+	return len(l.sinks) > 0
 }
 
-func Close() {
-	if logFile != nil {
-		_ = logFile.Close()
-		logFile = nil
-		logger = nil
+// writeBatch writes already encoded lines to the log file.
+func (l *Logger) writeBatch(lines []byte) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.enabled && l.logger != nil {
+		_, _ = l.logger.Writer().Write(lines)
 	}
-}
\ No newline at end of file
+}
+
+func (l *Logger) writeSinks(e LogEntry) {
+	for _, s := range l.Sinks() {
+		_ = s.Write(e)
+	}
+}
+
+// encodeLine encodes e with enc, or as JSON if enc is nil, falling back to
+// plain text if encoding fails.
+func encodeLine(enc Encoder, e LogEntry) []byte {
+	if enc == nil {
+		enc = JSONEncoder{}
+	}
+	data, err := enc.Encode(e)
+	if err != nil {
+		return []byte(fmt.Sprintf("[%s] %s", e.Level, e.Message))
+	}
+	return data
+}
+
+// LogOnly writes a structured entry without any console output.
+func (l *Logger) LogOnly(msgType messages.Type, message string, fields ...field.Field) {
+	l.LogMessage(msgType, message, fields...)
+}
+
+// Close writes any queued entries, closes the log file, if the logger owns
+// one, and removes and closes its sinks. The logger is synchronous again
+// afterwards.
+func (l *Logger) Close() {
+	l.DisableAsync()
+	l.mu.Lock()
+	l.pending = false
+	if l.logFile != nil {
+		_ = l.logFile.Close()
+		l.logFile = nil
+		l.logger = nil
+	}
+	l.mu.Unlock()
+
+	l.sinksMu.Lock()
+	sinks := l.sinks
+	l.sinks = nil
+	l.sinksMu.Unlock()
+	for _, s := range sinks {
+		_ = s.Close()
+	}
+}
+
+// SetLogTarget sets a new log file target. This is a strict function;
+// if the target is not writable, it will return an error.
+func SetLogTarget(target string) error {
+	return std.SetLogTarget(target)
+}
+
+// Err returns why the default logger is not writing to the target it was
+// configured with, or nil. See Logger.Err.
+func Err() error {
+	return std.Err()
+}
+
+func GetLogTarget() string {
+	return std.GetLogTarget()
+}
+
+// SetRotation sets how the default log file is rotated.
+func SetRotation(r Rotation) error {
+	return std.SetRotation(r)
+}
+
+// GetRotation returns the rotation settings of the default logger.
+func GetRotation() Rotation {
+	return std.GetRotation()
+}
+
+// SetEncoder sets how entries are encoded in the default log file.
+func SetEncoder(enc Encoder) {
+	std.SetEncoder(enc)
+}
+
+// GetEncoder returns the encoder of the default log file.
+func GetEncoder() Encoder {
+	return std.GetEncoder()
+}
+
+func SetEnabled(enable bool) {
+	std.SetEnabled(enable)
+}
+
+func IsEnabled() bool {
+	return std.IsEnabled()
+}
+
+// AddSink adds a sink to the default logger.
+func AddSink(s Sink) {
+	std.AddSink(s)
+}
+
+// RemoveSink removes a sink from the default logger without closing it.
+func RemoveSink(s Sink) bool {
+	return std.RemoveSink(s)
+}
+
+// EnableAsync makes the default logger write entries in the background.
+func EnableAsync(a Async) {
+	std.EnableAsync(a)
+}
+
+// DisableAsync makes the default logger synchronous again.
+func DisableAsync() {
+	std.DisableAsync()
+}
+
+// Flush waits until the default logger has written every queued entry.
+func Flush() {
+	std.Flush()
+}
+
+// Dropped returns the number of entries the default logger discarded.
+func Dropped() uint64 {
+	return std.Dropped()
+}
+
+func LogMessage(msgType messages.Type, message string, fields ...field.Field) {
+	std.LogMessage(msgType, message, fields...)
+}
+
+func LogOnly(msgType messages.Type, message string, fields ...field.Field) {
+	std.LogOnly(msgType, message, fields...)
+}
+
+func Close() {
+	std.Close()
+}
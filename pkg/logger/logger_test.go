@@ -1,14 +1,20 @@
 package logger
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/jsas4coding/utify/internal/tests"
+	"github.com/jsas4coding/utify/pkg/field"
 	"github.com/jsas4coding/utify/pkg/messages"
+	"github.com/jsas4coding/utify/pkg/redact"
 )
 
 func TestSetLogTarget(t *testing.T) {
@@ -130,12 +136,19 @@ func TestLogFilePermissions(t *testing.T) {
 }
 
 func TestLogToInvalidTarget(t *testing.T) {
-	// Attempt to log to a directory that doesn't exist and we can't create
-	invalidTarget := "/nonexistent/dir/test.log"
-	err := SetLogTarget(invalidTarget)
+	// A directory cannot be created below a regular file, even as root
+	blocker := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(blocker, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	l := NewWriter(nil)
+	err := l.SetLogTarget(filepath.Join(blocker, "dir", "test.log"))
 	if err == nil {
 		t.Error("Expected an error when setting an invalid log target, but got nil")
 	}
+	if l.Err() != err || l.IsEnabled() {
+		t.Errorf("Expected the logger disabled with Err %v, got %v", err, l.Err())
+	}
 }
 
 func TestLogMessageWithDisabledLogging(t *testing.T) {
@@ -180,4 +193,153 @@ func TestGetBinaryName(t *testing.T) {
 	if name != "utify" {
 		t.Errorf("Expected fallback binary name 'utify', got %q", name)
 	}
-}
\ No newline at end of file
+}
+func TestNewWriterLogger(t *testing.T) {
+	var buf strings.Builder
+	l := NewWriter(&buf)
+
+	l.LogMessage(messages.Info, "Writer message")
+	l.SetEnabled(false)
+	l.LogMessage(messages.Info, "Dropped message")
+	l.SetEnabled(true)
+	l.LogOnly(messages.Warning, "Re-enabled message")
+
+	content := buf.String()
+	if !strings.Contains(content, "Writer message") || !strings.Contains(content, "Re-enabled message") {
+		t.Errorf("Expected writer to receive entries, got %q", content)
+	}
+	if strings.Contains(content, "Dropped message") {
+		t.Error("Disabled writer logger should not write entries")
+	}
+	if l.GetLogTarget() != "" {
+		t.Errorf("Expected empty target for writer logger, got %q", l.GetLogTarget())
+	}
+}
+
+func TestNewFileLogger(t *testing.T) {
+	tests.CreateDataDir(t)
+	tempFile := filepath.Join(tests.DataDir, "test_instance.log")
+	defer func() { _ = os.Remove(tempFile) }()
+
+	l, err := New(tempFile)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	l.LogMessage(messages.Success, "Instance message")
+	l.Close()
+
+	content, err := os.ReadFile(tempFile)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	if !strings.Contains(string(content), "Instance message") {
+		t.Error("Log file should contain the instance message")
+	}
+	if Default() == l {
+		t.Error("New should not return the default logger")
+	}
+}
+
+func TestLogMessageWithFields(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewWriter(&buf)
+
+	l.LogMessage(messages.Info, "deployed",
+		field.F("service", "api"),
+		field.F("took", 1500*time.Millisecond),
+		field.F("size", field.Bytes(2048)),
+		field.F("err", errors.New("partial")),
+		field.F("message", "shadowed"))
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to parse log entry %q: %v", buf.String(), err)
+	}
+
+	expected := map[string]any{
+		"message":        "deployed",
+		"type":           "info",
+		"service":        "api",
+		"took":           "1.5s",
+		"size":           float64(2048),
+		"err":            "partial",
+		"fields.message": "shadowed",
+	}
+	for k, v := range expected {
+		if entry[k] != v {
+			t.Errorf("Expected %s to be %v, got %v", k, v, entry[k])
+		}
+	}
+}
+
+func TestLogEntryWithoutFields(t *testing.T) {
+	data, err := json.Marshal(LogEntry{Level: "INFO", Message: "plain", Type: messages.Info})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	expected := `{"timestamp":"","level":"INFO","message":"plain","type":"info","binary":""}`
+	if string(data) != expected {
+		t.Errorf("Expected %s, got %s", expected, data)
+	}
+}
+
+func TestConcurrentLogAndReconfigure(t *testing.T) {
+	dir := t.TempDir()
+	l, err := New(filepath.Join(dir, "a.log"))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer l.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 500; j++ {
+				l.LogMessage(messages.Info, "entry", field.F("n", j))
+			}
+		}()
+	}
+
+	for i := 0; i < 20; i++ {
+		_ = l.SetLogTarget(filepath.Join(dir, []string{"a.log", "b.log"}[i%2]))
+		l.SetEnabled(i%3 != 0)
+		_ = l.SetRotation(Rotation{MaxSize: int64(i%2) << 16})
+		if i%4 == 0 {
+			l.EnableAsync(Async{Policy: OverflowPolicy(i % 3)})
+		} else {
+			l.DisableAsync()
+		}
+		sink := NewMemorySink(nil, messages.SeverityTrace)
+		l.AddSink(sink)
+		l.RemoveSink(sink)
+		_ = l.GetLogTarget()
+		_ = l.IsEnabled()
+	}
+	wg.Wait()
+}
+
+func TestLogMessageRedactsSecrets(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewWriter(&buf)
+	sink := NewMemorySink(nil, messages.SeverityTrace)
+	l.AddSink(sink)
+
+	l.LogMessage(messages.Info, "dial postgres://app:s3cr3t@db", field.F("authorization", "Bearer abc"))
+	for _, s := range []string{buf.String(), sink.String()} {
+		if strings.Contains(s, "s3cr3t") || strings.Contains(s, "abc") || !strings.Contains(s, redact.Mask) {
+			t.Errorf("Expected secrets masked, got %q", s)
+		}
+	}
+
+	r := redact.New()
+	r.SetKeys()
+	r.SetRules(nil)
+	l.SetRedactor(r)
+	buf.Reset()
+	l.LogMessage(messages.Info, "password=plain")
+	if !strings.Contains(buf.String(), "password=plain") {
+		t.Errorf("Expected the logger's own redactor to be used, got %q", buf.String())
+	}
+}
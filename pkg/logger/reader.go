@@ -0,0 +1,341 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jsas4coding/utify/pkg/caller"
+	"github.com/jsas4coding/utify/pkg/field"
+	"github.com/jsas4coding/utify/pkg/messages"
+)
+
+// ErrMalformedEntry is returned by ParseEntry for lines that are not JSON
+// log entries.
+var ErrMalformedEntry = errors.New("malformed log entry")
+
+// fixedKeys are the properties ParseEntry maps to LogEntry fields.
+var fixedKeys = []string{"timestamp", "level", "message", "type", "binary", "caller"}
+
+// ParseEntry parses a line written by JSONEncoder with the default field
+// names. Other properties become Fields in their original order, with the
+// "fields." prefix of colliding keys removed; integral numbers are int64
+// values. Time is set if the timestamp is in RFC 3339 format.
+func ParseEntry(line []byte) (LogEntry, error) {
+	var e LogEntry
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber()
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return e, ErrMalformedEntry
+	}
+
+	hasMessage := false
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return e, fmt.Errorf("%w: %v", ErrMalformedEntry, err)
+		}
+		key, _ := tok.(string)
+		var value any
+		if err := dec.Decode(&value); err != nil {
+			return e, fmt.Errorf("%w: %v", ErrMalformedEntry, err)
+		}
+
+		s, isString := value.(string)
+		switch {
+		case key == "timestamp" && isString:
+			e.Timestamp = s
+			e.Time, _ = time.Parse(time.RFC3339Nano, s)
+		case key == "level" && isString:
+			e.Level = s
+		case key == "message" && isString:
+			e.Message = s
+			hasMessage = true
+		case key == "type" && isString:
+			e.Type = messages.Type(s)
+		case key == "binary" && isString:
+			e.Binary = s
+		case key == "caller":
+			e.Caller = parseCaller(value)
+		default:
+			if name, found := strings.CutPrefix(key, "fields."); found && slices.Contains(fixedKeys, name) {
+				key = name
+			}
+			e.Fields = append(e.Fields, field.F(key, number(value)))
+		}
+	}
+	if !hasMessage {
+		return e, fmt.Errorf("%w: no message", ErrMalformedEntry)
+	}
+	if e.Type == "" && e.Level != "" {
+		e.Type = messages.Type(strings.ToLower(e.Level))
+	}
+	return e, nil
+}
+
+// number converts a json.Number to an int64 if it is integral and to a
+// float64 otherwise.
+func number(v any) any {
+	n, ok := v.(json.Number)
+	if !ok {
+		return v
+	}
+	if i, err := n.Int64(); err == nil {
+		return i
+	}
+	f, _ := n.Float64()
+	return f
+}
+
+func parseCaller(v any) *caller.Caller {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil
+	}
+	var c caller.Caller
+	c.File, _ = m["file"].(string)
+	c.Function, _ = m["function"].(string)
+	if n, ok := m["line"].(json.Number); ok {
+		line, _ := n.Int64()
+		c.Line = int(line)
+	}
+	return &c
+}
+
+// Reader reads log entries from a stream of JSON lines, as written by the
+// JSON encoder. Lines that are not entries, including those of the other
+// encoders, are skipped and counted by Skipped:
+//
+//	r := logger.NewReader(file)
+//	for r.Next() {
+//		e := r.Entry()
+//	}
+//	if err := r.Err(); err != nil {
+//		...
+//	}
+type Reader struct {
+	r       *bufio.Reader
+	entry   LogEntry
+	err     error
+	skipped int
+}
+
+// NewReader returns a Reader reading entries from r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReader(r)}
+}
+
+// Next advances to the next entry and reports whether there is one.
+func (r *Reader) Next() bool {
+	for r.err == nil {
+		line, err := r.r.ReadBytes('\n')
+		if err != nil {
+			r.err = err
+		}
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		e, perr := ParseEntry(line)
+		if perr != nil {
+			r.skipped++
+			continue
+		}
+		r.entry = e
+		return true
+	}
+	return false
+}
+
+// Entry returns the entry read by the last successful call to Next.
+func (r *Reader) Entry() LogEntry {
+	return r.entry
+}
+
+// Err returns the first error other than io.EOF encountered while reading.
+func (r *Reader) Err() error {
+	if errors.Is(r.err, io.EOF) {
+		return nil
+	}
+	return r.err
+}
+
+// Skipped returns the number of non-empty lines that were not entries.
+func (r *Reader) Skipped() int {
+	return r.skipped
+}
+
+// Filter selects log entries. Zero fields match every entry.
+type Filter struct {
+	// Types lists the message types to keep.
+	Types []messages.Type
+	// Since and Until bound the entry time, inclusively. Entries without a
+	// parsable time do not match a bounded filter.
+	Since, Until time.Time
+	// Binary keeps entries written by the named binary.
+	Binary string
+	// Text keeps entries whose message or field values contain it.
+	Text string
+	// Pattern keeps entries whose message or fields, rendered as
+	// key=value pairs, match it.
+	Pattern *regexp.Regexp
+}
+
+// Match reports whether e is selected by the filter.
+func (f Filter) Match(e LogEntry) bool {
+	if len(f.Types) > 0 && !slices.Contains(f.Types, e.Type) {
+		return false
+	}
+	if !f.Since.IsZero() || !f.Until.IsZero() {
+		if e.Time.IsZero() || (!f.Since.IsZero() && e.Time.Before(f.Since)) ||
+			(!f.Until.IsZero() && e.Time.After(f.Until)) {
+			return false
+		}
+	}
+	if f.Binary != "" && e.Binary != f.Binary {
+		return false
+	}
+	if f.Text == "" && f.Pattern == nil {
+		return true
+	}
+	text := e.Message
+	if len(e.Fields) > 0 {
+		text += " " + field.String(e.Fields)
+	}
+	if f.Text != "" && !strings.Contains(text, f.Text) {
+		return false
+	}
+	return f.Pattern == nil || f.Pattern.MatchString(text)
+}
+
+// FollowOptions configures Follow.
+type FollowOptions struct {
+	// FromStart reads the entries already in the file before following it.
+	FromStart bool
+	// Interval is how often the file is checked for new data. Zero uses
+	// 250ms.
+	Interval time.Duration
+}
+
+// Follow calls fn for every entry appended to the log file at path, like
+// tail -f, until ctx is done or fn returns an error, which is returned.
+// The file is reopened from the start when it is rotated or truncated.
+func Follow(ctx context.Context, path string, opts FollowOptions, fn func(LogEntry) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+	if !opts.FromStart {
+		if _, err := file.Seek(0, io.SeekEnd); err != nil {
+			return err
+		}
+	}
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = 250 * time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	br := bufio.NewReader(file)
+	var partial []byte
+	emit := func(line []byte) error {
+		if e, err := ParseEntry(bytes.TrimSpace(line)); err == nil {
+			return fn(e)
+		}
+		return nil
+	}
+	// drain passes the complete lines up to the end of the file to fn
+	drain := func() error {
+		for {
+			line, err := br.ReadBytes('\n')
+			if err == nil {
+				line = append(partial, line...)
+				partial = nil
+				if err := emit(line); err != nil {
+					return err
+				}
+				continue
+			}
+			if !errors.Is(err, io.EOF) {
+				return err
+			}
+			// Keep an incomplete last line until the rest is written
+			partial = append(partial, line...)
+			return nil
+		}
+	}
+
+	for {
+		if err := drain(); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		reopened, err := reopenIfReplaced(file, path)
+		if err != nil {
+			return err
+		}
+		switch {
+		case reopened != nil:
+			// Read what was written to the old file before it was replaced
+			err := drain()
+			if err == nil && len(partial) > 0 {
+				err = emit(partial)
+			}
+			if err != nil {
+				_ = reopened.Close()
+				return err
+			}
+			_ = file.Close()
+			file = reopened
+			br.Reset(file)
+			partial = nil
+		case truncated(file):
+			if _, err := file.Seek(0, io.SeekStart); err != nil {
+				return err
+			}
+			br.Reset(file)
+			partial = nil
+		}
+	}
+}
+
+// reopenIfReplaced opens path if it no longer refers to file, e.g. after
+// rotation. It returns nil while path is missing or unchanged.
+func reopenIfReplaced(file *os.File, path string) (*os.File, error) {
+	current, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	latest, err := os.Stat(path)
+	if err != nil || os.SameFile(current, latest) {
+		return nil, nil
+	}
+	return os.Open(path)
+}
+
+// truncated reports whether file is shorter than the read position.
+func truncated(file *os.File) bool {
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	pos, err := file.Seek(0, io.SeekCurrent)
+	return err == nil && info.Size() < pos
+}
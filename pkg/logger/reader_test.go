@@ -0,0 +1,188 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jsas4coding/utify/pkg/caller"
+	"github.com/jsas4coding/utify/pkg/field"
+	"github.com/jsas4coding/utify/pkg/messages"
+)
+
+func TestParseEntryRoundTrip(t *testing.T) {
+	at := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+	in := LogEntry{
+		Timestamp: at.Format(time.RFC3339),
+		Level:     "WARNING",
+		Message:   "disk almost full",
+		Type:      messages.Warning,
+		Binary:    "app",
+		Caller:    &caller.Caller{File: "/src/app/main.go", Line: 12, Function: "main.main"},
+		Fields: []field.Field{
+			field.F("free", field.Bytes(2048)),
+			field.F("ratio", 0.5),
+			field.F("message", "shadowed"),
+			field.F("tags", []string{"a"}),
+		},
+	}
+	line, err := in.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON failed: %v", err)
+	}
+
+	out, err := ParseEntry(line)
+	if err != nil {
+		t.Fatalf("ParseEntry failed: %v", err)
+	}
+	if !out.Time.Equal(at) || out.Message != in.Message || out.Type != in.Type || out.Level != in.Level ||
+		out.Binary != in.Binary || *out.Caller != *in.Caller {
+		t.Errorf("Unexpected entry %+v", out)
+	}
+	expected := []field.Field{
+		field.F("free", int64(2048)),
+		field.F("ratio", 0.5),
+		field.F("message", "shadowed"),
+		field.F("tags", []any{"a"}),
+	}
+	if !reflect.DeepEqual(out.Fields, expected) {
+		t.Errorf("Expected fields %v, got %v", expected, out.Fields)
+	}
+
+	again, _ := out.MarshalJSON()
+	if !bytes.Equal(again, line) {
+		t.Errorf("Expected a parsed entry to encode identically:\n%s\n%s", line, again)
+	}
+}
+
+func TestParseEntryMalformed(t *testing.T) {
+	for _, line := range []string{"", "not json", "[1]", `{"level":"INFO"}`, `{"message":"x"`} {
+		if _, err := ParseEntry([]byte(line)); !errors.Is(err, ErrMalformedEntry) {
+			t.Errorf("ParseEntry(%q) = %v, expected ErrMalformedEntry", line, err)
+		}
+	}
+	e, err := ParseEntry([]byte(`{"level":"ERROR","message":"no type"}`))
+	if err != nil || e.Type != messages.Error {
+		t.Errorf("Expected the type derived from the level, got %q, %v", e.Type, err)
+	}
+}
+
+func TestReader(t *testing.T) {
+	input := `{"message":"one","type":"info"}
+
+[ERROR] fallback line
+{"message":"two","type":"error"}
+{"message":"no newline","type":"debug"}`
+	r := NewReader(strings.NewReader(input))
+
+	var messages []string
+	for r.Next() {
+		messages = append(messages, r.Entry().Message)
+	}
+	if r.Err() != nil {
+		t.Fatalf("Unexpected error: %v", r.Err())
+	}
+	if !reflect.DeepEqual(messages, []string{"one", "two", "no newline"}) || r.Skipped() != 1 {
+		t.Errorf("Expected three entries and one skipped line, got %q and %d", messages, r.Skipped())
+	}
+}
+
+func TestFilter(t *testing.T) {
+	at := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	e := LogEntry{Time: at, Message: "upload failed", Type: messages.Error, Binary: "app",
+		Fields: []field.Field{field.F("host", "db-1")}}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		match  bool
+	}{
+		{"zero", Filter{}, true},
+		{"type", Filter{Types: []messages.Type{messages.Warning, messages.Error}}, true},
+		{"other type", Filter{Types: []messages.Type{messages.Info}}, false},
+		{"since", Filter{Since: at}, true},
+		{"after since", Filter{Since: at.Add(time.Second)}, false},
+		{"until", Filter{Until: at.Add(-time.Second)}, false},
+		{"binary", Filter{Binary: "other"}, false},
+		{"text in field", Filter{Text: "db-1"}, true},
+		{"missing text", Filter{Text: "timeout"}, false},
+		{"pattern", Filter{Pattern: regexp.MustCompile(`^upload .* host=db-\d$`)}, true},
+		{"text and pattern", Filter{Text: "upload", Pattern: regexp.MustCompile(`host=db-2`)}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.filter.Match(e); got != tt.match {
+			t.Errorf("%s: Match = %v, expected %v", tt.name, got, tt.match)
+		}
+	}
+	if (Filter{Since: at}).Match(LogEntry{Message: "no time"}) {
+		t.Error("Expected entries without a time to fail a time range")
+	}
+}
+
+func TestFollow(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, []byte(`{"message":"old","type":"info"}`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	l, err := New(path)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer l.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	received := make(chan string)
+	done := make(chan error, 1)
+	go func() {
+		done <- Follow(ctx, path, FollowOptions{FromStart: true, Interval: 5 * time.Millisecond}, func(e LogEntry) error {
+			received <- e.Message
+			return nil
+		})
+	}()
+	next := func() string {
+		select {
+		case m := <-received:
+			return m
+		case <-ctx.Done():
+			t.Fatal("Timed out waiting for an entry")
+			return ""
+		}
+	}
+
+	if m := next(); m != "old" {
+		t.Errorf("Expected the existing entry first, got %q", m)
+	}
+	l.LogMessage(messages.Info, "appended")
+	if m := next(); m != "appended" {
+		t.Errorf("Expected the appended entry, got %q", m)
+	}
+
+	// Replace the file, as rotation does
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	l.LogMessage(messages.Info, "late")
+	if err := l.SetLogTarget(path); err != nil {
+		t.Fatal(err)
+	}
+	l.LogMessage(messages.Info, "rotated")
+	if m := next(); m != "late" {
+		t.Errorf("Expected the rest of the old file first, got %q", m)
+	}
+	if m := next(); m != "rotated" {
+		t.Errorf("Expected the entry of the new file, got %q", m)
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,408 @@
+package logger
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Rotation intervals for Rotation.Interval.
+const (
+	Hourly = time.Hour
+	Daily  = 24 * time.Hour
+)
+
+// DefaultRotationTimeFormat is the time format used in rotated file names.
+const DefaultRotationTimeFormat = "2006-01-02T15-04-05"
+
+// Rotation configures when a log file is rotated and how long rotated files
+// are kept. The zero Rotation never rotates.
+type Rotation struct {
+	// MaxSize rotates the file before a write would make it larger than
+	// MaxSize bytes. Zero disables size-based rotation.
+	MaxSize int64
+
+	// Interval rotates the file when an interval boundary passes. Daily
+	// rotates at local midnight, other intervals at multiples of the
+	// interval, e.g. on the hour for Hourly. Zero disables it.
+	Interval time.Duration
+
+	// TimeFormat formats the rotation time in rotated file names: app.log
+	// becomes app-<time>.log. A file rotated because an interval passed is
+	// named after the start of that interval instead, e.g. the previous
+	// day for Daily. Empty uses DefaultRotationTimeFormat.
+	TimeFormat string
+
+	// Compress gzips rotated files.
+	Compress bool
+
+	// MaxBackups is the number of rotated files kept. Zero keeps all.
+	MaxBackups int
+
+	// MaxAge removes rotated files older than MaxAge. Zero keeps all.
+	MaxAge time.Duration
+}
+
+// IsZero reports whether r never rotates.
+func (r Rotation) IsZero() bool {
+	return r.MaxSize == 0 && r.Interval == 0
+}
+
+func (r Rotation) timeFormat() string {
+	if r.TimeFormat != "" {
+		return r.TimeFormat
+	}
+	return DefaultRotationTimeFormat
+}
+
+// RotatingFile is an io.WriteCloser appending to a file and rotating it
+// according to a Rotation. It is safe for concurrent use; every write goes
+// entirely to one file, so no entry is split or lost across a rotation.
+// Compression and retention run in the background after a rotation.
+type RotatingFile struct {
+	path     string
+	rotation Rotation
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	deadline time.Time
+
+	cleanup sync.WaitGroup
+	cleanMu sync.Mutex
+
+	now    func() time.Time
+	rename func(oldpath, newpath string) error
+}
+
+// OpenRotating opens path for appending, creating it and its directory if
+// needed.
+func OpenRotating(path string, r Rotation) (*RotatingFile, error) {
+	f := &RotatingFile{path: path, rotation: r, now: time.Now, rename: os.Rename}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory for target '%s': %w", path, err)
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Path returns the path of the active file.
+func (f *RotatingFile) Path() string {
+	return f.path
+}
+
+// open opens the active file. An existing file keeps its size and, for
+// interval rotation, the period of its last modification.
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	f.file = file
+	f.size = info.Size()
+	start := f.now()
+	if f.size > 0 {
+		start = info.ModTime()
+	}
+	f.deadline = nextBoundary(start, f.rotation.Interval)
+	return nil
+}
+
+// Write appends p, rotating first if the size limit or interval requires it.
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+	var rotateErr error
+	if f.needsRotation(int64(len(p))) {
+		rotateErr = f.rotate()
+		if f.file == nil {
+			return 0, rotateErr
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	if err == nil {
+		err = rotateErr
+	}
+	return n, err
+}
+
+// needsRotation reports whether writing n bytes requires a rotation. An
+// empty file is never rotated; a passed deadline moves to the next
+// boundary instead. f.mu must be held.
+func (f *RotatingFile) needsRotation(n int64) bool {
+	if f.size == 0 {
+		if !f.deadline.IsZero() && !f.now().Before(f.deadline) {
+			f.deadline = nextBoundary(f.now(), f.rotation.Interval)
+		}
+		return false
+	}
+	if f.rotation.MaxSize > 0 && f.size+n > f.rotation.MaxSize {
+		return true
+	}
+	return !f.deadline.IsZero() && !f.now().Before(f.deadline)
+}
+
+// Rotate rotates the file immediately.
+func (f *RotatingFile) Rotate() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return os.ErrClosed
+	}
+	return f.rotate()
+}
+
+// rotate renames the active file and opens a new one. If the rename fails,
+// the active file is reopened and the error returned; f.file is only nil
+// when no file could be opened. f.mu must be held.
+func (f *RotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	f.file = nil
+
+	t := f.now()
+	if !f.deadline.IsZero() && !t.Before(f.deadline) {
+		t = periodStart(f.deadline, f.rotation.Interval)
+	}
+	rotated := f.rotatedName(t)
+	if err := f.rename(f.path, rotated); err != nil && !errors.Is(err, os.ErrNotExist) {
+		// Keep logging to the old file rather than losing entries.
+		if openErr := f.open(); openErr != nil {
+			return openErr
+		}
+		return fmt.Errorf("failed to rotate log file '%s': %w", f.path, err)
+	}
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	f.cleanup.Add(1)
+	go func() {
+		defer f.cleanup.Done()
+		f.postRotate(rotated)
+	}()
+	return nil
+}
+
+// rotatedName returns an unused name for a file rotated at t.
+func (f *RotatingFile) rotatedName(t time.Time) string {
+	dir, base, ext := splitPath(f.path)
+	name := filepath.Join(dir, base+"-"+t.Format(f.rotation.timeFormat())+ext)
+	candidate := name
+	for i := 1; exists(candidate) || exists(candidate+".gz"); i++ {
+		candidate = fmt.Sprintf("%s.%d", name, i)
+	}
+	return candidate
+}
+
+// postRotate compresses a rotated file and applies retention.
+func (f *RotatingFile) postRotate(rotated string) {
+	f.cleanMu.Lock()
+	defer f.cleanMu.Unlock()
+
+	if f.rotation.Compress {
+		_ = compressFile(rotated)
+	}
+	f.removeExpired()
+}
+
+// removeExpired deletes rotated files beyond MaxBackups or older than MaxAge.
+func (f *RotatingFile) removeExpired() {
+	if f.rotation.MaxBackups <= 0 && f.rotation.MaxAge <= 0 {
+		return
+	}
+
+	backups, err := f.backups()
+	if err != nil {
+		return
+	}
+	cutoff := f.now().Add(-f.rotation.MaxAge)
+	for i, b := range backups {
+		tooMany := f.rotation.MaxBackups > 0 && i >= f.rotation.MaxBackups
+		tooOld := f.rotation.MaxAge > 0 && b.modTime.Before(cutoff)
+		if tooMany || tooOld {
+			_ = os.Remove(b.path)
+		}
+	}
+}
+
+type backup struct {
+	path    string
+	modTime time.Time
+}
+
+// backups returns the rotated files of f, newest first.
+func (f *RotatingFile) backups() ([]backup, error) {
+	dir, base, ext := splitPath(f.path)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []backup
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !f.isRotatedName(name, base, ext) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backup{path: filepath.Join(dir, name), modTime: info.ModTime()})
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		if backups[i].modTime.Equal(backups[j].modTime) {
+			return backups[i].path > backups[j].path
+		}
+		return backups[i].modTime.After(backups[j].modTime)
+	})
+	return backups, nil
+}
+
+// isRotatedName reports whether name is one rotatedName produces for the
+// file base+ext: base-<time>ext, optionally followed by .N and .gz.
+func (f *RotatingFile) isRotatedName(name, base, ext string) bool {
+	name = strings.TrimSuffix(name, ".gz")
+	if f.isRotatedStem(name, base, ext) {
+		return true
+	}
+	i := strings.LastIndexByte(name, '.')
+	if i < 0 || !isDigits(name[i+1:]) {
+		return false
+	}
+	return f.isRotatedStem(name[:i], base, ext)
+}
+
+func (f *RotatingFile) isRotatedStem(name, base, ext string) bool {
+	if !strings.HasPrefix(name, base+"-") || !strings.HasSuffix(name, ext) ||
+		len(name) < len(base)+1+len(ext) {
+		return false
+	}
+	stamp := name[len(base)+1 : len(name)-len(ext)]
+	_, err := time.ParseInLocation(f.rotation.timeFormat(), stamp, time.Local)
+	return err == nil
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Close closes the active file and waits for background compression and
+// retention to finish.
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	var err error
+	if f.file != nil {
+		err = f.file.Close()
+		f.file = nil
+	}
+	f.mu.Unlock()
+
+	f.cleanup.Wait()
+	return err
+}
+
+// nextBoundary returns the first interval boundary after t, or the zero
+// time when interval is zero.
+func nextBoundary(t time.Time, interval time.Duration) time.Time {
+	switch {
+	case interval <= 0:
+		return time.Time{}
+	case interval == Daily:
+		y, m, d := t.Date()
+		return time.Date(y, m, d+1, 0, 0, 0, 0, t.Location())
+	default:
+		return t.Truncate(interval).Add(interval)
+	}
+}
+
+// periodStart returns the start of the interval that ends at boundary.
+func periodStart(boundary time.Time, interval time.Duration) time.Time {
+	if interval == Daily {
+		y, m, d := boundary.Date()
+		return time.Date(y, m, d-1, 0, 0, 0, 0, boundary.Location())
+	}
+	return boundary.Add(-interval)
+}
+
+func splitPath(path string) (dir, base, ext string) {
+	dir = filepath.Dir(path)
+	name := filepath.Base(path)
+	ext = filepath.Ext(name)
+	return dir, strings.TrimSuffix(name, ext), ext
+}
+
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+// compressFile gzips path to path.gz, keeping the modification time, and
+// removes the original.
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = src.Close() }()
+
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(dst)
+	gz.Name = filepath.Base(path)
+	gz.ModTime = info.ModTime()
+	if _, err := io.Copy(gz, src); err != nil {
+		_ = dst.Close()
+		_ = os.Remove(path + ".gz")
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		_ = dst.Close()
+		_ = os.Remove(path + ".gz")
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+	_ = os.Chtimes(path+".gz", info.ModTime(), info.ModTime())
+	return os.Remove(path)
+}
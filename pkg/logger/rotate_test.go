@@ -0,0 +1,332 @@
+package logger
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jsas4coding/utify/pkg/messages"
+)
+
+// countLines counts the lines of every file in dir, decompressing .gz files.
+func countLines(t *testing.T, dir string) int {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir failed: %v", err)
+	}
+
+	total := 0
+	for _, e := range entries {
+		f, err := os.Open(filepath.Join(dir, e.Name()))
+		if err != nil {
+			t.Fatalf("Open failed: %v", err)
+		}
+		var r io.Reader = f
+		if strings.HasSuffix(e.Name(), ".gz") {
+			gz, err := gzip.NewReader(f)
+			if err != nil {
+				t.Fatalf("gzip.NewReader(%s) failed: %v", e.Name(), err)
+			}
+			r = gz
+		}
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			total++
+		}
+		_ = f.Close()
+	}
+	return total
+}
+
+func listDir(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir failed: %v", err)
+	}
+	names := make([]string, len(entries))
+	for i, e := range entries {
+		names[i] = e.Name()
+	}
+	return names
+}
+
+func TestRotateBySize(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	f, err := OpenRotating(path, Rotation{MaxSize: 20})
+	if err != nil {
+		t.Fatalf("OpenRotating failed: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := f.Write([]byte("0123456789\n")); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	names := listDir(t, dir)
+	if len(names) != 3 {
+		t.Fatalf("Expected the active file and 2 rotated files, got %v", names)
+	}
+	for _, name := range names {
+		if name != "app.log" && (!strings.HasPrefix(name, "app-") || !strings.Contains(name, ".log")) {
+			t.Errorf("Unexpected rotated file name %q", name)
+		}
+	}
+	if countLines(t, dir) != 3 {
+		t.Errorf("Expected 3 lines across all files, got %d", countLines(t, dir))
+	}
+}
+
+func TestRotateByInterval(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "agent.log")
+
+	now := time.Date(2024, 5, 1, 23, 59, 0, 0, time.Local)
+	f, err := OpenRotating(path, Rotation{Interval: Daily, TimeFormat: "20060102"})
+	if err != nil {
+		t.Fatalf("OpenRotating failed: %v", err)
+	}
+	f.now = func() time.Time { return now }
+	f.deadline = nextBoundary(now, Daily)
+
+	_, _ = f.Write([]byte("before midnight\n"))
+	now = now.Add(2 * time.Minute)
+	_, _ = f.Write([]byte("after midnight\n"))
+	_ = f.Close()
+
+	rotated, err := os.ReadFile(filepath.Join(dir, "agent-20240501.log"))
+	if err != nil {
+		t.Fatalf("Expected a rotated file named after the day it covers, got %v", listDir(t, dir))
+	}
+	if string(rotated) != "before midnight\n" {
+		t.Errorf("Unexpected rotated content %q", rotated)
+	}
+	active, _ := os.ReadFile(path)
+	if string(active) != "after midnight\n" {
+		t.Errorf("Unexpected active content %q", active)
+	}
+}
+
+func TestNextBoundary(t *testing.T) {
+	t0 := time.Date(2024, 5, 1, 13, 25, 0, 0, time.UTC)
+	tests := []struct {
+		interval time.Duration
+		expected time.Time
+	}{
+		{0, time.Time{}},
+		{Hourly, time.Date(2024, 5, 1, 14, 0, 0, 0, time.UTC)},
+		{Daily, time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)},
+		{15 * time.Minute, time.Date(2024, 5, 1, 13, 30, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		if got := nextBoundary(t0, tt.interval); !got.Equal(tt.expected) {
+			t.Errorf("nextBoundary(%s) = %v, expected %v", tt.interval, got, tt.expected)
+		}
+		if tt.interval == 0 {
+			continue
+		}
+		start := periodStart(tt.expected, tt.interval)
+		if start.After(t0) || !nextBoundary(start, tt.interval).Equal(tt.expected) {
+			t.Errorf("periodStart(%s) = %v, expected the start of the interval containing %v", tt.interval, start, t0)
+		}
+	}
+}
+
+func TestRotateCompressAndRetention(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	f, err := OpenRotating(path, Rotation{MaxSize: 1, Compress: true, MaxBackups: 2})
+	if err != nil {
+		t.Fatalf("OpenRotating failed: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		_, _ = fmt.Fprintf(f, "entry %d\n", i)
+	}
+	_ = f.Close()
+
+	var gz int
+	for _, name := range listDir(t, dir) {
+		switch {
+		case name == "app.log":
+		case strings.HasSuffix(name, ".gz"):
+			gz++
+		default:
+			t.Errorf("Expected rotated files to be compressed, found %q", name)
+		}
+	}
+	if gz != 2 {
+		t.Errorf("Expected MaxBackups to keep 2 files, got %v", listDir(t, dir))
+	}
+	if countLines(t, dir) != 3 {
+		t.Errorf("Expected the 3 newest entries to be kept, got %d", countLines(t, dir))
+	}
+}
+
+func TestRotateMaxAge(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	old := filepath.Join(dir, "app-2020-01-01T00-00-00.log")
+	if err := os.WriteFile(old, []byte("old\n"), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	past := time.Now().Add(-48 * time.Hour)
+	_ = os.Chtimes(old, past, past)
+	unrelated := filepath.Join(dir, "other.log")
+	_ = os.WriteFile(unrelated, []byte("keep\n"), 0644)
+	_ = os.Chtimes(unrelated, past, past)
+
+	f, err := OpenRotating(path, Rotation{MaxSize: 1, MaxAge: 24 * time.Hour})
+	if err != nil {
+		t.Fatalf("OpenRotating failed: %v", err)
+	}
+	_, _ = f.Write([]byte("a\n"))
+	_, _ = f.Write([]byte("b\n"))
+	_ = f.Close()
+
+	if exists(old) {
+		t.Error("Expected files older than MaxAge to be removed")
+	}
+	if !exists(unrelated) {
+		t.Error("Expected files of other logs to be kept")
+	}
+}
+
+func TestRotateKeepsUnrelatedFiles(t *testing.T) {
+	for _, target := range []string{"app.log", "app"} {
+		dir := t.TempDir()
+		path := filepath.Join(dir, target)
+		unrelated := []string{"app-worker.log", "app-worker", "app-2020-01-01T00-00-00.log.old",
+			"app-2020-01-01.log"}
+		past := time.Now().Add(-48 * time.Hour)
+		for _, name := range unrelated {
+			_ = os.WriteFile(filepath.Join(dir, name), []byte("keep\n"), 0644)
+			_ = os.Chtimes(filepath.Join(dir, name), past, past)
+		}
+
+		f, err := OpenRotating(path, Rotation{MaxSize: 1, MaxBackups: 1, MaxAge: time.Hour})
+		if err != nil {
+			t.Fatalf("OpenRotating failed: %v", err)
+		}
+		for i := 0; i < 4; i++ {
+			_, _ = fmt.Fprintf(f, "entry %d\n", i)
+		}
+		_ = f.Close()
+
+		for _, name := range unrelated {
+			if !exists(filepath.Join(dir, name)) {
+				t.Errorf("Expected %q next to %q to be kept", name, target)
+			}
+		}
+		if got := len(listDir(t, dir)); got != len(unrelated)+2 {
+			t.Errorf("Expected the active file and one backup of %q, got %v", target, listDir(t, dir))
+		}
+	}
+}
+
+func TestRotateEmptyFileAtDeadline(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	now := time.Date(2024, 5, 1, 10, 30, 0, 0, time.Local)
+	f, err := OpenRotating(path, Rotation{Interval: Hourly})
+	if err != nil {
+		t.Fatalf("OpenRotating failed: %v", err)
+	}
+	f.now = func() time.Time { return now }
+	f.deadline = nextBoundary(now, Hourly)
+
+	now = now.Add(2 * time.Hour)
+	_, _ = f.Write([]byte("first\n"))
+	_, _ = f.Write([]byte("second\n"))
+	_ = f.Close()
+
+	if names := listDir(t, dir); len(names) != 1 {
+		t.Errorf("Expected no rotation of an empty file, got %v", names)
+	}
+}
+
+func TestRotateRenameFailureKeepsEntry(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	f, err := OpenRotating(path, Rotation{MaxSize: 1})
+	if err != nil {
+		t.Fatalf("OpenRotating failed: %v", err)
+	}
+	f.rename = func(string, string) error { return os.ErrPermission }
+	_, _ = f.Write([]byte("a\n"))
+	if _, err := f.Write([]byte("b\n")); err == nil {
+		t.Error("Expected the failed rotation to be reported")
+	}
+	_ = f.Close()
+
+	data, _ := os.ReadFile(path)
+	if string(data) != "a\nb\n" {
+		t.Errorf("Expected the entry to go to the active file, got %q", data)
+	}
+}
+
+func TestRotateConcurrentWrites(t *testing.T) {
+	dir := t.TempDir()
+	l, err := NewRotating(filepath.Join(dir, "app.log"), Rotation{MaxSize: 512, Compress: true})
+	if err != nil {
+		t.Fatalf("NewRotating failed: %v", err)
+	}
+
+	const goroutines, perGoroutine = 8, 50
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < perGoroutine; i++ {
+				l.LogMessage(messages.Info, fmt.Sprintf("goroutine %d entry %d", g, i))
+			}
+		}(g)
+	}
+	wg.Wait()
+	l.Close()
+
+	if got := countLines(t, dir); got != goroutines*perGoroutine {
+		t.Errorf("Expected %d entries across rotated files, got %d", goroutines*perGoroutine, got)
+	}
+}
+
+func TestSetRotation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	l, err := New(path)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer l.Close()
+
+	if err := l.SetRotation(Rotation{MaxSize: 1}); err != nil {
+		t.Fatalf("SetRotation failed: %v", err)
+	}
+	if l.GetRotation().MaxSize != 1 || l.GetLogTarget() != path {
+		t.Errorf("Unexpected logger state after SetRotation")
+	}
+	l.LogMessage(messages.Info, "one")
+	l.LogMessage(messages.Info, "two")
+
+	if len(listDir(t, dir)) != 2 {
+		t.Errorf("Expected one rotation, got %v", listDir(t, dir))
+	}
+}
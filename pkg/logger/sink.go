@@ -0,0 +1,156 @@
+package logger
+
+import (
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/jsas4coding/utify/pkg/messages"
+)
+
+// Sink is a destination for log entries. A Logger writes every entry to its
+// file target and to each of its sinks; sinks decide what to keep and how
+// to encode it.
+type Sink interface {
+	Write(e LogEntry) error
+	Close() error
+}
+
+// WriterSink encodes entries at or above a minimum severity and writes them
+// to an io.Writer, one per line. It is safe for concurrent use.
+type WriterSink struct {
+	mu      sync.Mutex
+	w       io.Writer
+	closer  io.Closer
+	encoder Encoder
+	level   messages.Severity
+}
+
+// NewWriterSink returns a sink writing entries of at least level to w with
+// enc. A nil enc uses JSONEncoder. Closing the sink does not close w.
+func NewWriterSink(w io.Writer, enc Encoder, level messages.Severity) *WriterSink {
+	if enc == nil {
+		enc = JSONEncoder{}
+	}
+	return &WriterSink{w: w, encoder: enc, level: level}
+}
+
+// NewStderrSink returns a sink writing entries of at least level to
+// os.Stderr with enc.
+func NewStderrSink(enc Encoder, level messages.Severity) *WriterSink {
+	return NewWriterSink(os.Stderr, enc, level)
+}
+
+// NewFileSink returns a sink appending entries of at least level to path
+// with enc, creating the file and its directory if needed.
+func NewFileSink(path string, enc Encoder, level messages.Severity) (*WriterSink, error) {
+	return NewRotatingFileSink(path, Rotation{}, enc, level)
+}
+
+// NewRotatingFileSink is like NewFileSink but rotates the file according
+// to r.
+func NewRotatingFileSink(path string, r Rotation, enc Encoder, level messages.Severity) (*WriterSink, error) {
+	f, err := OpenRotating(path, r)
+	if err != nil {
+		return nil, err
+	}
+	s := NewWriterSink(f, enc, level)
+	s.closer = f
+	return s, nil
+}
+
+// Write implements Sink. Entries below the sink's level are skipped.
+func (s *WriterSink) Write(e LogEntry) error {
+	if messages.SeverityOf(e.Type) < s.level {
+		return nil
+	}
+	data, err := s.encoder.Encode(e)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.w.Write(append(data, '\n'))
+	return err
+}
+
+// Close closes the file of a file sink.
+func (s *WriterSink) Close() error {
+	if s.closer == nil {
+		return nil
+	}
+	return s.closer.Close()
+}
+
+// MemorySink keeps encoded entries in memory, e.g. for tests or to show
+// recent entries in a UI. It is safe for concurrent use.
+type MemorySink struct {
+	mu      sync.Mutex
+	encoder Encoder
+	level   messages.Severity
+	entries []LogEntry
+	lines   []string
+}
+
+// NewMemorySink returns a sink keeping entries of at least level, encoded
+// with enc. A nil enc uses JSONEncoder.
+func NewMemorySink(enc Encoder, level messages.Severity) *MemorySink {
+	if enc == nil {
+		enc = JSONEncoder{}
+	}
+	return &MemorySink{encoder: enc, level: level}
+}
+
+// Write implements Sink.
+func (s *MemorySink) Write(e LogEntry) error {
+	if messages.SeverityOf(e.Type) < s.level {
+		return nil
+	}
+	data, err := s.encoder.Encode(e)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, e)
+	s.lines = append(s.lines, string(data))
+	return nil
+}
+
+// Entries returns the kept entries.
+func (s *MemorySink) Entries() []LogEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]LogEntry(nil), s.entries...)
+}
+
+// Lines returns the encoded entries.
+func (s *MemorySink) Lines() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.lines...)
+}
+
+// String returns the encoded entries, one per line.
+func (s *MemorySink) String() string {
+	lines := s.Lines()
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// Reset discards the kept entries.
+func (s *MemorySink) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries, s.lines = nil, nil
+}
+
+// Close implements Sink.
+func (s *MemorySink) Close() error {
+	return nil
+}
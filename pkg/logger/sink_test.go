@@ -0,0 +1,114 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jsas4coding/utify/pkg/field"
+	"github.com/jsas4coding/utify/pkg/messages"
+)
+
+func TestTextEncoder(t *testing.T) {
+	e := LogEntry{
+		Timestamp: "2024-05-01T12:00:00Z",
+		Level:     "ERROR",
+		Message:   "upload failed",
+		Type:      messages.Error,
+		Fields:    []field.Field{field.F("file", "my report.pdf")},
+	}
+	data, err := TextEncoder{}.Encode(e)
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	expected := `2024-05-01T12:00:00Z ERROR    upload failed file="my report.pdf"`
+	if string(data) != expected {
+		t.Errorf("Expected %q, got %q", expected, data)
+	}
+}
+
+func TestSinkFanOut(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.json")
+	file, err := NewFileSink(path, JSONEncoder{}, messages.SeverityTrace)
+	if err != nil {
+		t.Fatalf("NewFileSink failed: %v", err)
+	}
+	var debug bytes.Buffer
+	text := NewWriterSink(&debug, TextEncoder{}, messages.SeverityWarn)
+	mem := NewMemorySink(nil, messages.SeverityError)
+
+	l := NewWithSinks(file, text, mem)
+	l.LogMessage(messages.Info, "started", field.F("port", 8080))
+	l.LogMessage(messages.Warning, "slow response")
+	l.LogMessage(messages.Error, "failed")
+	l.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 JSON entries in the file, got %d", len(lines))
+	}
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("Invalid JSON %q: %v", lines[0], err)
+	}
+	if entry["message"] != "started" || entry["port"] != float64(8080) {
+		t.Errorf("Unexpected entry %v", entry)
+	}
+
+	textLines := strings.Split(strings.TrimSpace(debug.String()), "\n")
+	if len(textLines) != 2 || !strings.Contains(textLines[0], "WARNING  slow response") {
+		t.Errorf("Expected warnings and errors as text, got %q", debug.String())
+	}
+
+	if entries := mem.Entries(); len(entries) != 1 || entries[0].Message != "failed" {
+		t.Errorf("Expected only the error in memory, got %v", entries)
+	}
+	if len(l.Sinks()) != 0 {
+		t.Error("Expected Close to remove the sinks")
+	}
+}
+
+func TestAddRemoveSink(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewWriter(&buf)
+	mem := NewMemorySink(TextEncoder{}, messages.SeverityTrace)
+
+	l.AddSink(mem)
+	l.LogMessage(messages.Debug, "one")
+	if !l.RemoveSink(mem) {
+		t.Error("Expected RemoveSink to find the sink")
+	}
+	if l.RemoveSink(mem) {
+		t.Error("Expected a removed sink not to be found again")
+	}
+	l.LogMessage(messages.Debug, "two")
+
+	if len(mem.Lines()) != 1 || !strings.HasSuffix(mem.String(), "DEBUG    one\n") {
+		t.Errorf("Unexpected sink content %q", mem.String())
+	}
+	if strings.Count(buf.String(), "\n") != 2 {
+		t.Errorf("Expected the writer to receive both entries, got %q", buf.String())
+	}
+
+	mem.Reset()
+	if mem.String() != "" {
+		t.Error("Expected Reset to discard the entries")
+	}
+}
+
+func TestSinksOfDisabledLogger(t *testing.T) {
+	mem := NewMemorySink(nil, messages.SeverityTrace)
+	l := NewWithSinks(mem)
+	l.SetEnabled(false)
+	l.LogMessage(messages.Info, "hidden")
+	if len(mem.Entries()) != 0 {
+		t.Error("Expected a disabled logger not to write to its sinks")
+	}
+}
@@ -1,6 +1,9 @@
 package messages
 
 import (
+	"errors"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/jsas4coding/utify/pkg/colors"
@@ -37,7 +40,7 @@ func TestIsErrorType(t *testing.T) {
 	}{
 		{"Error", Error, true},
 		{"Critical", Critical, true},
-		{"Debug", Debug, true},
+		{"Debug", Debug, false},
 		{"Success", Success, false},
 		{"Warning", Warning, false},
 		{"Info", Info, false},
@@ -56,9 +59,11 @@ func TestIsErrorType(t *testing.T) {
 func TestGetColorWithUserOverride(t *testing.T) {
 	colors.ClearUserColors()
 	customColor := "\033[95m"
-	colors.SetColorTable(map[string]string{
+	if err := colors.SetColorTable(map[string]string{
 		string(Success): customColor,
-	})
+	}); err != nil {
+		t.Fatalf("SetColorTable failed: %v", err)
+	}
 
 	color := GetColor(Success)
 	if color != customColor {
@@ -68,3 +73,88 @@ func TestGetColorWithUserOverride(t *testing.T) {
 	// Clean up
 	colors.ClearUserColors()
 }
+
+func TestRegister(t *testing.T) {
+	const rollback Type = "rollback"
+	defer Unregister(rollback)
+
+	def := Definition{
+		Color:    colors.RGB(255, 135, 0),
+		Icon:     "↩",
+		Label:    "ROLLBACK",
+		Severity: SeverityWarn,
+		IsError:  true,
+	}
+	if err := Register(rollback, def); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	got, ok := Lookup(rollback)
+	if !ok || got != def {
+		t.Errorf("Expected registered definition %+v, got %+v", def, got)
+	}
+	if !IsErrorType(rollback) {
+		t.Error("Expected registered type to be an error type")
+	}
+	if SeverityOf(rollback) != SeverityWarn {
+		t.Errorf("Expected severity warn, got %s", SeverityOf(rollback))
+	}
+	if GetColor(rollback) != "\033[38;2;255;135;0m" {
+		t.Errorf("Expected registered color, got %q", GetColor(rollback))
+	}
+	if !slices.Contains(Types(), rollback) {
+		t.Error("Expected Types to include the registered type")
+	}
+
+	Unregister(rollback)
+	if IsRegistered(rollback) {
+		t.Error("Expected type to be removed by Unregister")
+	}
+}
+
+func TestRegisterRejectsInvalidTypes(t *testing.T) {
+	if err := Register("", Definition{}); !errors.Is(err, ErrInvalidType) {
+		t.Errorf("Expected ErrInvalidType, got %v", err)
+	}
+	if err := Register(Error, Definition{}); !errors.Is(err, ErrBuiltinType) {
+		t.Errorf("Expected ErrBuiltinType, got %v", err)
+	}
+	if !IsErrorType(Error) {
+		t.Error("Predefined definitions should be unchanged")
+	}
+}
+
+func TestBuiltinSeverities(t *testing.T) {
+	tests := map[Type]Severity{
+		Debug:    SeverityDebug,
+		Info:     SeverityInfo,
+		Success:  SeverityNotice,
+		Warning:  SeverityWarn,
+		Error:    SeverityError,
+		Critical: SeverityCritical,
+		"nope":   SeverityInfo,
+	}
+	for msgType, expected := range tests {
+		if got := SeverityOf(msgType); got != expected {
+			t.Errorf("Expected %s for %s, got %s", expected, msgType, got)
+		}
+	}
+	if SeverityWarn.String() != "warn" {
+		t.Errorf("Unexpected severity name %q", SeverityWarn.String())
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	for s, name := range severityNames {
+		got, err := ParseSeverity(strings.ToUpper(name))
+		if err != nil || got != s {
+			t.Errorf("ParseSeverity(%q) = %v, %v; expected %v", name, got, err, s)
+		}
+	}
+	if s, err := ParseSeverity("warning"); err != nil || s != SeverityWarn {
+		t.Errorf("Expected warning alias, got %v, %v", s, err)
+	}
+	if _, err := ParseSeverity("loud"); !errors.Is(err, ErrInvalidSeverity) {
+		t.Errorf("Expected ErrInvalidSeverity, got %v", err)
+	}
+}
@@ -0,0 +1,155 @@
+package messages
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/jsas4coding/utify/pkg/colors"
+)
+
+var (
+	// ErrInvalidType is returned when registering a type with an empty name.
+	ErrInvalidType = errors.New("invalid message type")
+
+	// ErrBuiltinType is returned when registering over a predefined type.
+	ErrBuiltinType = errors.New("message type is predefined")
+
+	// ErrInvalidSeverity is returned by ParseSeverity for unknown names.
+	ErrInvalidSeverity = errors.New("invalid severity")
+)
+
+// Severity orders message types from the most verbose to the most severe.
+// The zero value is SeverityInfo.
+type Severity int8
+
+const (
+	SeverityTrace Severity = iota - 2
+	SeverityDebug
+	SeverityInfo
+	SeverityNotice
+	SeverityWarn
+	SeverityError
+	SeverityCritical
+)
+
+var severityNames = map[Severity]string{
+	SeverityTrace:    "trace",
+	SeverityDebug:    "debug",
+	SeverityInfo:     "info",
+	SeverityNotice:   "notice",
+	SeverityWarn:     "warn",
+	SeverityError:    "error",
+	SeverityCritical: "critical",
+}
+
+// String returns the lower-case name of the severity.
+func (s Severity) String() string {
+	if name, exists := severityNames[s]; exists {
+		return name
+	}
+	return fmt.Sprintf("severity(%d)", int8(s))
+}
+
+// ParseSeverity parses a severity name such as "debug" or "warn". "warning"
+// is accepted as an alias of "warn".
+func ParseSeverity(name string) (Severity, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "warning" {
+		return SeverityWarn, nil
+	}
+	for s, n := range severityNames {
+		if n == name {
+			return s, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %q", ErrInvalidSeverity, name)
+}
+
+// Definition describes how a message type is rendered and classified.
+// Empty icons fall back to the icon of the Default type.
+type Definition struct {
+	Color    colors.Color
+	Icon     string
+	NerdIcon string
+	Label    string
+	Severity Severity
+	IsError  bool
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[Type]Definition{}
+)
+
+// Register adds a custom message type. Registering a type again replaces
+// its definition; predefined types cannot be replaced.
+func Register(msgType Type, def Definition) error {
+	if msgType == "" {
+		return fmt.Errorf("%w: empty name", ErrInvalidType)
+	}
+	if _, exists := builtin[msgType]; exists {
+		return fmt.Errorf("%w: %q", ErrBuiltinType, msgType)
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[msgType] = def
+	return nil
+}
+
+// Unregister removes a custom message type. It is a no-op for predefined
+// and unknown types.
+func Unregister(msgType Type) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	delete(registry, msgType)
+}
+
+// Lookup returns the definition of a predefined or registered type.
+func Lookup(msgType Type) (Definition, bool) {
+	if def, exists := builtin[msgType]; exists {
+		return def, true
+	}
+
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	def, exists := registry[msgType]
+	return def, exists
+}
+
+// IsRegistered reports whether msgType is a predefined or registered type.
+func IsRegistered(msgType Type) bool {
+	_, exists := Lookup(msgType)
+	return exists
+}
+
+// IsBuiltin reports whether msgType is one of the predefined types.
+func IsBuiltin(msgType Type) bool {
+	_, exists := builtin[msgType]
+	return exists
+}
+
+// Types returns all predefined and registered types in sorted order.
+func Types() []Type {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	types := make([]Type, 0, len(builtin)+len(registry))
+	for msgType := range builtin {
+		types = append(types, msgType)
+	}
+	for msgType := range registry {
+		types = append(types, msgType)
+	}
+	slices.Sort(types)
+	return types
+}
+
+// SeverityOf returns the severity of msgType. Unknown types are SeverityInfo.
+func SeverityOf(msgType Type) Severity {
+	def, _ := Lookup(msgType)
+	return def.Severity
+}
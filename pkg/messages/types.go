@@ -33,42 +33,52 @@ const (
 	Default    Type = "default"
 )
 
-var defaultColors = map[Type]string{
-	Success:    colors.Green,
-	Error:      colors.Red,
-	Warning:    colors.Yellow,
-	Info:       colors.Cyan,
-	Debug:      colors.Gray,
-	Search:     colors.Blue,
-	Sync:       colors.Magenta,
-	Download:   colors.White,
-	Refresh:    colors.LightBlue,
-	Upload:     colors.Green,
-	Delete:     colors.Red,
-	Critical:   colors.Magenta,
-	Git:        colors.Magenta,
-	New:        colors.Green,
-	Edit:       colors.Blue,
-	Update:     colors.Yellow,
-	Generation: colors.Cyan,
-	Find:       colors.Blue,
-	Link:       colors.Magenta,
-	Unlink:     colors.Red,
-	Upgrade:    colors.LightBlue,
-	Install:    colors.Green,
-	Font:       colors.White,
-	Theme:      colors.Magenta,
-	Icon:       colors.White,
-	Default:    colors.White,
+// builtin holds the definitions of the predefined message types. Their icons
+// live in the icons package.
+var builtin = map[Type]Definition{
+	Success:    {Color: colors.MustParse(colors.Green), Severity: SeverityNotice},
+	Error:      {Color: colors.MustParse(colors.Red), Severity: SeverityError, IsError: true},
+	Warning:    {Color: colors.MustParse(colors.Yellow), Severity: SeverityWarn},
+	Info:       {Color: colors.MustParse(colors.Cyan)},
+	Debug:      {Color: colors.MustParse(colors.Gray), Severity: SeverityDebug},
+	Search:     {Color: colors.MustParse(colors.Blue)},
+	Sync:       {Color: colors.MustParse(colors.Magenta)},
+	Download:   {Color: colors.MustParse(colors.White)},
+	Refresh:    {Color: colors.MustParse(colors.LightBlue)},
+	Upload:     {Color: colors.MustParse(colors.Green)},
+	Delete:     {Color: colors.MustParse(colors.Red)},
+	Critical:   {Color: colors.MustParse(colors.Magenta), Severity: SeverityCritical, IsError: true},
+	Git:        {Color: colors.MustParse(colors.Magenta)},
+	New:        {Color: colors.MustParse(colors.Green)},
+	Edit:       {Color: colors.MustParse(colors.Blue)},
+	Update:     {Color: colors.MustParse(colors.Yellow)},
+	Generation: {Color: colors.MustParse(colors.Cyan)},
+	Find:       {Color: colors.MustParse(colors.Blue)},
+	Link:       {Color: colors.MustParse(colors.Magenta)},
+	Unlink:     {Color: colors.MustParse(colors.Red)},
+	Upgrade:    {Color: colors.MustParse(colors.LightBlue)},
+	Install:    {Color: colors.MustParse(colors.Green)},
+	Font:       {Color: colors.MustParse(colors.White)},
+	Theme:      {Color: colors.MustParse(colors.Magenta)},
+	Icon:       {Color: colors.MustParse(colors.White)},
+	Default:    {Color: colors.MustParse(colors.White)},
 }
 
 func GetColor(msgType Type) string {
-	if color, exists := colors.GetUserColor(string(msgType)); exists {
+	return ColorFrom(colors.DefaultTable(), msgType).Sequence(colors.ProfileTrueColor)
+}
+
+// ColorFrom returns the color for msgType, preferring overrides in table.
+func ColorFrom(table *colors.Table, msgType Type) colors.Color {
+	if color, exists := table.Lookup(string(msgType)); exists {
 		return color
 	}
-	return defaultColors[msgType]
+	def, _ := Lookup(msgType)
+	return def.Color
 }
 
+// IsErrorType reports whether msgType is defined as an error type.
 func IsErrorType(msgType Type) bool {
-	return msgType == Error || msgType == Critical || msgType == Debug
+	def, _ := Lookup(msgType)
+	return def.IsError
 }
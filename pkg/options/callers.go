@@ -0,0 +1,31 @@
+package options
+
+import (
+	"slices"
+
+	"github.com/jsas4coding/utify/pkg/messages"
+)
+
+// Callers configures capture of the source location of messages. The zero
+// value captures nothing, and nothing is spent on capture.
+type Callers struct {
+	// Log records the caller in the log entries of every message.
+	Log bool
+	// Console shows the caller on the console lines of these message
+	// types, through the caller token of the layout.
+	Console []messages.Type
+	// Skip is the number of stack frames to skip above the first caller
+	// outside utify, so that wrappers report their own callers.
+	Skip int
+}
+
+// InConsole reports whether console lines of msgType show the caller.
+func (c Callers) InConsole(msgType messages.Type) bool {
+	return slices.Contains(c.Console, msgType)
+}
+
+// Clone returns a copy of c that does not share its type list.
+func (c Callers) Clone() Callers {
+	c.Console = slices.Clone(c.Console)
+	return c
+}
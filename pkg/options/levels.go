@@ -0,0 +1,46 @@
+package options
+
+import "github.com/jsas4coding/utify/pkg/messages"
+
+// Levels holds the minimum severities a message needs to be printed to the
+// console and to be written to the log.
+type Levels struct {
+	Console messages.Severity
+	Log     messages.Severity
+}
+
+// DefaultLevels returns the default thresholds: info and above on the
+// console, debug and above in the log.
+func DefaultLevels() Levels {
+	return Levels{Console: messages.SeverityInfo, Log: messages.SeverityDebug}
+}
+
+// VerbosityLevels returns thresholds for a -q/-v style verbosity. Zero gives
+// DefaultLevels; -1 shows warnings and above, -2 or less errors only; 1
+// shows debug messages and 2 or more trace messages, which are then logged
+// too. Quiet modes only affect the console.
+func VerbosityLevels(verbosity int) Levels {
+	l := DefaultLevels()
+	switch {
+	case verbosity <= -2:
+		l.Console = messages.SeverityError
+	case verbosity == -1:
+		l.Console = messages.SeverityWarn
+	case verbosity == 1:
+		l.Console = messages.SeverityDebug
+	case verbosity >= 2:
+		l.Console = messages.SeverityTrace
+		l.Log = messages.SeverityTrace
+	}
+	return l
+}
+
+// PrintsToConsole reports whether messages of msgType are printed.
+func (l Levels) PrintsToConsole(msgType messages.Type) bool {
+	return messages.SeverityOf(msgType) >= l.Console
+}
+
+// WritesToLog reports whether messages of msgType are logged.
+func (l Levels) WritesToLog(msgType messages.Type) bool {
+	return messages.SeverityOf(msgType) >= l.Log
+}
@@ -0,0 +1,45 @@
+package options
+
+import (
+	"testing"
+
+	"github.com/jsas4coding/utify/pkg/messages"
+)
+
+func TestVerbosityLevels(t *testing.T) {
+	tests := []struct {
+		verbosity int
+		console   messages.Severity
+		log       messages.Severity
+	}{
+		{-3, messages.SeverityError, messages.SeverityDebug},
+		{-2, messages.SeverityError, messages.SeverityDebug},
+		{-1, messages.SeverityWarn, messages.SeverityDebug},
+		{0, messages.SeverityInfo, messages.SeverityDebug},
+		{1, messages.SeverityDebug, messages.SeverityDebug},
+		{2, messages.SeverityTrace, messages.SeverityTrace},
+	}
+
+	for _, tt := range tests {
+		l := VerbosityLevels(tt.verbosity)
+		if l.Console != tt.console || l.Log != tt.log {
+			t.Errorf("VerbosityLevels(%d) = %+v, expected console %s and log %s",
+				tt.verbosity, l, tt.console, tt.log)
+		}
+	}
+}
+
+func TestLevelsFilter(t *testing.T) {
+	l := DefaultLevels()
+	if l.PrintsToConsole(messages.Debug) || !l.PrintsToConsole(messages.Info) {
+		t.Error("Expected the default console level to hide debug and show info")
+	}
+	if !l.WritesToLog(messages.Debug) {
+		t.Error("Expected the default log level to keep debug")
+	}
+
+	quiet := VerbosityLevels(-1)
+	if quiet.PrintsToConsole(messages.Success) || !quiet.PrintsToConsole(messages.Warning) {
+		t.Error("Expected quiet mode to hide success and show warnings")
+	}
+}
@@ -3,14 +3,26 @@ package options
 import "github.com/jsas4coding/utify/pkg/messages"
 
 type Options struct {
-	Bold      bool
-	Italic    bool
-	NoColor   bool
-	NoIcon    bool
-	NoStyle   bool
-	Exit      bool
-	ShowIcons bool
-	Callback  func(messages.Type, string)
+	Bold    bool
+	Italic  bool
+	NoColor bool
+	// ForceColor renders colors even when the output does not support them.
+	ForceColor bool
+	NoIcon     bool
+	NoStyle    bool
+	Exit       bool
+	ShowIcons  bool
+	Callback   func(messages.Type, string)
+	// Route overrides the routing table for this call. Zero uses the table.
+	Route Route
+	// Scope names the component a message comes from, e.g. "db". It
+	// overrides the scope of the formatter.
+	Scope string
+	// Caller captures the caller of this call for both the console and the
+	// log, regardless of the Callers configuration.
+	Caller bool
+	// CallerSkip skips additional stack frames when the caller is captured.
+	CallerSkip int
 }
 
 func Default() *Options {
@@ -29,6 +41,35 @@ func (o *Options) WithItalic() *Options {
 
 func (o *Options) WithoutColor() *Options {
 	o.NoColor = true
+	o.ForceColor = false
+	return o
+}
+
+// WithColor renders colors and styles even when terminal detection would
+// disable them, e.g. when output is piped. It clears NoColor.
+func (o *Options) WithColor() *Options {
+	o.ForceColor = true
+	o.NoColor = false
+	return o
+}
+
+// WithScope sets the scope shown by layouts with a scope token.
+func (o *Options) WithScope(scope string) *Options {
+	o.Scope = scope
+	return o
+}
+
+// WithCaller captures the caller of this call and shows it on the console
+// and in the log.
+func (o *Options) WithCaller() *Options {
+	o.Caller = true
+	return o
+}
+
+// WithCallerSkip skips n more stack frames when capturing the caller, for
+// helpers that wrap utify calls.
+func (o *Options) WithCallerSkip(n int) *Options {
+	o.CallerSkip = n
 	return o
 }
 
@@ -60,3 +101,9 @@ func (o *Options) WithCallback(cb func(messages.Type, string)) *Options {
 	o.Exit = false
 	return o
 }
+
+// WithRoute delivers messages to route instead of the routing table entry.
+func (o *Options) WithRoute(route Route) *Options {
+	o.Route = route
+	return o
+}
@@ -136,3 +136,19 @@ func TestWithoutIcon(t *testing.T) {
 		t.Error("WithoutIcon should set NoIcon to true")
 	}
 }
+
+func TestWithColor(t *testing.T) {
+	opts := Default().WithoutColor().WithColor()
+
+	if !opts.ForceColor {
+		t.Error("WithColor should set ForceColor to true")
+	}
+	if opts.NoColor {
+		t.Error("WithColor should set NoColor to false")
+	}
+
+	opts.WithoutColor()
+	if opts.ForceColor {
+		t.Error("WithoutColor should set ForceColor to false")
+	}
+}
@@ -0,0 +1,57 @@
+package options
+
+import "github.com/jsas4coding/utify/pkg/messages"
+
+// Route is a bit set of destinations a message is delivered to.
+type Route uint8
+
+const (
+	// RouteStdout prints the message to standard output.
+	RouteStdout Route = 1 << iota
+	// RouteStderr prints the message to standard error.
+	RouteStderr
+	// RouteLog writes the message to the structured log.
+	RouteLog
+	// RouteCallback runs the callback configured with WithCallback.
+	RouteCallback
+)
+
+// DefaultRoute is used for message types without an entry in a Routing table.
+const DefaultRoute = RouteStdout | RouteLog | RouteCallback
+
+// Has reports whether r includes every destination in dest.
+func (r Route) Has(dest Route) bool {
+	return r&dest == dest
+}
+
+// Routing maps message types to the destinations they are delivered to.
+type Routing map[messages.Type]Route
+
+// DefaultRouting returns the default routing table: errors, critical
+// messages and warnings go to stderr, everything else to stdout. All types
+// are logged and trigger callbacks.
+func DefaultRouting() Routing {
+	stderr := RouteStderr | RouteLog | RouteCallback
+	return Routing{
+		messages.Error:    stderr,
+		messages.Critical: stderr,
+		messages.Warning:  stderr,
+	}
+}
+
+// Lookup returns the route for msgType, or DefaultRoute if it has none.
+func (r Routing) Lookup(msgType messages.Type) Route {
+	if route, exists := r[msgType]; exists {
+		return route
+	}
+	return DefaultRoute
+}
+
+// Clone returns a copy of the routing table.
+func (r Routing) Clone() Routing {
+	clone := make(Routing, len(r))
+	for k, v := range r {
+		clone[k] = v
+	}
+	return clone
+}
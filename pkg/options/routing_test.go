@@ -0,0 +1,47 @@
+package options
+
+import (
+	"testing"
+
+	"github.com/jsas4coding/utify/pkg/messages"
+)
+
+func TestDefaultRouting(t *testing.T) {
+	routing := DefaultRouting()
+
+	for _, msgType := range []messages.Type{messages.Error, messages.Critical, messages.Warning} {
+		route := routing.Lookup(msgType)
+		if !route.Has(RouteStderr) || route.Has(RouteStdout) {
+			t.Errorf("Expected %s to be routed to stderr only, got %b", msgType, route)
+		}
+	}
+
+	route := routing.Lookup(messages.Success)
+	if route != DefaultRoute {
+		t.Errorf("Expected success to use DefaultRoute, got %b", route)
+	}
+	if !route.Has(RouteStdout | RouteLog | RouteCallback) {
+		t.Errorf("Expected DefaultRoute to print, log and call back, got %b", route)
+	}
+}
+
+func TestRoutingClone(t *testing.T) {
+	routing := Routing{messages.Info: RouteLog}
+	clone := routing.Clone()
+	clone[messages.Info] = RouteStdout
+
+	if routing.Lookup(messages.Info) != RouteLog {
+		t.Error("Modifying a clone should not change the original")
+	}
+}
+
+func TestWithRoute(t *testing.T) {
+	opts := Default().WithRoute(RouteStderr | RouteLog)
+
+	if opts.Route != RouteStderr|RouteLog {
+		t.Errorf("WithRoute should set Route, got %b", opts.Route)
+	}
+	if Default().Route != 0 {
+		t.Error("Default options should not override routing")
+	}
+}
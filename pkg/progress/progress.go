@@ -0,0 +1,440 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jsas4coding/utify/pkg/colors"
+	"github.com/jsas4coding/utify/pkg/duration"
+	"github.com/jsas4coding/utify/pkg/field"
+	"github.com/jsas4coding/utify/pkg/formatter"
+	"github.com/jsas4coding/utify/pkg/messages"
+	"github.com/jsas4coding/utify/pkg/options"
+	"github.com/jsas4coding/utify/pkg/terminal"
+)
+
+// Default settings used for zero Options fields.
+const (
+	DefaultWidth         = 30
+	DefaultInterval      = 100 * time.Millisecond
+	DefaultPlainInterval = 5 * time.Second
+)
+
+// Options configures a Group. The zero value draws bars on os.Stderr with
+// the colors and icons of the default formatter.
+type Options struct {
+	// Output receives the bars. Nil uses os.Stderr.
+	Output io.Writer
+
+	// Formatter provides the colors and icons of the message types. Nil
+	// uses formatter.Default().
+	Formatter *formatter.Formatter
+
+	// Options are the display options of the bars. Nil shows icons.
+	Options *options.Options
+
+	// Width is the number of cells of a bar. Zero uses DefaultWidth.
+	Width int
+
+	// Interval is how often bars are redrawn on a terminal. Zero uses
+	// DefaultInterval.
+	Interval time.Duration
+
+	// PlainInterval is how often a line is printed for every bar that
+	// changed when Output is not a terminal. Zero uses DefaultPlainInterval.
+	PlainInterval time.Duration
+
+	// Plain prints lines instead of redrawing bars even on a terminal.
+	Plain bool
+}
+
+// Group draws concurrent progress bars, one line each, e.g. for parallel
+// downloads. On a terminal the bars are redrawn in place; otherwise a plain
+// line is printed periodically for every bar that progressed, and once more
+// when it finishes. Bars of a group should not be mixed with other output
+// to the same terminal while they are drawn.
+type Group struct {
+	opts  Options
+	out   io.Writer
+	plain bool
+	now   func() time.Time
+
+	mu      sync.Mutex
+	bars    []*Bar
+	region  *terminal.Region
+	frame   int
+	running bool
+	kick    chan struct{}
+	stopped chan struct{}
+}
+
+// NewGroup returns an empty group. A nil opts uses the zero Options.
+func NewGroup(opts *Options) *Group {
+	g := &Group{now: time.Now, kick: make(chan struct{}, 1)}
+	if opts != nil {
+		g.opts = *opts
+	}
+	g.out = g.opts.Output
+	if g.out == nil {
+		g.out = os.Stderr
+	}
+	g.plain = g.opts.Plain || !terminal.IsInteractive(g.out)
+	g.region = terminal.NewRegion(g.out)
+	return g
+}
+
+// New returns a bar drawn by a group of its own on os.Stderr:
+//
+//	bar := progress.New(messages.Download, "app.tar.gz", resp.ContentLength).WithBytes()
+//	_, err := io.Copy(file, bar.Reader(resp.Body))
+//	bar.Done()
+//	bar.Wait()
+func New(msgType messages.Type, message string, total int64) *Bar {
+	return NewGroup(nil).Add(msgType, message, total)
+}
+
+// Add adds a bar counting up to total. A total of zero or less makes an
+// indeterminate bar, which shows activity but no percentage.
+func (g *Group) Add(msgType messages.Type, message string, total int64) *Bar {
+	b := g.newBar(msgType, message, total)
+	g.mu.Lock()
+	g.bars = append(g.bars, b)
+	if !g.running {
+		g.running = true
+		g.stopped = make(chan struct{})
+		go g.run(g.stopped)
+	}
+	g.mu.Unlock()
+	g.refresh()
+	return b
+}
+
+func (g *Group) newBar(msgType messages.Type, message string, total int64) *Bar {
+	b := &Bar{group: g, msgType: msgType, message: message, start: g.now(), printed: -1}
+	b.total.Store(total)
+	return b
+}
+
+// Wait blocks until every bar is done and has been drawn in its final
+// state.
+func (g *Group) Wait() {
+	g.mu.Lock()
+	running, stopped := g.running, g.stopped
+	g.mu.Unlock()
+	if running {
+		<-stopped
+	}
+}
+
+// refresh asks the drawing goroutine to draw now.
+func (g *Group) refresh() {
+	select {
+	case g.kick <- struct{}{}:
+	default:
+	}
+}
+
+func (g *Group) run(stopped chan struct{}) {
+	interval := g.opts.Interval
+	if g.plain {
+		interval = g.opts.PlainInterval
+		if interval <= 0 {
+			interval = DefaultPlainInterval
+		}
+	} else if interval <= 0 {
+		interval = DefaultInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-g.kick:
+		}
+		g.mu.Lock()
+		finished := g.draw()
+		if finished {
+			g.running = false
+		}
+		g.mu.Unlock()
+		if finished {
+			close(stopped)
+			return
+		}
+	}
+}
+
+// draw writes the bars and reports whether they are all done, in which case
+// they are removed from the group. g.mu must be held.
+func (g *Group) draw() bool {
+	now := g.now()
+	finished := true
+	var buf strings.Builder
+	if g.plain {
+		for _, b := range g.bars {
+			finished = finished && b.done
+			current := b.current.Load()
+			if b.reported || (!b.done && current == b.printed) {
+				continue
+			}
+			b.printed, b.reported = current, b.done
+			buf.WriteString(g.render(b, now))
+			buf.WriteByte('\n')
+		}
+	} else {
+		lines := make([]string, len(g.bars))
+		for i, b := range g.bars {
+			finished = finished && b.done
+			lines[i] = g.render(b, now)
+		}
+		g.region.Draw(lines)
+		g.frame++
+	}
+	if buf.Len() > 0 {
+		_, _ = io.WriteString(g.out, buf.String())
+	}
+	if finished {
+		g.bars = nil
+		g.region.Reset()
+	}
+	return finished
+}
+
+// render returns the line of b: its icon and message, the bar on terminals,
+// then the percentage, count, rate and remaining or elapsed time.
+func (g *Group) render(b *Bar, now time.Time) string {
+	msgType := b.msgType
+	if b.failed {
+		msgType = messages.Error
+	}
+	icon, style := g.formatter().Decoration(g.out, msgType, g.options())
+	reset := ""
+	if style != "" {
+		reset = colors.Reset
+	}
+
+	current, total := b.current.Load(), b.total.Load()
+	end := now
+	if b.done {
+		end = b.end
+	}
+	elapsed := end.Sub(b.start)
+
+	parts := make([]string, 0, 7)
+	if head := strings.TrimSpace(icon + " " + b.message); head != "" {
+		parts = append(parts, style+head+reset)
+	}
+	if !g.plain {
+		parts = append(parts, g.cells(b, current, total, style, reset))
+	}
+	if total > 0 {
+		parts = append(parts, fmt.Sprintf("%3d%%", percent(current, total)))
+		parts = append(parts, b.count(current)+"/"+b.count(total))
+	} else {
+		parts = append(parts, b.count(current))
+	}
+	var rate float64
+	if elapsed > 0 {
+		rate = float64(current) / elapsed.Seconds()
+		parts = append(parts, b.count(int64(rate))+"/s")
+	}
+	// Times are shown to the second, so they do not flicker
+	switch {
+	case b.failed:
+		parts = append(parts, "failed after "+duration.Format(elapsed, duration.Seconds))
+	case b.done:
+		parts = append(parts, duration.Format(elapsed, duration.Seconds))
+	case total > 0 && rate > 0 && current < total:
+		eta := time.Duration(float64(total-current) / rate * float64(time.Second))
+		parts = append(parts, "ETA "+duration.Format(eta, duration.Seconds))
+	}
+	return strings.Join(parts, " ")
+}
+
+// cells returns the bar graphic. An indeterminate bar shows a block moving
+// back and forth until it is done.
+func (g *Group) cells(b *Bar, current, total int64, style, reset string) string {
+	width := g.opts.Width
+	if width <= 0 {
+		width = DefaultWidth
+	}
+	var before, filled int
+	switch {
+	case b.done && !b.failed && total <= 0:
+		filled = width
+	case total > 0:
+		filled = int(percent(current, total) * int64(width) / 100)
+	default:
+		filled = min(3, width)
+		span := width - filled
+		if span > 0 {
+			before = g.frame % (2 * span)
+			if before > span {
+				before = 2*span - before
+			}
+		}
+	}
+	return "[" + strings.Repeat("░", before) + style + strings.Repeat("█", filled) + reset +
+		strings.Repeat("░", width-before-filled) + "]"
+}
+
+func (g *Group) formatter() *formatter.Formatter {
+	if g.opts.Formatter != nil {
+		return g.opts.Formatter
+	}
+	return formatter.Default()
+}
+
+func (g *Group) options() *options.Options {
+	if g.opts.Options != nil {
+		return g.opts.Options
+	}
+	return options.Default().WithIcon()
+}
+
+func percent(current, total int64) int64 {
+	return min(max(current, 0), total) * 100 / total
+}
+
+// Bar is a progress bar of a Group. Its methods are safe for concurrent
+// use.
+type Bar struct {
+	group   *Group
+	current atomic.Int64
+	total   atomic.Int64
+
+	// The remaining fields are guarded by group.mu.
+	msgType messages.Type
+	message string
+	bytes   bool
+	start   time.Time
+	end     time.Time
+	done    bool
+	failed  bool
+	// printed is the value of the last plain line and reported whether
+	// the final one was printed.
+	printed  int64
+	reported bool
+}
+
+// WithBytes shows the counts of the bar as byte sizes, e.g. "4.5 MiB", like
+// field.Bytes.
+func (b *Bar) WithBytes() *Bar {
+	b.group.mu.Lock()
+	defer b.group.mu.Unlock()
+	b.bytes = true
+	return b
+}
+
+// Add advances the bar by n.
+func (b *Bar) Add(n int64) {
+	b.current.Add(n)
+}
+
+// Increment advances the bar by one.
+func (b *Bar) Increment() {
+	b.current.Add(1)
+}
+
+// Set sets the current value of the bar.
+func (b *Bar) Set(n int64) {
+	b.current.Store(n)
+}
+
+// Current returns the current value of the bar.
+func (b *Bar) Current() int64 {
+	return b.current.Load()
+}
+
+// SetTotal changes the total, e.g. once the size of a download is known.
+// Zero or less makes the bar indeterminate.
+func (b *Bar) SetTotal(total int64) {
+	b.total.Store(total)
+}
+
+// Total returns the total of the bar.
+func (b *Bar) Total() int64 {
+	return b.total.Load()
+}
+
+// SetMessage changes the text shown next to the bar.
+func (b *Bar) SetMessage(message string) {
+	b.group.mu.Lock()
+	defer b.group.mu.Unlock()
+	b.message = message
+}
+
+// Done marks the bar as finished. A determinate bar keeps its current
+// value, so a bar stopped early does not show 100%.
+func (b *Bar) Done() {
+	b.finish(false)
+}
+
+// Fail marks the bar as finished unsuccessfully. It is drawn with the
+// style of messages.Error.
+func (b *Bar) Fail() {
+	b.finish(true)
+}
+
+func (b *Bar) finish(failed bool) {
+	b.group.mu.Lock()
+	if !b.done {
+		b.done, b.failed, b.end = true, failed, b.group.now()
+	}
+	b.group.mu.Unlock()
+	b.group.refresh()
+}
+
+// Wait blocks until every bar of the group is done and drawn.
+func (b *Bar) Wait() {
+	b.group.Wait()
+}
+
+// Reader returns a reader advancing the bar by the bytes read from r. The
+// bar counts bytes, see WithBytes.
+func (b *Bar) Reader(r io.Reader) io.Reader {
+	b.WithBytes()
+	return &reader{r: r, bar: b}
+}
+
+// Writer returns a writer advancing the bar by the bytes written to w. The
+// bar counts bytes, see WithBytes.
+func (b *Bar) Writer(w io.Writer) io.Writer {
+	b.WithBytes()
+	return &writer{w: w, bar: b}
+}
+
+func (b *Bar) count(n int64) string {
+	if b.bytes {
+		return field.Bytes(n).String()
+	}
+	return fmt.Sprint(n)
+}
+
+type reader struct {
+	r   io.Reader
+	bar *Bar
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.bar.Add(int64(n))
+	return n, err
+}
+
+type writer struct {
+	w   io.Writer
+	bar *Bar
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.bar.Add(int64(n))
+	return n, err
+}
@@ -0,0 +1,183 @@
+package progress
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jsas4coding/utify/pkg/colors"
+	"github.com/jsas4coding/utify/pkg/formatter"
+	"github.com/jsas4coding/utify/pkg/messages"
+	"github.com/jsas4coding/utify/pkg/options"
+)
+
+var start = time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+// newTestGroup returns a group writing to out without colors or icons, whose
+// clock is at start plus *elapsed.
+func newTestGroup(out io.Writer, plain bool, elapsed *time.Duration) *Group {
+	g := NewGroup(&Options{
+		Output:        out,
+		Formatter:     &formatter.Formatter{Profile: colors.ProfileNone},
+		Options:       options.Default(),
+		Width:         10,
+		PlainInterval: time.Hour,
+		Plain:         plain,
+	})
+	g.plain = plain
+	g.now = func() time.Time { return start.Add(*elapsed) }
+	return g
+}
+
+func TestRenderDeterminate(t *testing.T) {
+	var elapsed time.Duration
+	g := newTestGroup(io.Discard, false, &elapsed)
+	b := g.newBar(messages.Download, "app.tar.gz", 10<<20).WithBytes()
+	elapsed = 2 * time.Second
+	b.Set(4 << 20)
+
+	expected := "app.tar.gz [████░░░░░░]  40% 4.0 MiB/10.0 MiB 2.0 MiB/s ETA 3s"
+	if line := g.render(b, g.now()); line != expected {
+		t.Errorf("Expected %q, got %q", expected, line)
+	}
+
+	b.Done()
+	elapsed = time.Minute
+	expected = "app.tar.gz [████░░░░░░]  40% 4.0 MiB/10.0 MiB 2.0 MiB/s 2s"
+	if line := g.render(b, g.now()); line != expected {
+		t.Errorf("Expected %q after Done, got %q", expected, line)
+	}
+}
+
+func TestRenderIndeterminate(t *testing.T) {
+	var elapsed time.Duration
+	g := newTestGroup(io.Discard, false, &elapsed)
+	b := g.newBar(messages.Install, "packages", 0)
+	elapsed = time.Second
+	b.Add(5)
+
+	frames := []string{"[███░░░░░░░]", "[░███░░░░░░]", "[░░░░░░░███]", "[░░░░░░███░]"}
+	for i, frame := range []int{0, 1, 7, 8} {
+		g.frame = frame
+		if line := g.render(b, g.now()); line != "packages "+frames[i]+" 5 5/s" {
+			t.Errorf("Frame %d: unexpected line %q", frame, line)
+		}
+	}
+
+	b.Done()
+	if line := g.render(b, g.now()); line != "packages [██████████] 5 5/s 1s" {
+		t.Errorf("Unexpected line after Done %q", line)
+	}
+}
+
+func TestRenderFailed(t *testing.T) {
+	var elapsed time.Duration
+	g := newTestGroup(io.Discard, true, &elapsed)
+	b := g.newBar(messages.Upload, "report.pdf", 100)
+	elapsed = 3 * time.Second
+	b.Set(30)
+	b.Fail()
+
+	if line := g.render(b, g.now()); line != "report.pdf  30% 30/100 10/s failed after 3s" {
+		t.Errorf("Unexpected line %q", line)
+	}
+}
+
+func TestRenderStyle(t *testing.T) {
+	var elapsed time.Duration
+	g := newTestGroup(io.Discard, false, &elapsed)
+	g.opts.Formatter = &formatter.Formatter{Profile: colors.ProfileANSI}
+	b := g.newBar(messages.Success, "done", 2)
+	b.Set(1)
+
+	line := g.render(b, g.now())
+	if !strings.HasPrefix(line, colors.Green+"done"+colors.Reset+" ["+colors.Green+"█████"+colors.Reset+"░░░░░]") {
+		t.Errorf("Expected the message and bar in the type's color, got %q", line)
+	}
+}
+
+func TestDrawTerminal(t *testing.T) {
+	var out bytes.Buffer
+	var elapsed time.Duration
+	g := newTestGroup(&out, false, &elapsed)
+	first := g.newBar(messages.Download, "a", 2)
+	second := g.newBar(messages.Download, "b", 2)
+	g.bars = []*Bar{first, second}
+
+	if g.draw() {
+		t.Fatal("Expected unfinished bars")
+	}
+	first.Set(2)
+	first.Done()
+	second.Done()
+	if !g.draw() {
+		t.Fatal("Expected the bars to be finished")
+	}
+
+	expected := "\r\033[Ja [░░░░░░░░░░]   0% 0/2\nb [░░░░░░░░░░]   0% 0/2\n" +
+		"\033[2A\r\033[Ja [██████████] 100% 2/2 0s\nb [░░░░░░░░░░]   0% 0/2 0s\n"
+	if out.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, out.String())
+	}
+	if len(g.bars) != 0 {
+		t.Errorf("Expected finished bars to be removed")
+	}
+}
+
+func TestGroupPlain(t *testing.T) {
+	var out bytes.Buffer
+	var elapsed time.Duration
+	g := newTestGroup(&out, true, &elapsed)
+
+	var wg sync.WaitGroup
+	for _, name := range []string{"a", "b"} {
+		b := g.Add(messages.Download, name, 4)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 4 {
+				b.Increment()
+			}
+			b.Done()
+		}()
+	}
+	wg.Wait()
+	g.Wait()
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	final := 0
+	for _, line := range lines {
+		if strings.HasSuffix(line, "100% 4/4 0s") {
+			final++
+		}
+	}
+	if final != 2 {
+		t.Errorf("Expected a final line per bar, got %q", out.String())
+	}
+	if strings.Contains(out.String(), "█") || strings.Contains(out.String(), "\033") {
+		t.Errorf("Expected plain lines without bars or escapes, got %q", out.String())
+	}
+}
+
+func TestReaderWriter(t *testing.T) {
+	var elapsed time.Duration
+	g := newTestGroup(io.Discard, true, &elapsed)
+	b := g.Add(messages.Download, "copy", 11)
+
+	var dst bytes.Buffer
+	if _, err := io.Copy(b.Writer(&dst), b.Reader(strings.NewReader("hello"))); err != nil {
+		t.Fatal(err)
+	}
+	b.Done()
+	b.Wait()
+
+	if b.Current() != 10 || dst.String() != "hello" {
+		t.Errorf("Expected 10 bytes counted, got %d", b.Current())
+	}
+	if b.count(b.Current()) != "10 B" {
+		t.Errorf("Expected the bar to count bytes, got %q", b.count(b.Current()))
+	}
+}
@@ -0,0 +1,326 @@
+package redact
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jsas4coding/utify/pkg/field"
+)
+
+// Mask replaces redacted values.
+const Mask = "[REDACTED]"
+
+// ErrInvalidRule is wrapped by the error NewRule returns for an invalid
+// pattern.
+var ErrInvalidRule = errors.New("invalid redaction rule")
+
+// Secret is a string that always renders as Mask, on the console, in
+// formatted text and in logs. Reveal returns the actual value.
+type Secret string
+
+// Reveal returns the unmasked value.
+func (s Secret) Reveal() string {
+	return string(s)
+}
+
+// String returns Mask.
+func (Secret) String() string {
+	return Mask
+}
+
+// GoString returns Mask, so %#v does not leak the value either.
+func (Secret) GoString() string {
+	return Mask
+}
+
+// Format writes Mask for every verb, quoted for %q.
+func (Secret) Format(s fmt.State, verb rune) {
+	if verb == 'q' {
+		_, _ = fmt.Fprint(s, strconv.Quote(Mask))
+		return
+	}
+	_, _ = fmt.Fprint(s, Mask)
+}
+
+// MarshalJSON returns Mask as a JSON string.
+func (Secret) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(Mask)), nil
+}
+
+// Rule replaces the matches of a pattern in message text and string field
+// values. Replace is expanded like regexp.Regexp.ReplaceAllString; an empty
+// Replace replaces the whole match with Mask.
+type Rule struct {
+	Name    string
+	Pattern *regexp.Regexp
+	Replace string
+}
+
+// NewRule compiles pattern into a rule. An invalid pattern returns an error
+// wrapping ErrInvalidRule.
+func NewRule(name, pattern, replace string) (Rule, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return Rule{}, fmt.Errorf("%w %q: %v", ErrInvalidRule, name, err)
+	}
+	return Rule{Name: name, Pattern: re, Replace: replace}, nil
+}
+
+// MustRule is like NewRule but panics on an invalid pattern.
+func MustRule(name, pattern, replace string) Rule {
+	r, err := NewRule(name, pattern, replace)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+func (r Rule) apply(s string) string {
+	if r.Replace == "" {
+		return r.Pattern.ReplaceAllLiteralString(s, Mask)
+	}
+	return r.Pattern.ReplaceAllString(s, r.Replace)
+}
+
+// DefaultRules returns the built-in rules for common token shapes: bearer
+// tokens, AWS access key IDs, GitHub tokens, credentials in URLs and
+// assignments such as password=... in text.
+func DefaultRules() []Rule {
+	return []Rule{
+		MustRule("bearer", `(?i)\b(bearer)\s+[A-Za-z0-9\-._~+/]+=*`, "$1 "+Mask),
+		MustRule("aws-access-key", `\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`, ""),
+		MustRule("github-token", `\bgh[pousr]_[A-Za-z0-9]{36,}\b`, ""),
+		MustRule("url-credentials", `\b([a-zA-Z][a-zA-Z0-9+.\-]*://[^:@/\s]*:)[^@/\s]+@`, "${1}"+Mask+"@"),
+		MustRule("assignment",
+			`(?i)\b(password|passwd|pwd|secret|token|api[_-]?key|access[_-]?key)(\s*[=:]\s*)("[^"]*"|[^\s,;&]+)`,
+			"${1}${2}"+Mask),
+	}
+}
+
+// DefaultKeys returns the built-in sensitive field names.
+func DefaultKeys() []string {
+	return []string{
+		"password", "passwd", "secret", "token", "api_key", "apikey", "access_key",
+		"private_key", "authorization", "cookie", "set_cookie", "credentials",
+	}
+}
+
+// Redactor masks secrets in message text and fields. Values of fields with
+// a sensitive name are replaced by Mask, and the rules are applied to
+// message text and string field values. It is safe for concurrent use.
+type Redactor struct {
+	mu      sync.RWMutex
+	enabled bool
+	rules   []Rule
+	keys    []string
+}
+
+// New returns an enabled redactor with the default rules and keys.
+func New() *Redactor {
+	return &Redactor{enabled: true, rules: DefaultRules(), keys: DefaultKeys()}
+}
+
+var std = New()
+
+// Default returns the package-level redactor used by formatters and loggers
+// without their own.
+func Default() *Redactor {
+	return std
+}
+
+// SetEnabled enables or disables redaction. Secret values are masked
+// either way.
+func (r *Redactor) SetEnabled(enabled bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.enabled = enabled
+}
+
+// Enabled reports whether redaction is enabled.
+func (r *Redactor) Enabled() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.enabled
+}
+
+// SetRules replaces the rules.
+func (r *Redactor) SetRules(rules []Rule) {
+	rules = slices.Clone(rules)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rules = rules
+}
+
+// AddRule adds a rule, replacing a rule with the same name in place.
+func (r *Redactor) AddRule(rule Rule) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// String applies the rules without the lock, so the list is copied on
+	// write.
+	rules := slices.Clone(r.rules)
+	if i := slices.IndexFunc(rules, func(x Rule) bool { return x.Name == rule.Name }); i >= 0 {
+		rules[i] = rule
+	} else {
+		rules = append(rules, rule)
+	}
+	r.rules = rules
+}
+
+// RemoveRule removes the rule named name and reports whether it existed.
+func (r *Redactor) RemoveRule(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := slices.IndexFunc(r.rules, func(x Rule) bool { return x.Name == name })
+	if i < 0 {
+		return false
+	}
+	r.rules = slices.Delete(slices.Clone(r.rules), i, i+1)
+	return true
+}
+
+// Rules returns a copy of the rules.
+func (r *Redactor) Rules() []Rule {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slices.Clone(r.rules)
+}
+
+// SetKeys replaces the sensitive field names.
+func (r *Redactor) SetKeys(keys ...string) {
+	keys = normalizeKeys(keys)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.keys = keys
+}
+
+// AddKeys adds sensitive field names.
+func (r *Redactor) AddKeys(keys ...string) {
+	keys = normalizeKeys(keys)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	merged := slices.Clone(r.keys)
+	for _, k := range keys {
+		if !slices.Contains(merged, k) {
+			merged = append(merged, k)
+		}
+	}
+	r.keys = merged
+}
+
+// Keys returns a copy of the sensitive field names.
+func (r *Redactor) Keys() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slices.Clone(r.keys)
+}
+
+// snapshot returns the state used to redact, without copying; the rules and
+// keys are replaced, never modified.
+func (r *Redactor) snapshot() (bool, []Rule, []string) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.enabled, r.rules, r.keys
+}
+
+// String applies the rules to s.
+func (r *Redactor) String(s string) string {
+	enabled, rules, _ := r.snapshot()
+	if !enabled {
+		return s
+	}
+	return applyRules(rules, s)
+}
+
+// Fields returns fields with the values of sensitive keys replaced by Mask
+// and the rules applied to string and error values. fields is returned as
+// is if nothing is redacted.
+func (r *Redactor) Fields(fields []field.Field) []field.Field {
+	enabled, rules, keys := r.snapshot()
+	if !enabled || len(fields) == 0 {
+		return fields
+	}
+	var out []field.Field
+	for i, f := range fields {
+		v, redacted := redactValue(rules, keys, f)
+		if !redacted {
+			continue
+		}
+		if out == nil {
+			out = slices.Clone(fields)
+		}
+		out[i].Value = v
+	}
+	if out == nil {
+		return fields
+	}
+	return out
+}
+
+// IsSensitiveKey reports whether key names a sensitive field. Keys match
+// case-insensitively with '-' and '_' treated alike, either whole or as the
+// last '_' or '.' separated part, e.g. "db_password" or "req.Authorization".
+func (r *Redactor) IsSensitiveKey(key string) bool {
+	_, _, keys := r.snapshot()
+	return isSensitive(keys, key)
+}
+
+// redactValue returns the redacted value of f and whether it differs.
+// Values other than numbers, booleans, durations and times are matched
+// against the rules in their formatted form, so a Stringer such as a
+// *url.URL is replaced by its masked string.
+func redactValue(rules []Rule, keys []string, f field.Field) (any, bool) {
+	var s string
+	switch v := f.Value.(type) {
+	case Secret:
+		return nil, false
+	case nil, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64,
+		field.Bytes, time.Duration, time.Time:
+	case string:
+		s = v
+	default:
+		s = field.Format(v)
+	}
+	if isSensitive(keys, f.Key) {
+		return Mask, true
+	}
+	if s == "" {
+		return nil, false
+	}
+	redacted := applyRules(rules, s)
+	return redacted, redacted != s
+}
+
+func applyRules(rules []Rule, s string) string {
+	for _, rule := range rules {
+		s = rule.apply(s)
+	}
+	return s
+}
+
+func isSensitive(keys []string, key string) bool {
+	key = normalizeKey(key)
+	for _, k := range keys {
+		if key == k || strings.HasSuffix(key, "_"+k) || strings.HasSuffix(key, "."+k) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeKey(key string) string {
+	return strings.ReplaceAll(strings.ToLower(key), "-", "_")
+}
+
+func normalizeKeys(keys []string) []string {
+	out := make([]string, len(keys))
+	for i, k := range keys {
+		out[i] = normalizeKey(k)
+	}
+	return out
+}
@@ -0,0 +1,202 @@
+package sloghandler
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+
+	"github.com/jsas4coding/utify/pkg/field"
+	"github.com/jsas4coding/utify/pkg/formatter"
+	"github.com/jsas4coding/utify/pkg/messages"
+	"github.com/jsas4coding/utify/pkg/options"
+)
+
+// DefaultTypeKey is the attribute key that selects the message type of a
+// record when Options.TypeKey is empty.
+const DefaultTypeKey = "utify.type"
+
+// Options configures a Handler. The zero value renders through the default
+// formatter and maps levels with TypeForLevel.
+type Options struct {
+	// Formatter renders and logs the records. Nil uses formatter.Default().
+	Formatter *formatter.Formatter
+
+	// Options are the display options of every record. Nil uses
+	// options.Default().
+	Options *options.Options
+
+	// Level is the minimum level handled. Nil defers to the formatter's
+	// console and log thresholds for the record's message type.
+	Level slog.Leveler
+
+	// TypeKey is the key of a string attribute naming the message type of a
+	// record, e.g. slog.String("utify.type", "deploy"). It overrides the
+	// level mapping and is not rendered as a field. Empty uses DefaultTypeKey.
+	TypeKey string
+
+	// TypeForLevel maps record levels to message types. Nil uses
+	// TypeForLevel.
+	TypeForLevel func(slog.Level) messages.Type
+}
+
+// Handler is a slog.Handler rendering records through a utify formatter, so
+// they get the icons, colors and layouts of the console output and are
+// written to the utify JSON log. Attributes become message fields; groups
+// qualify their keys with dots.
+type Handler struct {
+	opts   Options
+	prefix string
+	attrs  []field.Field
+	// msgType is set when TypeKey was given to WithAttrs.
+	msgType messages.Type
+}
+
+var _ slog.Handler = (*Handler)(nil)
+
+// New returns a Handler. A nil opts uses the zero Options. To switch a whole
+// program to utify output:
+//
+//	slog.SetDefault(slog.New(sloghandler.New(nil)))
+func New(opts *Options) *Handler {
+	h := &Handler{}
+	if opts != nil {
+		h.opts = *opts
+	}
+	if h.opts.Formatter == nil {
+		h.opts.Formatter = formatter.Default()
+	}
+	if h.opts.Options == nil {
+		h.opts.Options = options.Default()
+	}
+	if h.opts.TypeKey == "" {
+		h.opts.TypeKey = DefaultTypeKey
+	}
+	if h.opts.TypeForLevel == nil {
+		h.opts.TypeForLevel = TypeForLevel
+	}
+	return h
+}
+
+// TypeForLevel is the default level mapping: levels below Info are Debug,
+// below Warn Info, below Error Warning, below Error+4 Error and anything
+// higher Critical.
+func TypeForLevel(level slog.Level) messages.Type {
+	switch {
+	case level < slog.LevelInfo:
+		return messages.Debug
+	case level < slog.LevelWarn:
+		return messages.Info
+	case level < slog.LevelError:
+		return messages.Warning
+	case level < slog.LevelError+4:
+		return messages.Error
+	default:
+		return messages.Critical
+	}
+}
+
+// Enabled reports whether records of the level are handled.
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	if h.opts.Level != nil {
+		return level >= h.opts.Level.Level()
+	}
+	return h.opts.Formatter.Enabled(h.typeFor(level))
+}
+
+// Handle renders the record and writes it to the formatter's log, with its
+// time and the fields extracted from ctx, as for formatter.EchoContext.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	msgType := h.typeFor(r.Level)
+	fields := make([]field.Field, len(h.attrs), len(h.attrs)+r.NumAttrs())
+	copy(fields, h.attrs)
+
+	r.Attrs(func(a slog.Attr) bool {
+		if a.Key == h.opts.TypeKey && h.prefix == "" {
+			msgType = messages.Type(a.Value.Resolve().String())
+			return true
+		}
+		fields = appendAttr(fields, h.prefix, a)
+		return true
+	})
+
+	// Error types return ErrSilent, which is not a handler failure.
+	_, _ = h.opts.Formatter.EchoContextAt(ctx, r.Time, msgType, r.Message, h.opts.Options, fields...)
+	return nil
+}
+
+// WithAttrs returns a Handler that adds attrs to every record.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	h2 := *h
+	h2.attrs = slices.Clip(h.attrs)
+	for _, a := range attrs {
+		if a.Key == h.opts.TypeKey && h.prefix == "" {
+			h2.msgType = messages.Type(a.Value.Resolve().String())
+			continue
+		}
+		h2.attrs = appendAttr(h2.attrs, h.prefix, a)
+	}
+	return &h2
+}
+
+// WithGroup returns a Handler that qualifies the keys of later attributes
+// with name, e.g. "request.id".
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.prefix = h.prefix + name + "."
+	return &h2
+}
+
+func (h *Handler) typeFor(level slog.Level) messages.Type {
+	if h.msgType != "" {
+		return h.msgType
+	}
+	return h.opts.TypeForLevel(level)
+}
+
+// appendAttr appends a as fields, flattening groups into dotted keys.
+func appendAttr(fields []field.Field, prefix string, a slog.Attr) []field.Field {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return fields
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		groupPrefix := prefix
+		if a.Key != "" {
+			groupPrefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			fields = appendAttr(fields, groupPrefix, ga)
+		}
+		return fields
+	}
+	return append(fields, field.F(prefix+a.Key, value(a.Value)))
+}
+
+// value converts v to the Go value utify formats by type.
+func value(v slog.Value) any {
+	switch v.Kind() {
+	case slog.KindString:
+		return v.String()
+	case slog.KindInt64:
+		return v.Int64()
+	case slog.KindUint64:
+		return v.Uint64()
+	case slog.KindFloat64:
+		return v.Float64()
+	case slog.KindBool:
+		return v.Bool()
+	case slog.KindDuration:
+		return v.Duration()
+	case slog.KindTime:
+		return v.Time()
+	default:
+		return v.Any()
+	}
+}
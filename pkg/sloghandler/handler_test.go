@@ -0,0 +1,158 @@
+package sloghandler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jsas4coding/utify/pkg/colors"
+	"github.com/jsas4coding/utify/pkg/ctxfield"
+	"github.com/jsas4coding/utify/pkg/formatter"
+	"github.com/jsas4coding/utify/pkg/logger"
+	"github.com/jsas4coding/utify/pkg/messages"
+	"github.com/jsas4coding/utify/pkg/options"
+)
+
+func newTestHandler(opts *Options) (*Handler, *bytes.Buffer, *bytes.Buffer) {
+	var out, logOut bytes.Buffer
+	f := &formatter.Formatter{
+		Output:      &out,
+		ErrorOutput: &out,
+		Profile:     colors.ProfileNone,
+		Levels:      &options.Levels{Console: messages.SeverityDebug, Log: messages.SeverityDebug},
+		Logger:      logger.NewWriter(&logOut),
+	}
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.Formatter = f
+	return New(opts), &out, &logOut
+}
+
+func TestHandle(t *testing.T) {
+	h, out, logOut := newTestHandler(nil)
+	log := slog.New(h)
+
+	log.Info("deployed", "service", "api", slog.Duration("took", 1500*time.Millisecond))
+	log.Error("failed", "err", errors.New("timeout"))
+
+	expected := "deployed service=api took=1.5s\nfailed err=timeout\n"
+	if out.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, out.String())
+	}
+
+	lines := strings.Split(strings.TrimSpace(logOut.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 log entries, got %d: %q", len(lines), logOut.String())
+	}
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(lines[1]), &entry); err != nil {
+		t.Fatalf("Invalid log entry %q: %v", lines[1], err)
+	}
+	if entry["type"] != "error" || entry["message"] != "failed" || entry["err"] != "timeout" {
+		t.Errorf("Unexpected log entry %v", entry)
+	}
+}
+
+func TestHandleContextAndTime(t *testing.T) {
+	h, _, logOut := newTestHandler(nil)
+	ctx := ctxfield.WithRequestID(context.Background(), "req-1")
+	when := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	r := slog.NewRecord(when, slog.LevelInfo, "handled", 0)
+
+	if err := h.Handle(ctx, r); err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+	var entry map[string]any
+	if err := json.Unmarshal(logOut.Bytes(), &entry); err != nil {
+		t.Fatalf("Invalid log entry %q: %v", logOut.String(), err)
+	}
+	if entry["request_id"] != "req-1" {
+		t.Errorf("Expected the context fields in the log, got %v", entry)
+	}
+	if entry["timestamp"] != "2024-05-01T12:00:00Z" {
+		t.Errorf("Expected the record time, got %v", entry["timestamp"])
+	}
+}
+
+func TestTypeForLevel(t *testing.T) {
+	tests := map[slog.Level]messages.Type{
+		slog.LevelDebug - 4: messages.Debug,
+		slog.LevelDebug:     messages.Debug,
+		slog.LevelInfo:      messages.Info,
+		slog.LevelInfo + 2:  messages.Info,
+		slog.LevelWarn:      messages.Warning,
+		slog.LevelError:     messages.Error,
+		slog.LevelError + 4: messages.Critical,
+	}
+	for level, expected := range tests {
+		if got := TypeForLevel(level); got != expected {
+			t.Errorf("TypeForLevel(%s) = %s, expected %s", level, got, expected)
+		}
+	}
+}
+
+func TestTypeKey(t *testing.T) {
+	h, _, logOut := newTestHandler(nil)
+	log := slog.New(h)
+
+	log.Info("rolled back", DefaultTypeKey, "rollback", "to", "v1.4.2")
+	log.With(DefaultTypeKey, string(messages.Sync)).Warn("synced")
+
+	for _, want := range []string{`"type":"rollback"`, `"to":"v1.4.2"`, `"type":"sync"`} {
+		if !strings.Contains(logOut.String(), want) {
+			t.Errorf("Expected log to contain %s, got %q", want, logOut.String())
+		}
+	}
+	if strings.Contains(logOut.String(), `"utify.type"`) {
+		t.Errorf("Expected the type attribute not to be logged as a field, got %q", logOut.String())
+	}
+
+	custom, _, customLog := newTestHandler(&Options{TypeKey: "kind"})
+	slog.New(custom).Info("indexed", "kind", string(messages.Search))
+	if !strings.Contains(customLog.String(), `"type":"search"`) {
+		t.Errorf("Expected custom type key to apply, got %q", customLog.String())
+	}
+}
+
+func TestGroupsAndAttrs(t *testing.T) {
+	h, out, _ := newTestHandler(nil)
+	log := slog.New(h).With("app", "agent").WithGroup("req").With("id", 7)
+
+	log.Info("served", slog.Group("user", "name", "ana"), "status", 200, slog.Group("empty"))
+
+	expected := "served app=agent req.id=7 req.user.name=ana req.status=200\n"
+	if out.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, out.String())
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	ctx := context.Background()
+
+	h, _, _ := newTestHandler(nil)
+	h.opts.Formatter.Levels = &options.Levels{Console: messages.SeverityWarn, Log: messages.SeverityError}
+	if h.Enabled(ctx, slog.LevelInfo) || !h.Enabled(ctx, slog.LevelWarn) {
+		t.Error("Expected Enabled to follow the formatter thresholds")
+	}
+
+	leveled, _, _ := newTestHandler(&Options{Level: slog.LevelError})
+	if leveled.Enabled(ctx, slog.LevelWarn) || !leveled.Enabled(ctx, slog.LevelError) {
+		t.Error("Expected Enabled to follow Options.Level")
+	}
+}
+
+func TestCustomMapping(t *testing.T) {
+	h, _, logOut := newTestHandler(&Options{
+		TypeForLevel: func(slog.Level) messages.Type { return messages.Git },
+	})
+	slog.New(h).Info("pushed")
+	if !strings.Contains(logOut.String(), `"type":"git"`) {
+		t.Errorf("Expected custom level mapping, got %q", logOut.String())
+	}
+}
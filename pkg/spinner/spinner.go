@@ -0,0 +1,307 @@
+package spinner
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/jsas4coding/utify/pkg/colors"
+	"github.com/jsas4coding/utify/pkg/field"
+	"github.com/jsas4coding/utify/pkg/formatter"
+	"github.com/jsas4coding/utify/pkg/messages"
+	"github.com/jsas4coding/utify/pkg/options"
+	"github.com/jsas4coding/utify/pkg/redact"
+	"github.com/jsas4coding/utify/pkg/terminal"
+)
+
+// ErrUnknownFrames is returned when a built-in frame set name does not
+// exist.
+var ErrUnknownFrames = errors.New("unknown spinner frames")
+
+// Frames is an animation: each frame is shown for Interval, in turn.
+type Frames struct {
+	Name     string
+	Frames   []string
+	Interval time.Duration
+}
+
+// Built-in frame sets.
+var (
+	Dots = Frames{
+		Name:     "dots",
+		Frames:   []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"},
+		Interval: 80 * time.Millisecond,
+	}
+	Line = Frames{
+		Name:     "line",
+		Frames:   []string{"-", "\\", "|", "/"},
+		Interval: 130 * time.Millisecond,
+	}
+	// NerdFont requires a Nerd Font (nf-md-circle_slice_1 to 8).
+	NerdFont = Frames{
+		Name: "nerd-font",
+		Frames: []string{"\U000f0a9e", "\U000f0a9f", "\U000f0aa0", "\U000f0aa1",
+			"\U000f0aa2", "\U000f0aa3", "\U000f0aa4", "\U000f0aa5"},
+		Interval: 100 * time.Millisecond,
+	}
+)
+
+var builtins = []Frames{Dots, Line, NerdFont}
+
+// Builtin returns the built-in frame set with the given name.
+func Builtin(name string) (Frames, error) {
+	for _, f := range builtins {
+		if f.Name == name {
+			return f, nil
+		}
+	}
+	return Frames{}, fmt.Errorf("%w: '%s'", ErrUnknownFrames, name)
+}
+
+// Names returns the names of the built-in frame sets.
+func Names() []string {
+	names := make([]string, len(builtins))
+	for i, f := range builtins {
+		names[i] = f.Name
+	}
+	return names
+}
+
+var (
+	mu     sync.RWMutex
+	frames = Dots
+)
+
+// SetFrames sets the frames of spinners started afterwards.
+func SetFrames(f Frames) {
+	mu.Lock()
+	defer mu.Unlock()
+	frames = f
+}
+
+// GetFrames returns the frames of new spinners.
+func GetFrames() Frames {
+	mu.RLock()
+	defer mu.RUnlock()
+	return frames
+}
+
+// Escape sequences hiding and showing the cursor.
+const (
+	hideCursor = "\033[?25l"
+	showCursor = "\033[?25h"
+)
+
+// Hooks replaced by tests.
+var (
+	isInteractive = terminal.IsInteractive
+	notify        = signal.Notify
+	raise         = func(sig os.Signal) {
+		p, err := os.FindProcess(os.Getpid())
+		if err == nil {
+			err = p.Signal(sig)
+		}
+		if err != nil {
+			os.Exit(130)
+		}
+	}
+)
+
+// Spinner animates a frame and a text on the console output of its message
+// type until it is finished with a message. It only animates on a
+// terminal; elsewhere nothing is shown until it finishes. Other output to
+// the same terminal should wait until the spinner is finished. Its methods
+// are safe for concurrent use.
+type Spinner struct {
+	formatter *formatter.Formatter
+	msgType   messages.Type
+	opts      *options.Options
+	out       io.Writer
+
+	mu     sync.Mutex
+	region *terminal.Region
+	text   string
+	frames Frames
+	frame  int
+	active bool
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// Start starts a spinner for a message of msgType rendered by f, which
+// uses formatter.Default() if nil. The spinner takes the color of msgType
+// and the style of opts, which are also used for the finishing message.
+//
+// The cursor is hidden while the spinner runs. On an interrupt or SIGTERM
+// the spinner stops, restores the cursor and re-sends the signal, so the
+// program ends as it would have without a spinner.
+func Start(f *formatter.Formatter, msgType messages.Type, text string, opts *options.Options) *Spinner {
+	if f == nil {
+		f = formatter.Default()
+	}
+	s := &Spinner{formatter: f, msgType: msgType, opts: opts, frames: GetFrames()}
+	s.text = s.redact(text)
+
+	out := f.ConsoleOutput(msgType, opts)
+	if out == nil || !isInteractive(out) {
+		return s
+	}
+	s.out = out
+	s.region = terminal.NewRegion(out)
+	s.active = true
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	sigs := make(chan os.Signal, 1)
+	notify(sigs, os.Interrupt, syscall.SIGTERM)
+
+	s.mu.Lock()
+	_, _ = io.WriteString(out, hideCursor)
+	s.draw()
+	s.mu.Unlock()
+	go s.run(sigs)
+	return s
+}
+
+func (s *Spinner) run(sigs chan os.Signal) {
+	defer close(s.done)
+	defer signal.Stop(sigs)
+	for {
+		s.mu.Lock()
+		interval := s.frames.Interval
+		s.mu.Unlock()
+		if interval <= 0 {
+			interval = Dots.Interval
+		}
+		timer := time.NewTimer(interval)
+
+		select {
+		case <-s.stop:
+			timer.Stop()
+			return
+		case sig := <-sigs:
+			timer.Stop()
+			s.mu.Lock()
+			interrupted := s.active
+			if interrupted {
+				s.active = false
+				s.region.Clear()
+				_, _ = io.WriteString(s.out, showCursor)
+			}
+			s.mu.Unlock()
+			if interrupted {
+				// Stop first, or the signal comes back to sigs and is lost.
+				signal.Stop(sigs)
+				raise(sig)
+			}
+			return
+		case <-timer.C:
+			s.mu.Lock()
+			s.frame++
+			s.draw()
+			s.mu.Unlock()
+		}
+	}
+}
+
+// draw writes the current frame and text. s.mu must be held.
+func (s *Spinner) draw() {
+	if !s.active {
+		return
+	}
+	frame := ""
+	if len(s.frames.Frames) > 0 {
+		frame = s.frames.Frames[s.frame%len(s.frames.Frames)]
+	}
+	_, style := s.formatter.Decoration(s.out, s.msgType, s.opts)
+	reset := ""
+	if style != "" {
+		reset = colors.Reset
+	}
+	s.region.Draw([]string{style + frame + " " + s.text + reset})
+}
+
+// SetText changes the text shown next to the frame.
+func (s *Spinner) SetText(text string) {
+	text = s.redact(text)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.text = text
+	s.draw()
+}
+
+// Text returns the text shown next to the frame.
+func (s *Spinner) Text() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.text
+}
+
+// SetFrames changes the animation.
+func (s *Spinner) SetFrames(f Frames) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.frames = f
+}
+
+// Active reports whether the spinner is animating.
+func (s *Spinner) Active() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.active
+}
+
+// Stop clears the spinner line and restores the cursor without printing a
+// message.
+func (s *Spinner) Stop() {
+	s.mu.Lock()
+	active := s.active
+	s.active = false
+	s.mu.Unlock()
+	if !active {
+		return
+	}
+	close(s.stop)
+	<-s.done
+	s.region.Clear()
+	_, _ = io.WriteString(s.out, showCursor)
+}
+
+// Finish stops the spinner and prints and logs text as a message of
+// msgType in its place, like formatter.Echo with the spinner's options.
+func (s *Spinner) Finish(msgType messages.Type, text string, fields ...field.Field) {
+	s.Stop()
+	_, _ = s.formatter.Echo(msgType, text, s.opts, fields...)
+}
+
+// Success finishes the spinner with a success message.
+func (s *Spinner) Success(text string, fields ...field.Field) {
+	s.Finish(messages.Success, text, fields...)
+}
+
+// Error finishes the spinner with an error message.
+func (s *Spinner) Error(text string, fields ...field.Field) {
+	s.Finish(messages.Error, text, fields...)
+}
+
+// Warning finishes the spinner with a warning message.
+func (s *Spinner) Warning(text string, fields ...field.Field) {
+	s.Finish(messages.Warning, text, fields...)
+}
+
+// Info finishes the spinner with an info message.
+func (s *Spinner) Info(text string, fields ...field.Field) {
+	s.Finish(messages.Info, text, fields...)
+}
+
+func (s *Spinner) redact(text string) string {
+	if s.formatter.Redactor != nil {
+		return s.formatter.Redactor.String(text)
+	}
+	return redact.Default().String(text)
+}
@@ -0,0 +1,226 @@
+package spinner
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jsas4coding/utify/pkg/colors"
+	"github.com/jsas4coding/utify/pkg/formatter"
+	"github.com/jsas4coding/utify/pkg/layout"
+	"github.com/jsas4coding/utify/pkg/logger"
+	"github.com/jsas4coding/utify/pkg/messages"
+	"github.com/jsas4coding/utify/pkg/options"
+)
+
+// syncBuffer is a bytes.Buffer safe for the spinner goroutine.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+var still = Frames{Name: "still", Frames: []string{"*", "+"}, Interval: time.Hour}
+
+func newFormatter(out io.Writer, logBuf *bytes.Buffer) *formatter.Formatter {
+	return &formatter.Formatter{
+		Output:      out,
+		ErrorOutput: out,
+		Profile:     colors.ProfileNone,
+		Layouts:     layout.Layouts{messages.Default: layout.MustParse("{message}")},
+		Logger:      logger.NewWriter(logBuf),
+	}
+}
+
+// fakeTerminal makes every writer a terminal and captures the signal
+// channel and re-sent signals until the test ends.
+func fakeTerminal(t *testing.T) (sigs func() chan<- os.Signal, raised func() os.Signal) {
+	var (
+		mu     sync.Mutex
+		ch     chan<- os.Signal
+		signal os.Signal
+	)
+	origInteractive, origNotify, origRaise := isInteractive, notify, raise
+	isInteractive = func(io.Writer) bool { return true }
+	notify = func(c chan<- os.Signal, _ ...os.Signal) {
+		mu.Lock()
+		defer mu.Unlock()
+		ch = c
+	}
+	raise = func(sig os.Signal) {
+		mu.Lock()
+		defer mu.Unlock()
+		signal = sig
+	}
+	t.Cleanup(func() { isInteractive, notify, raise = origInteractive, origNotify, origRaise })
+	return func() chan<- os.Signal {
+			mu.Lock()
+			defer mu.Unlock()
+			return ch
+		}, func() os.Signal {
+			mu.Lock()
+			defer mu.Unlock()
+			return signal
+		}
+}
+
+func TestNotTerminal(t *testing.T) {
+	var out, logBuf bytes.Buffer
+	s := Start(newFormatter(&out, &logBuf), messages.Sync, "Syncing repos", options.Default())
+	if s.Active() || out.Len() > 0 {
+		t.Fatalf("Expected no animation, got %q", out.String())
+	}
+	s.SetText("Still syncing")
+	s.Success("Synced 3 repos")
+
+	if out.String() != "Synced 3 repos\n" {
+		t.Errorf("Expected only the final message, got %q", out.String())
+	}
+	if !strings.Contains(logBuf.String(), `"message":"Synced 3 repos"`) {
+		t.Errorf("Expected the final message to be logged, got %q", logBuf.String())
+	}
+}
+
+func TestAnimation(t *testing.T) {
+	fakeTerminal(t)
+	defer SetFrames(GetFrames())
+	SetFrames(still)
+	var out syncBuffer
+	var logBuf bytes.Buffer
+	s := Start(newFormatter(&out, &logBuf), messages.Sync, "Syncing repos", options.Default())
+	s.SetText("Syncing 2/3")
+	s.SetFrames(Frames{Frames: []string{"+"}, Interval: time.Hour})
+	s.Error("Sync failed")
+
+	expected := hideCursor + "\r\033[J* Syncing repos\n" + "\033[1A\r\033[J* Syncing 2/3\n" +
+		"\033[1A\r\033[J" + showCursor + "Sync failed\n"
+	if out.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, out.String())
+	}
+	if s.Active() {
+		t.Error("Expected the spinner to be stopped")
+	}
+	s.Stop()
+	if !strings.HasSuffix(out.String(), "Sync failed\n") {
+		t.Error("Expected Stop after Finish to write nothing")
+	}
+}
+
+func TestFrames(t *testing.T) {
+	fakeTerminal(t)
+	defer SetFrames(GetFrames())
+	SetFrames(Frames{Frames: []string{"a", "b"}, Interval: time.Millisecond})
+
+	var out syncBuffer
+	var logBuf bytes.Buffer
+	s := Start(newFormatter(&out, &logBuf), messages.Sync, "x", options.Default())
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(out.String(), "b x") && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	s.Stop()
+
+	if !strings.Contains(out.String(), "a x") || !strings.Contains(out.String(), "b x") {
+		t.Errorf("Expected the frames in turn, got %q", out.String())
+	}
+}
+
+func TestInterrupt(t *testing.T) {
+	sigs, raised := fakeTerminal(t)
+	defer SetFrames(GetFrames())
+	SetFrames(still)
+	var out syncBuffer
+	var logBuf bytes.Buffer
+	s := Start(newFormatter(&out, &logBuf), messages.Sync, "Syncing", options.Default())
+
+	sigs() <- os.Interrupt
+	deadline := time.Now().Add(5 * time.Second)
+	for s.Active() && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	<-s.done
+
+	if raised() != os.Interrupt {
+		t.Errorf("Expected the interrupt to be re-sent, got %v", raised())
+	}
+	if !strings.HasSuffix(out.String(), "\033[1A\r\033[J"+showCursor) {
+		t.Errorf("Expected the cursor to be restored, got %q", out.String())
+	}
+	s.Stop()
+	if strings.Count(out.String(), showCursor) != 1 {
+		t.Errorf("Expected the cursor to be restored once, got %q", out.String())
+	}
+}
+
+// TestInterruptEndsProgram runs itself in a subprocess with a spinner,
+// interrupts it and checks that the re-sent signal ends it.
+func TestInterruptEndsProgram(t *testing.T) {
+	if os.Getenv("UTIFY_TEST_SPINNER_SIGNAL") != "" {
+		isInteractive = func(io.Writer) bool { return true }
+		var out, logBuf bytes.Buffer
+		Start(newFormatter(&out, &logBuf), messages.Sync, "Syncing", options.Default())
+		_, _ = os.Stdout.WriteString("ready\n")
+		time.Sleep(10 * time.Second)
+		os.Exit(3)
+	}
+	if runtime.GOOS == "windows" {
+		t.Skip("interrupts cannot be sent to a process on Windows")
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestInterruptEndsProgram$")
+	cmd.Env = append(os.Environ(), "UTIFY_TEST_SPINNER_SIGNAL=1")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatalf("StdoutPipe failed: %v", err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	ready := make([]byte, len("ready\n"))
+	if _, err := io.ReadFull(stdout, ready); err != nil {
+		t.Fatalf("Expected the subprocess to start a spinner: %v", err)
+	}
+	if err := cmd.Process.Signal(os.Interrupt); err != nil {
+		t.Fatalf("Signal failed: %v", err)
+	}
+	err = cmd.Wait()
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != -1 {
+		t.Errorf("Expected the interrupt to end the program, got %v", err)
+	}
+}
+
+func TestBuiltin(t *testing.T) {
+	for _, name := range Names() {
+		f, err := Builtin(name)
+		if err != nil || f.Name != name || len(f.Frames) == 0 || f.Interval <= 0 {
+			t.Errorf("Invalid built-in frames %q: %+v, %v", name, f, err)
+		}
+	}
+	if !slices.Equal(Names(), []string{"dots", "line", "nerd-font"}) {
+		t.Errorf("Unexpected names %v", Names())
+	}
+	if _, err := Builtin("bogus"); !errors.Is(err, ErrUnknownFrames) {
+		t.Errorf("Expected ErrUnknownFrames, got %v", err)
+	}
+}
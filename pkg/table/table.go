@@ -0,0 +1,397 @@
+package table
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jsas4coding/utify/pkg/colors"
+	"github.com/jsas4coding/utify/pkg/formatter"
+	"github.com/jsas4coding/utify/pkg/messages"
+	"github.com/jsas4coding/utify/pkg/options"
+	"github.com/jsas4coding/utify/pkg/redact"
+	"github.com/jsas4coding/utify/pkg/terminal"
+)
+
+// Align is the alignment of a column.
+type Align int
+
+// Alignments. Columns are left-aligned by default.
+const (
+	AlignLeft Align = iota
+	AlignRight
+	AlignCenter
+)
+
+// Line is a horizontal rule of a border. A rule with an empty Fill is not
+// drawn.
+type Line struct {
+	Left, Fill, Mid, Right string
+}
+
+// Border is the set of characters drawn around and between cells.
+type Border struct {
+	// Top, Header and Bottom are the rules above the table, under the
+	// header and under the table.
+	Top, Header, Bottom Line
+
+	// Left, Mid and Right separate the cells of a row.
+	Left, Mid, Right string
+
+	// Padding is the number of spaces on each side of a cell.
+	Padding int
+
+	// Markdown escapes pipes in cells and marks the alignment of columns
+	// in the header rule.
+	Markdown bool
+}
+
+// Built-in borders. The zero Border is None.
+var (
+	// None separates columns with two spaces.
+	None = Border{Mid: "  "}
+
+	// ASCII draws boxes with +, - and |.
+	ASCII = Border{
+		Top:     Line{"+", "-", "+", "+"},
+		Header:  Line{"+", "-", "+", "+"},
+		Bottom:  Line{"+", "-", "+", "+"},
+		Left:    "|",
+		Mid:     "|",
+		Right:   "|",
+		Padding: 1,
+	}
+
+	// Rounded draws boxes with rounded corners.
+	Rounded = Border{
+		Top:     Line{"╭", "─", "┬", "╮"},
+		Header:  Line{"├", "─", "┼", "┤"},
+		Bottom:  Line{"╰", "─", "┴", "╯"},
+		Left:    "│",
+		Mid:     "│",
+		Right:   "│",
+		Padding: 1,
+	}
+
+	// Markdown renders a GitHub-flavored markdown table.
+	Markdown = Border{
+		Header:   Line{"|", "-", "|", "|"},
+		Left:     "|",
+		Mid:      "|",
+		Right:    "|",
+		Padding:  1,
+		Markdown: true,
+	}
+)
+
+// Options configures a Table. The zero value renders through the default
+// formatter.
+type Options struct {
+	// Formatter provides the colors and icons of styled rows and cells,
+	// masks secrets in cells and prints the table. Nil uses
+	// formatter.Default().
+	Formatter *formatter.Formatter
+
+	// Options are the display options of styled rows and cells. Nil shows
+	// icons.
+	Options *options.Options
+}
+
+// Cell is a cell styled like messages of Type, with its color and icon.
+type Cell struct {
+	Type  messages.Type
+	Value any
+}
+
+// Styled returns value as a cell styled like messages of msgType.
+func Styled(msgType messages.Type, value any) Cell {
+	return Cell{Type: msgType, Value: value}
+}
+
+type column struct {
+	header   string
+	align    Align
+	maxWidth int
+}
+
+type row struct {
+	styled  bool
+	msgType messages.Type
+	cells   []any
+}
+
+// Table renders rows in aligned columns. Widths are computed from the
+// visible width of cells, so colors are ignored and emoji count as two
+// columns.
+type Table struct {
+	formatter *formatter.Formatter
+	opts      *options.Options
+	border    Border
+	columns   []column
+	rows      []row
+}
+
+// New returns a table with a column per header. A nil opts uses the zero
+// Options.
+func New(opts *Options, headers ...string) *Table {
+	var o Options
+	if opts != nil {
+		o = *opts
+	}
+	t := &Table{formatter: o.Formatter, opts: o.Options, border: None}
+	if t.formatter == nil {
+		t.formatter = formatter.Default()
+	}
+	if t.opts == nil {
+		t.opts = options.Default().WithIcon()
+	}
+	for _, header := range headers {
+		t.columns = append(t.columns, column{header: header})
+	}
+	return t
+}
+
+// SetBorder sets the border style. The zero Border is None.
+func (t *Table) SetBorder(b Border) *Table {
+	if b == (Border{}) {
+		b = None
+	}
+	t.border = b
+	return t
+}
+
+// SetAlign sets the alignment of column i, counting from zero.
+func (t *Table) SetAlign(i int, align Align) *Table {
+	t.column(i).align = align
+	return t
+}
+
+// SetMaxWidth truncates the cells of column i, counting from zero, wider
+// than width columns with an ellipsis. Zero or less removes the limit.
+func (t *Table) SetMaxWidth(i int, width int) *Table {
+	t.column(i).maxWidth = width
+	return t
+}
+
+func (t *Table) column(i int) *column {
+	for len(t.columns) <= i {
+		t.columns = append(t.columns, column{})
+	}
+	return &t.columns[i]
+}
+
+// AddRow adds a row. Cells are formatted with fmt.Sprint; a Cell is
+// styled on its own.
+func (t *Table) AddRow(cells ...any) *Table {
+	t.rows = append(t.rows, row{cells: cells})
+	return t
+}
+
+// AddStyledRow adds a row styled like messages of msgType: its cells take
+// the type's color, and the icon is shown before the first cell.
+func (t *Table) AddStyledRow(msgType messages.Type, cells ...any) *Table {
+	t.rows = append(t.rows, row{styled: true, msgType: msgType, cells: cells})
+	return t
+}
+
+// Print renders the table on the formatter's console output for info
+// messages, if any.
+func (t *Table) Print() {
+	if out := t.formatter.ConsoleOutput(messages.Info, t.opts); out != nil {
+		_ = t.Render(out)
+	}
+}
+
+// String returns the table as rendered to a writer that is not a
+// terminal.
+func (t *Table) String() string {
+	var b strings.Builder
+	_ = t.Render(&b)
+	return b.String()
+}
+
+// rendered is a cell ready to be padded.
+type rendered struct {
+	text, style string
+}
+
+// Render writes the table to w, with colors if w supports them.
+func (t *Table) Render(w io.Writer) error {
+	columns := len(t.columns)
+	for _, r := range t.rows {
+		columns = max(columns, len(r.cells))
+	}
+	if columns == 0 {
+		return nil
+	}
+
+	header := make([]rendered, columns)
+	showHeader := t.border.Markdown
+	for i, c := range t.columns {
+		header[i].text = t.text(c.header)
+		showHeader = showHeader || c.header != ""
+	}
+	body := make([][]rendered, len(t.rows))
+	for i, r := range t.rows {
+		body[i] = t.renderRow(w, r, columns)
+	}
+
+	widths := make([]int, columns)
+	for i := range widths {
+		limit := 0
+		if i < len(t.columns) {
+			limit = t.columns[i].maxWidth
+		}
+		if showHeader {
+			header[i].text = truncate(header[i].text, limit)
+			widths[i] = terminal.Width(header[i].text)
+		}
+		for _, cells := range body {
+			cells[i].text = truncate(cells[i].text, limit)
+			widths[i] = max(widths[i], terminal.Width(cells[i].text))
+		}
+		if t.border.Markdown {
+			// A header rule needs at least three dashes
+			widths[i] = max(widths[i], 3-2*t.border.Padding)
+		}
+	}
+
+	var b strings.Builder
+	t.rule(&b, t.border.Top, widths)
+	if showHeader {
+		t.line(&b, header, widths)
+		t.rule(&b, t.border.Header, widths)
+	}
+	for _, cells := range body {
+		t.line(&b, cells, widths)
+	}
+	t.rule(&b, t.border.Bottom, widths)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// renderRow returns the cells of r with their styles, padded to columns.
+func (t *Table) renderRow(w io.Writer, r row, columns int) []rendered {
+	cells := make([]rendered, columns)
+	var icon, style string
+	if r.styled {
+		icon, style = t.formatter.Decoration(w, r.msgType, t.opts)
+	}
+	for i := range cells {
+		cells[i].style = style
+		if i >= len(r.cells) {
+			continue
+		}
+		value := r.cells[i]
+		cellIcon := ""
+		if c, ok := value.(Cell); ok {
+			cellIcon, cells[i].style = t.formatter.Decoration(w, c.Type, t.opts)
+			value = c.Value
+		}
+		// Icons are trimmed of the spaces some carry for terminals drawing
+		// them narrow, since widths are computed here
+		parts := make([]string, 0, 3)
+		if i == 0 && strings.TrimSpace(icon) != "" {
+			parts = append(parts, strings.TrimSpace(icon))
+		}
+		if cellIcon = strings.TrimSpace(cellIcon); cellIcon != "" {
+			parts = append(parts, cellIcon)
+		}
+		cells[i].text = strings.Join(append(parts, t.text(fmt.Sprint(value))), " ")
+	}
+	return cells
+}
+
+// text returns s masked and on a single line.
+func (t *Table) text(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", " ")
+	s = strings.ReplaceAll(s, "\n", " ")
+	if t.formatter.Redactor != nil {
+		s = t.formatter.Redactor.String(s)
+	} else {
+		s = redact.Default().String(s)
+	}
+	if t.border.Markdown {
+		s = strings.ReplaceAll(s, "|", `\|`)
+	}
+	return s
+}
+
+// line writes a row of cells.
+func (t *Table) line(b *strings.Builder, cells []rendered, widths []int) {
+	var line strings.Builder
+	pad := strings.Repeat(" ", t.border.Padding)
+	line.WriteString(t.border.Left)
+	for i, c := range cells {
+		if i > 0 {
+			line.WriteString(t.border.Mid)
+		}
+		text := c.text
+		if c.style != "" && text != "" {
+			text = c.style + text + colors.Reset
+		}
+		line.WriteString(pad + align(text, widths[i], t.alignment(i)) + pad)
+	}
+	line.WriteString(t.border.Right)
+	s := line.String()
+	if t.border.Right == "" {
+		s = strings.TrimRight(s, " ")
+	}
+	b.WriteString(s + "\n")
+}
+
+// rule writes a horizontal rule, if the border has one.
+func (t *Table) rule(b *strings.Builder, l Line, widths []int) {
+	if l.Fill == "" {
+		return
+	}
+	b.WriteString(l.Left)
+	for i, width := range widths {
+		if i > 0 {
+			b.WriteString(l.Mid)
+		}
+		width += 2 * t.border.Padding
+		if !t.border.Markdown {
+			b.WriteString(strings.Repeat(l.Fill, width))
+			continue
+		}
+		switch t.alignment(i) {
+		case AlignRight:
+			b.WriteString(strings.Repeat(l.Fill, width-1) + ":")
+		case AlignCenter:
+			b.WriteString(":" + strings.Repeat(l.Fill, width-2) + ":")
+		default:
+			b.WriteString(strings.Repeat(l.Fill, width))
+		}
+	}
+	b.WriteString(l.Right + "\n")
+}
+
+func (t *Table) alignment(i int) Align {
+	if i < len(t.columns) {
+		return t.columns[i].align
+	}
+	return AlignLeft
+}
+
+// align pads s to width visible columns.
+func align(s string, width int, a Align) string {
+	space := width - terminal.Width(s)
+	if space <= 0 {
+		return s
+	}
+	switch a {
+	case AlignRight:
+		return strings.Repeat(" ", space) + s
+	case AlignCenter:
+		return strings.Repeat(" ", space/2) + s + strings.Repeat(" ", space-space/2)
+	}
+	return s + strings.Repeat(" ", space)
+}
+
+func truncate(s string, width int) string {
+	if width <= 0 {
+		return s
+	}
+	return terminal.Truncate(s, width)
+}
@@ -0,0 +1,110 @@
+package table
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/jsas4coding/utify/pkg/colors"
+	"github.com/jsas4coding/utify/pkg/formatter"
+	"github.com/jsas4coding/utify/pkg/messages"
+	"github.com/jsas4coding/utify/pkg/options"
+	"github.com/jsas4coding/utify/pkg/terminal"
+)
+
+func newTable(profile colors.Profile) *Table {
+	t := New(&Options{Formatter: &formatter.Formatter{Profile: profile}}, "NAME", "STATUS", "AGE")
+	t.SetAlign(2, AlignRight)
+	t.AddRow("api", "running 🚀", 3)
+	t.AddStyledRow(messages.Error, "worker", "failed", 12)
+	t.AddRow("db", Styled(messages.Success, "ready"), "1h")
+	return t
+}
+
+func TestBorders(t *testing.T) {
+	tests := map[string]struct {
+		border   Border
+		expected string
+	}{
+		"none": {None, `NAME       STATUS      AGE
+api        running 🚀    3
+❌ worker  failed       12
+db         ✅ ready     1h
+`},
+		"ascii": {ASCII, `+-----------+------------+-----+
+| NAME      | STATUS     | AGE |
++-----------+------------+-----+
+| api       | running 🚀 |   3 |
+| ❌ worker | failed     |  12 |
+| db        | ✅ ready   |  1h |
++-----------+------------+-----+
+`},
+		"rounded": {Rounded, `╭───────────┬────────────┬─────╮
+│ NAME      │ STATUS     │ AGE │
+├───────────┼────────────┼─────┤
+│ api       │ running 🚀 │   3 │
+│ ❌ worker │ failed     │  12 │
+│ db        │ ✅ ready   │  1h │
+╰───────────┴────────────┴─────╯
+`},
+		"markdown": {Markdown, `| NAME      | STATUS     | AGE |
+|-----------|------------|----:|
+| api       | running 🚀 |   3 |
+| ❌ worker | failed     |  12 |
+| db        | ✅ ready   |  1h |
+`},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if s := newTable(colors.ProfileNone).SetBorder(tt.border).String(); s != tt.expected {
+				t.Errorf("Expected:\n%s\ngot:\n%s", tt.expected, s)
+			}
+		})
+	}
+}
+
+func TestStyles(t *testing.T) {
+	var out bytes.Buffer
+	if err := newTable(colors.ProfileANSI).Render(&out); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	lines := strings.Split(out.String(), "\n")
+	if !strings.Contains(lines[2], colors.Red+"❌ worker"+colors.Reset) ||
+		!strings.Contains(lines[2], colors.Red+"failed"+colors.Reset) {
+		t.Errorf("Expected the failed row in red, got %q", lines[2])
+	}
+	if !strings.Contains(lines[3], colors.Green+"✅ ready"+colors.Reset) || strings.Contains(lines[3], "db"+colors.Reset) {
+		t.Errorf("Expected only the status cell in green, got %q", lines[3])
+	}
+	// Colors take no columns
+	if plain := newTable(colors.ProfileNone).String(); terminal.StripANSI(out.String()) != plain {
+		t.Errorf("Expected the columns aligned as without colors, got:\n%s", out.String())
+	}
+}
+
+func TestTruncation(t *testing.T) {
+	tb := New(&Options{Formatter: &formatter.Formatter{Profile: colors.ProfileNone}, Options: options.Default()},
+		"NAME", "STATUS")
+	tb.SetMaxWidth(0, 8).SetAlign(1, AlignCenter)
+	tb.AddRow("worker-with-a-long-name", "ok")
+	tb.AddStyledRow(messages.Error, "db", "failed", "extra")
+	expected := `NAME      STATUS
+worker-…    ok
+db        failed  extra
+`
+	if s := tb.String(); s != expected {
+		t.Errorf("Expected:\n%q\ngot:\n%q", expected, s)
+	}
+}
+
+func TestCells(t *testing.T) {
+	tb := New(&Options{Formatter: &formatter.Formatter{Profile: colors.ProfileNone}}, "NOTE").SetBorder(Markdown)
+	tb.AddRow("a | b\nnext line token=secret123")
+	s := tb.String()
+	if !strings.Contains(s, `a \| b next line`) || strings.Contains(s, "secret123") {
+		t.Errorf("Expected cells escaped, joined and masked, got %q", s)
+	}
+	if New(nil).String() != "" {
+		t.Error("Expected an empty table to render nothing")
+	}
+}
@@ -0,0 +1,474 @@
+package tasks
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jsas4coding/utify/pkg/colors"
+	"github.com/jsas4coding/utify/pkg/duration"
+	"github.com/jsas4coding/utify/pkg/field"
+	"github.com/jsas4coding/utify/pkg/formatter"
+	"github.com/jsas4coding/utify/pkg/messages"
+	"github.com/jsas4coding/utify/pkg/options"
+	"github.com/jsas4coding/utify/pkg/redact"
+	"github.com/jsas4coding/utify/pkg/spinner"
+	"github.com/jsas4coding/utify/pkg/terminal"
+)
+
+// State is the state of a task.
+type State int
+
+const (
+	// Pending tasks have not started.
+	Pending State = iota
+	// Running tasks have started and not finished.
+	Running
+	// Done tasks finished successfully.
+	Done
+	// Skipped tasks were not run.
+	Skipped
+	// Failed tasks finished with an error.
+	Failed
+)
+
+// String returns the lower-case name of the state, e.g. "running".
+func (s State) String() string {
+	switch s {
+	case Pending:
+		return "pending"
+	case Running:
+		return "running"
+	case Done:
+		return "done"
+	case Skipped:
+		return "skipped"
+	case Failed:
+		return "failed"
+	}
+	return fmt.Sprintf("State(%d)", int(s))
+}
+
+// markers are the symbols of the states; running tasks show a spinner
+// frame instead when the list is redrawn.
+var markers = map[State]string{
+	Pending: "○",
+	Running: "▶",
+	Done:    "✔",
+	Skipped: "↷",
+	Failed:  "✖",
+}
+
+// styles are the message types whose colors the states take.
+var styles = map[State]messages.Type{
+	Pending: messages.Debug,
+	Running: messages.Sync,
+	Done:    messages.Success,
+	Skipped: messages.Warning,
+	Failed:  messages.Error,
+}
+
+// isInteractive is replaced by tests.
+var isInteractive = terminal.IsInteractive
+
+// Options configures a List. The zero value renders through the default
+// formatter.
+type Options struct {
+	// Formatter renders the list on its console output for info messages,
+	// and prints and logs the summary. Nil uses formatter.Default().
+	Formatter *formatter.Formatter
+
+	// Options are the display options of the summary and task lines.
+	// Nil uses options.Default().
+	Options *options.Options
+
+	// Frames animate running tasks. Empty frames use spinner.GetFrames().
+	Frames spinner.Frames
+
+	// Plain prints a line per state change instead of redrawing the list,
+	// even on a terminal.
+	Plain bool
+}
+
+// List shows the state of tasks and their sub-tasks. On a terminal the
+// list is redrawn in place with a spinner on running tasks; elsewhere a line
+// is printed whenever a task starts or finishes. Finished tasks are logged.
+// Other output to the same terminal should wait until the list is
+// finished. Its methods and those of its tasks are safe for concurrent use.
+type List struct {
+	formatter *formatter.Formatter
+	opts      *options.Options
+	frames    spinner.Frames
+	out       io.Writer
+	plain     bool
+	now       func() time.Time
+
+	mu       sync.Mutex
+	tasks    []*Task
+	start    time.Time
+	region   *terminal.Region
+	frame    int
+	finished bool
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// New returns an empty list. A nil opts uses the zero Options.
+func New(opts *Options) *List {
+	var o Options
+	if opts != nil {
+		o = *opts
+	}
+	l := &List{formatter: o.Formatter, opts: o.Options, frames: o.Frames, now: time.Now}
+	if l.formatter == nil {
+		l.formatter = formatter.Default()
+	}
+	if l.opts == nil {
+		l.opts = options.Default()
+	}
+	if len(l.frames.Frames) == 0 {
+		l.frames = spinner.GetFrames()
+	}
+	l.out = l.formatter.ConsoleOutput(messages.Info, l.opts)
+	l.plain = o.Plain || l.out == nil || !isInteractive(l.out)
+	if !l.plain {
+		l.region = terminal.NewRegion(l.out)
+	}
+	l.start = l.now()
+	return l
+}
+
+// Add adds a pending task.
+func (l *List) Add(title string) *Task {
+	return l.add(nil, title)
+}
+
+func (l *List) add(parent *Task, title string) *Task {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	t := &Task{list: l, parent: parent, title: title}
+	if parent != nil {
+		parent.children = append(parent.children, t)
+	} else {
+		l.tasks = append(l.tasks, t)
+	}
+	if !l.plain && l.stop == nil && !l.finished {
+		l.stop = make(chan struct{})
+		l.done = make(chan struct{})
+		go l.run()
+	}
+	return t
+}
+
+func (l *List) run() {
+	defer close(l.done)
+	interval := l.frames.Interval
+	if interval <= 0 {
+		interval = spinner.Dots.Interval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		l.mu.Lock()
+		l.draw()
+		l.frame++
+		l.mu.Unlock()
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// draw redraws the list in place. l.mu must be held.
+func (l *List) draw() {
+	var lines []string
+	now := l.now()
+	l.walk(func(t *Task, depth int) {
+		lines = append(lines, l.render(t, depth, now))
+	})
+	l.region.Draw(lines)
+}
+
+// walk calls fn for every task in display order. l.mu must be held.
+func (l *List) walk(fn func(t *Task, depth int)) {
+	var visit func(tasks []*Task, depth int)
+	visit = func(tasks []*Task, depth int) {
+		for _, t := range tasks {
+			fn(t, depth)
+			visit(t.children, depth+1)
+		}
+	}
+	visit(l.tasks, 0)
+}
+
+// render returns the line of t: its marker, title and duration, skip reason
+// or error. l.mu must be held.
+func (l *List) render(t *Task, depth int, now time.Time) string {
+	marker := markers[t.state]
+	if t.state == Running && !l.plain && len(l.frames.Frames) > 0 {
+		marker = l.frames.Frames[l.frame%len(l.frames.Frames)]
+	}
+	_, style := l.formatter.Decoration(l.out, styles[t.state], l.opts)
+	if style != "" {
+		marker = style + marker + colors.Reset
+	}
+
+	line := t.title
+	switch t.state {
+	case Running:
+		// Redrawn every frame, so shown to the second to avoid flicker
+		if !l.plain {
+			line += " (" + duration.Format(now.Sub(t.started), duration.Seconds) + ")"
+		}
+	case Done:
+		line += " (" + FormatDuration(t.duration()) + ")"
+	case Skipped:
+		if t.reason != "" {
+			line += " (skipped: " + t.reason + ")"
+		} else {
+			line += " (skipped)"
+		}
+	case Failed:
+		line += " (failed after " + FormatDuration(t.duration())
+		if t.err != nil {
+			line += ": " + t.err.Error()
+		}
+		line += ")"
+	}
+	return strings.Repeat("  ", depth) + marker + " " + l.redact(line)
+}
+
+func (l *List) redact(text string) string {
+	if l.formatter.Redactor != nil {
+		return l.formatter.Redactor.String(text)
+	}
+	return redact.Default().String(text)
+}
+
+// changed prints the line of t in plain mode. l.mu must be held.
+func (l *List) changed(t *Task) {
+	if !l.plain || l.out == nil || l.finished {
+		return
+	}
+	depth := 0
+	for p := t.parent; p != nil; p = p.parent {
+		depth++
+	}
+	_, _ = io.WriteString(l.out, l.render(t, depth, l.now())+"\n")
+}
+
+// Summary counts the tasks of a list by state.
+type Summary struct {
+	Done, Skipped, Failed, Unfinished int
+	Elapsed                           time.Duration
+}
+
+// String returns e.g. "3 done, 1 skipped, 1 failed in 12.3s".
+func (s Summary) String() string {
+	parts := []string{fmt.Sprintf("%d done", s.Done)}
+	if s.Skipped > 0 {
+		parts = append(parts, fmt.Sprintf("%d skipped", s.Skipped))
+	}
+	if s.Failed > 0 {
+		parts = append(parts, fmt.Sprintf("%d failed", s.Failed))
+	}
+	if s.Unfinished > 0 {
+		parts = append(parts, fmt.Sprintf("%d unfinished", s.Unfinished))
+	}
+	return strings.Join(parts, ", ") + " in " + FormatDuration(s.Elapsed)
+}
+
+// Summary returns the counts of the tasks and sub-tasks so far.
+func (l *List) Summary() Summary {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.summary()
+}
+
+func (l *List) summary() Summary {
+	s := Summary{Elapsed: l.now().Sub(l.start)}
+	l.walk(func(t *Task, _ int) {
+		switch t.state {
+		case Done:
+			s.Done++
+		case Skipped:
+			s.Skipped++
+		case Failed:
+			s.Failed++
+		default:
+			s.Unfinished++
+		}
+	})
+	return s
+}
+
+// Finish stops redrawing the list and prints and logs the summary, as an
+// error message if a task failed and a success message otherwise. Later
+// changes to the tasks are not shown.
+func (l *List) Finish() Summary {
+	l.mu.Lock()
+	if l.finished {
+		s := l.summary()
+		l.mu.Unlock()
+		return s
+	}
+	l.finished = true
+	stop, done := l.stop, l.done
+	l.mu.Unlock()
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+
+	l.mu.Lock()
+	if !l.plain {
+		l.draw()
+	}
+	s := l.summary()
+	l.mu.Unlock()
+
+	msgType := messages.Success
+	if s.Failed > 0 {
+		msgType = messages.Error
+	}
+	_, _ = l.formatter.Echo(msgType, "Tasks: "+s.String(), l.opts)
+	return s
+}
+
+// Task is a step of a List.
+type Task struct {
+	list     *List
+	parent   *Task
+	children []*Task
+
+	// Guarded by list.mu.
+	title   string
+	state   State
+	started time.Time
+	ended   time.Time
+	reason  string
+	err     error
+}
+
+// Add adds a pending sub-task, shown indented under t.
+func (t *Task) Add(title string) *Task {
+	return t.list.add(t, title)
+}
+
+// Start marks the task as running.
+func (t *Task) Start() {
+	l := t.list
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if t.state != Pending {
+		return
+	}
+	t.state, t.started = Running, l.now()
+	l.changed(t)
+}
+
+// Done marks the task as done.
+func (t *Task) Done() {
+	t.finish(Done, "", nil)
+}
+
+// Skip marks the task as skipped, e.g. because it is up to date.
+func (t *Task) Skip(reason string) {
+	t.finish(Skipped, reason, nil)
+}
+
+// Fail marks the task as failed with err.
+func (t *Task) Fail(err error) {
+	t.finish(Failed, "", err)
+}
+
+func (t *Task) finish(state State, reason string, err error) {
+	l := t.list
+	l.mu.Lock()
+	if t.state != Pending && t.state != Running {
+		l.mu.Unlock()
+		return
+	}
+	now := l.now()
+	if t.state == Pending {
+		t.started = now
+	}
+	t.state, t.ended, t.reason, t.err = state, now, reason, err
+	l.changed(t)
+	title, duration := t.title, t.duration()
+	l.mu.Unlock()
+
+	fields := []field.Field{field.F("duration", duration)}
+	switch state {
+	case Done:
+		l.formatter.Log(messages.Success, title, fields...)
+	case Skipped:
+		l.formatter.Log(messages.Warning, title+" skipped", append(fields, field.F("reason", reason))...)
+	case Failed:
+		l.formatter.Log(messages.Error, title+" failed", append(fields, field.F("error", err))...)
+	}
+}
+
+// Run starts the task, calls fn and marks the task as failed if fn returns
+// an error and as done otherwise, unless fn finished it, e.g. with Skip.
+// The error of fn is returned.
+func (t *Task) Run(fn func(t *Task) error) error {
+	t.Start()
+	err := fn(t)
+	if err != nil {
+		t.Fail(err)
+	} else {
+		t.Done()
+	}
+	return err
+}
+
+// SetTitle changes the title of the task.
+func (t *Task) SetTitle(title string) {
+	t.list.mu.Lock()
+	defer t.list.mu.Unlock()
+	t.title = title
+}
+
+// State returns the state of the task.
+func (t *Task) State() State {
+	t.list.mu.Lock()
+	defer t.list.mu.Unlock()
+	return t.state
+}
+
+// Duration returns how long the task ran, so far if it is running.
+func (t *Task) Duration() time.Duration {
+	t.list.mu.Lock()
+	defer t.list.mu.Unlock()
+	if t.state == Running {
+		return t.list.now().Sub(t.started)
+	}
+	return t.duration()
+}
+
+// duration returns the time from start to end. list.mu must be held.
+func (t *Task) duration() time.Duration {
+	if t.ended.IsZero() {
+		return 0
+	}
+	return t.ended.Sub(t.started)
+}
+
+// durationSteps round task durations to milliseconds below a second, to
+// tenths of a second below a minute and to seconds above.
+var durationSteps = []duration.Step{
+	{From: time.Minute, Precision: time.Second},
+	{From: time.Second, Precision: 100 * time.Millisecond},
+	{Precision: time.Millisecond},
+}
+
+// FormatDuration rounds d for display: to milliseconds below a second, to
+// tenths of a second below a minute and to seconds above.
+func FormatDuration(d time.Duration) string {
+	return duration.Format(d, durationSteps)
+}
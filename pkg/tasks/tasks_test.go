@@ -0,0 +1,208 @@
+package tasks
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jsas4coding/utify/pkg/colors"
+	"github.com/jsas4coding/utify/pkg/formatter"
+	"github.com/jsas4coding/utify/pkg/layout"
+	"github.com/jsas4coding/utify/pkg/logger"
+	"github.com/jsas4coding/utify/pkg/messages"
+	"github.com/jsas4coding/utify/pkg/spinner"
+)
+
+// syncBuffer is a bytes.Buffer safe for the drawing goroutine.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// clock is a fake time source advanced by tests.
+type clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newList(t *testing.T, out io.Writer, logBuf *bytes.Buffer, terminal bool) (*List, *clock) {
+	t.Helper()
+	orig := isInteractive
+	isInteractive = func(io.Writer) bool { return terminal }
+	t.Cleanup(func() { isInteractive = orig })
+
+	c := &clock{now: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)}
+	l := New(&Options{
+		Formatter: &formatter.Formatter{
+			Output:      out,
+			ErrorOutput: out,
+			Profile:     colors.ProfileNone,
+			Layouts:     layout.Layouts{messages.Default: layout.MustParse("{message}")},
+			Logger:      logger.NewWriter(logBuf),
+		},
+		Frames: spinner.Frames{Frames: []string{"*"}, Interval: time.Hour},
+	})
+	l.now = c.Now
+	l.start = c.Now()
+	return l, c
+}
+
+func TestPlain(t *testing.T) {
+	var out, logBuf bytes.Buffer
+	l, c := newList(t, &out, &logBuf, false)
+
+	deps := l.Add("Install dependencies")
+	libfoo := deps.Add("libfoo")
+	libbar := deps.Add("libbar")
+	config := l.Add("Write config")
+	migrate := l.Add("Migrate database")
+	l.Add("Restart")
+
+	deps.Start()
+	_ = libfoo.Run(func(*Task) error {
+		c.Advance(1500 * time.Millisecond)
+		return nil
+	})
+	libbar.Skip("up to date")
+	c.Advance(250 * time.Millisecond)
+	deps.Done()
+	config.Start()
+	config.Done()
+	err := migrate.Run(func(*Task) error {
+		c.Advance(2 * time.Second)
+		return errors.New("connection refused")
+	})
+	if err == nil || migrate.State() != Failed {
+		t.Fatalf("Expected Run to fail the task, got %v and %v", err, migrate.State())
+	}
+	s := l.Finish()
+
+	expected := `▶ Install dependencies
+  ▶ libfoo
+  ✔ libfoo (1.5s)
+  ↷ libbar (skipped: up to date)
+✔ Install dependencies (1.8s)
+▶ Write config
+✔ Write config (0s)
+▶ Migrate database
+✖ Migrate database (failed after 2s: connection refused)
+Tasks: 3 done, 1 skipped, 1 failed, 1 unfinished in 3.8s
+`
+	if out.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+	if s != (Summary{Done: 3, Skipped: 1, Failed: 1, Unfinished: 1, Elapsed: 3750 * time.Millisecond}) {
+		t.Errorf("Unexpected summary %+v", s)
+	}
+	for _, text := range []string{`"message":"libfoo"`, `"duration":"1.5s"`, `"reason":"up to date"`,
+		`"message":"Migrate database failed"`, `"error":"connection refused"`, `"level":"ERROR","message":"Tasks: `} {
+		if !strings.Contains(logBuf.String(), text) {
+			t.Errorf("Expected %s in the log, got %q", text, logBuf.String())
+		}
+	}
+}
+
+func TestTerminal(t *testing.T) {
+	var out syncBuffer
+	var logBuf bytes.Buffer
+	l, c := newList(t, &out, &logBuf, true)
+
+	build := l.Add("Build")
+	l.Add("Test")
+	build.Start()
+	c.Advance(time.Second)
+	build.Done()
+	s := l.Finish()
+
+	// The first draw may happen before or after "Test" is added, but always
+	// before the final one, which moves the cursor up over it
+	final := "A\r\033[J✔ Build (1s)\n○ Test\nTasks: 1 done, 1 unfinished in 1s\n"
+	if !strings.HasSuffix(out.String(), final) {
+		t.Errorf("Expected the list redrawn in place, got %q", out.String())
+	}
+	if s.Done != 1 || s.Unfinished != 1 {
+		t.Errorf("Unexpected summary %+v", s)
+	}
+
+	build.Fail(errors.New("late"))
+	if l.Finish() != s || build.State() != Done {
+		t.Error("Expected changes after Finish to be ignored")
+	}
+}
+
+func TestParallel(t *testing.T) {
+	var out, logBuf bytes.Buffer
+	l, _ := newList(t, &out, &logBuf, false)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		task := l.Add("step")
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = task.Run(func(*Task) error { return nil })
+		}()
+	}
+	wg.Wait()
+	if s := l.Finish(); s.Done != 10 {
+		t.Errorf("Expected 10 done tasks, got %+v", s)
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := map[time.Duration]string{
+		1234567 * time.Nanosecond: "1ms",
+		1540 * time.Millisecond:   "1.5s",
+		185 * time.Second:         "3m5s",
+	}
+	for d, expected := range tests {
+		if s := FormatDuration(d); s != expected {
+			t.Errorf("FormatDuration(%v): expected %q, got %q", d, expected, s)
+		}
+	}
+}
+
+func TestRunningDurationInSeconds(t *testing.T) {
+	var out syncBuffer
+	var logBuf bytes.Buffer
+	l, c := newList(t, &out, &logBuf, true)
+	task := l.Add("Build")
+	task.Start()
+	c.Advance(1234 * time.Millisecond)
+
+	l.mu.Lock()
+	line := l.render(task, 0, c.Now())
+	l.mu.Unlock()
+	l.Finish()
+	if !strings.HasSuffix(line, "Build (1s)") {
+		t.Errorf("Expected the running duration in whole seconds, got %q", line)
+	}
+}
@@ -0,0 +1,89 @@
+package terminal
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// IsInteractive reports whether output to w can be redrawn in place: w is a
+// terminal and TERM is not dumb.
+func IsInteractive(w io.Writer) bool {
+	return IsTerminal(w) && os.Getenv("TERM") != "dumb"
+}
+
+// Columns returns the width of the terminal w is attached to, falling back
+// to the COLUMNS environment variable, or 0 if it is unknown.
+func Columns(w io.Writer) int {
+	if f, ok := w.(fdWriter); ok {
+		if n := columnsFd(f.Fd()); n > 0 {
+			return n
+		}
+	}
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	return 0
+}
+
+// Region is a block of lines redrawn in place on a terminal, such as
+// progress bars or a task list. Every draw replaces the rows of the
+// previous one, including those of lines that wrapped.
+type Region struct {
+	out  io.Writer
+	rows int
+}
+
+// NewRegion returns a region drawn on out from the cursor position.
+func NewRegion(out io.Writer) *Region {
+	return &Region{out: out}
+}
+
+// Draw replaces the lines last drawn with lines, leaving the cursor below
+// them.
+func (r *Region) Draw(lines []string) {
+	var buf strings.Builder
+	r.clear(&buf)
+	columns := Columns(r.out)
+	for _, line := range lines {
+		buf.WriteString(line)
+		buf.WriteByte('\n')
+		r.rows += Rows(line, columns)
+	}
+	_, _ = io.WriteString(r.out, buf.String())
+}
+
+// Clear removes the lines last drawn, leaving the cursor where they started.
+func (r *Region) Clear() {
+	var buf strings.Builder
+	r.clear(&buf)
+	_, _ = io.WriteString(r.out, buf.String())
+}
+
+// Reset forgets the lines last drawn, so they stay on screen and the next
+// draw starts below them.
+func (r *Region) Reset() {
+	r.rows = 0
+}
+
+// clear writes the escapes moving the cursor up to the first row drawn and
+// clearing the screen below it.
+func (r *Region) clear(buf *strings.Builder) {
+	if r.rows > 0 {
+		fmt.Fprintf(buf, "\033[%dA", r.rows)
+	}
+	buf.WriteString("\r\033[J")
+	r.rows = 0
+}
+
+// Rows returns the number of terminal rows line takes on a terminal of the
+// given width, one if the width is unknown.
+func Rows(line string, columns int) int {
+	w := Width(line)
+	if columns <= 0 || w <= columns {
+		return 1
+	}
+	return (w + columns - 1) / columns
+}
@@ -0,0 +1,60 @@
+package terminal
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRows(t *testing.T) {
+	tests := []struct {
+		line     string
+		columns  int
+		expected int
+	}{
+		{"", 10, 1},
+		{"0123456789", 10, 1},
+		{"0123456789a", 10, 2},
+		{"\033[31m0123456789\033[0m", 10, 1},
+		{"日本語日本語", 5, 3},
+		{"0123456789a", 0, 1},
+	}
+	for _, tt := range tests {
+		if got := Rows(tt.line, tt.columns); got != tt.expected {
+			t.Errorf("Rows(%q, %d): expected %d, got %d", tt.line, tt.columns, tt.expected, got)
+		}
+	}
+}
+
+func TestRegion(t *testing.T) {
+	t.Setenv("COLUMNS", "10")
+	var out bytes.Buffer
+	r := NewRegion(&out)
+
+	r.Draw([]string{"a", "0123456789abc"})
+	r.Draw([]string{"b"})
+	r.Clear()
+
+	expected := "\r\033[Ja\n0123456789abc\n" + "\033[3A\r\033[Jb\n" + "\033[1A\r\033[J"
+	if out.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, out.String())
+	}
+
+	out.Reset()
+	r.Draw([]string{"kept"})
+	r.Reset()
+	r.Draw([]string{"below"})
+	if out.String() != "\r\033[Jkept\n\r\033[Jbelow\n" {
+		t.Errorf("Expected Reset to keep the lines drawn, got %q", out.String())
+	}
+}
+
+func TestColumns(t *testing.T) {
+	t.Setenv("COLUMNS", "")
+	if n := Columns(&bytes.Buffer{}); n != 0 {
+		t.Errorf("Expected an unknown width, got %d", n)
+	}
+	t.Setenv("COLUMNS", "120")
+	if n := Columns(&bytes.Buffer{}); n != 120 {
+		t.Errorf("Expected the width from COLUMNS, got %d", n)
+	}
+}
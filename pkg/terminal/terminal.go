@@ -0,0 +1,119 @@
+package terminal
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jsas4coding/utify/pkg/colors"
+)
+
+// fdWriter is implemented by *os.File and other writers backed by a file descriptor.
+type fdWriter interface {
+	Fd() uintptr
+}
+
+// IsTerminal reports whether w is attached to a terminal. Other character
+// devices, such as /dev/null, are not terminals.
+func IsTerminal(w io.Writer) bool {
+	f, ok := w.(fdWriter)
+	return ok && isTerminalFd(f.Fd())
+}
+
+// ColorProfile detects the color profile for output written to w. It honors
+// FORCE_COLOR, NO_COLOR, CLICOLOR_FORCE, CLICOLOR and TERM=dumb, checks
+// whether w is a terminal and recognizes common CI environments.
+func ColorProfile(w io.Writer) colors.Profile {
+	return detectProfile(IsTerminal(w))
+}
+
+// detectProfile applies the environment conventions in order of precedence.
+func detectProfile(isTTY bool) colors.Profile {
+	if forced, ok := forceColorProfile(); ok {
+		return forced
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return colors.ProfileNone
+	}
+	if force := os.Getenv("CLICOLOR_FORCE"); force != "" && force != "0" {
+		return max(EnvProfile(), colors.ProfileANSI)
+	}
+	if !isTTY {
+		return ciProfile()
+	}
+	if os.Getenv("CLICOLOR") == "0" {
+		return colors.ProfileNone
+	}
+	return EnvProfile()
+}
+
+// forceColorProfile interprets FORCE_COLOR: 0 or false disables colors,
+// 1 to 3 select a minimum level, and any other non-empty value forces basic
+// colors.
+func forceColorProfile() (colors.Profile, bool) {
+	value := os.Getenv("FORCE_COLOR")
+	if value == "" {
+		return colors.ProfileAuto, false
+	}
+	switch strings.ToLower(value) {
+	case "0", "false":
+		return colors.ProfileNone, true
+	case "2":
+		return max(EnvProfile(), colors.ProfileANSI256), true
+	case "3":
+		return colors.ProfileTrueColor, true
+	default:
+		return max(EnvProfile(), colors.ProfileANSI), true
+	}
+}
+
+// ciProfile returns the profile for non-terminal output in CI systems whose
+// log viewers render ANSI escapes, or ProfileNone elsewhere.
+func ciProfile() colors.Profile {
+	if os.Getenv("GITHUB_ACTIONS") != "" || os.Getenv("GITEA_ACTIONS") != "" {
+		return colors.ProfileTrueColor
+	}
+	ciVars := []string{
+		"GITLAB_CI", "BUILDKITE", "CIRCLECI", "TRAVIS",
+		"APPVEYOR", "DRONE", "WOODPECKER_CI", "TF_BUILD",
+	}
+	for _, name := range ciVars {
+		if os.Getenv(name) != "" {
+			return colors.ProfileANSI
+		}
+	}
+	return colors.ProfileNone
+}
+
+// EnvProfile returns the color depth advertised by the environment for a
+// terminal, ignoring whether output actually goes to one.
+func EnvProfile() colors.Profile {
+	term := strings.ToLower(os.Getenv("TERM"))
+	if term == "dumb" {
+		return colors.ProfileNone
+	}
+
+	colorTerm := strings.ToLower(os.Getenv("COLORTERM"))
+	if colorTerm == "truecolor" || colorTerm == "24bit" {
+		return colors.ProfileTrueColor
+	}
+	if os.Getenv("WT_SESSION") != "" {
+		return colors.ProfileTrueColor
+	}
+
+	switch os.Getenv("TERM_PROGRAM") {
+	case "iTerm.app", "WezTerm", "ghostty", "vscode":
+		return colors.ProfileTrueColor
+	case "Apple_Terminal":
+		return colors.ProfileANSI256
+	}
+
+	if strings.Contains(term, "truecolor") || strings.Contains(term, "24bit") ||
+		strings.Contains(term, "direct") {
+		return colors.ProfileTrueColor
+	}
+	if strings.Contains(term, "256color") {
+		return colors.ProfileANSI256
+	}
+	return colors.ProfileANSI
+}
@@ -0,0 +1,13 @@
+//go:build !(linux || darwin || dragonfly || freebsd || netbsd || openbsd || windows)
+
+package terminal
+
+// isTerminalFd reports false: terminals are not detected on this platform.
+func isTerminalFd(fd uintptr) bool {
+	return false
+}
+
+// columnsFd reports 0: the terminal width is not detected on this platform.
+func columnsFd(fd uintptr) int {
+	return 0
+}
@@ -0,0 +1,99 @@
+package terminal
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/jsas4coding/utify/pkg/colors"
+)
+
+// clearColorEnv unsets every variable consulted by detection for the test.
+func clearColorEnv(t *testing.T) {
+	t.Helper()
+	for _, name := range []string{
+		"FORCE_COLOR", "NO_COLOR", "CLICOLOR", "CLICOLOR_FORCE", "TERM", "COLORTERM",
+		"TERM_PROGRAM", "WT_SESSION", "GITHUB_ACTIONS", "GITEA_ACTIONS", "GITLAB_CI",
+		"BUILDKITE", "CIRCLECI", "TRAVIS", "APPVEYOR", "DRONE", "WOODPECKER_CI", "TF_BUILD",
+	} {
+		t.Setenv(name, "")
+	}
+}
+
+func TestIsTerminal(t *testing.T) {
+	if IsTerminal(&bytes.Buffer{}) {
+		t.Error("Expected a buffer not to be a terminal")
+	}
+
+	f, err := os.CreateTemp(t.TempDir(), "out")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	if IsTerminal(f) {
+		t.Error("Expected a regular file not to be a terminal")
+	}
+
+	null, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("Failed to open %s: %v", os.DevNull, err)
+	}
+	defer func() { _ = null.Close() }()
+	if IsTerminal(null) {
+		t.Errorf("Expected %s not to be a terminal", os.DevNull)
+	}
+}
+
+func TestColorProfileForNonTerminal(t *testing.T) {
+	clearColorEnv(t)
+	t.Setenv("TERM", "xterm-256color")
+
+	if got := ColorProfile(&bytes.Buffer{}); got != colors.ProfileNone {
+		t.Errorf("Expected ProfileNone for a buffer, got %s", got)
+	}
+}
+
+func TestDetectProfile(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		isTTY    bool
+		expected colors.Profile
+	}{
+		{"TTY basic", map[string]string{"TERM": "xterm"}, true, colors.ProfileANSI},
+		{"TTY 256", map[string]string{"TERM": "xterm-256color"}, true, colors.ProfileANSI256},
+		{"TTY truecolor", map[string]string{"TERM": "xterm-256color", "COLORTERM": "truecolor"}, true,
+			colors.ProfileTrueColor},
+		{"TTY dumb", map[string]string{"TERM": "dumb"}, true, colors.ProfileNone},
+		{"TTY NO_COLOR", map[string]string{"TERM": "xterm", "NO_COLOR": "1"}, true, colors.ProfileNone},
+		{"TTY CLICOLOR=0", map[string]string{"TERM": "xterm", "CLICOLOR": "0"}, true, colors.ProfileNone},
+		{"pipe", map[string]string{"TERM": "xterm-256color"}, false, colors.ProfileNone},
+		{"pipe CLICOLOR_FORCE", map[string]string{"CLICOLOR_FORCE": "1"}, false, colors.ProfileANSI},
+		{"pipe CLICOLOR_FORCE=0", map[string]string{"CLICOLOR_FORCE": "0"}, false, colors.ProfileNone},
+		{"pipe FORCE_COLOR", map[string]string{"FORCE_COLOR": "1"}, false, colors.ProfileANSI},
+		{"pipe FORCE_COLOR=2", map[string]string{"FORCE_COLOR": "2"}, false, colors.ProfileANSI256},
+		{"pipe FORCE_COLOR=3", map[string]string{"FORCE_COLOR": "3"}, false, colors.ProfileTrueColor},
+		{"FORCE_COLOR beats NO_COLOR", map[string]string{"FORCE_COLOR": "1", "NO_COLOR": "1"}, false,
+			colors.ProfileANSI},
+		{"FORCE_COLOR=0 on TTY", map[string]string{"TERM": "xterm", "FORCE_COLOR": "0"}, true,
+			colors.ProfileNone},
+		{"NO_COLOR beats CLICOLOR_FORCE", map[string]string{"NO_COLOR": "1", "CLICOLOR_FORCE": "1"}, false,
+			colors.ProfileNone},
+		{"GitHub Actions", map[string]string{"GITHUB_ACTIONS": "true"}, false, colors.ProfileTrueColor},
+		{"GitLab CI", map[string]string{"GITLAB_CI": "true"}, false, colors.ProfileANSI},
+		{"Windows Terminal", map[string]string{"WT_SESSION": "abc"}, true, colors.ProfileTrueColor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearColorEnv(t)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			if got := detectProfile(tt.isTTY); got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
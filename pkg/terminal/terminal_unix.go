@@ -0,0 +1,27 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd
+
+package terminal
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// isTerminalFd reports whether fd is a terminal: reading its terminal
+// attributes only succeeds for terminals, unlike /dev/null and other
+// character devices.
+func isTerminalFd(fd uintptr) bool {
+	var termios syscall.Termios
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, ioctlReadTermios, uintptr(unsafe.Pointer(&termios)))
+	return errno == 0
+}
+
+// columnsFd returns the width of the terminal fd, or 0 if it is not one.
+func columnsFd(fd uintptr) int {
+	var ws struct{ Row, Col, X, Y uint16 }
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, syscall.TIOCGWINSZ, uintptr(unsafe.Pointer(&ws)))
+	if errno != 0 {
+		return 0
+	}
+	return int(ws.Col)
+}
@@ -0,0 +1,31 @@
+package terminal
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+var procGetConsoleScreenBufferInfo = syscall.NewLazyDLL("kernel32.dll").NewProc("GetConsoleScreenBufferInfo")
+
+// isTerminalFd reports whether fd is a console.
+func isTerminalFd(fd uintptr) bool {
+	var mode uint32
+	return syscall.GetConsoleMode(syscall.Handle(fd), &mode) == nil
+}
+
+// columnsFd returns the width of the console window fd, or 0 if it is not
+// a console.
+func columnsFd(fd uintptr) int {
+	// CONSOLE_SCREEN_BUFFER_INFO
+	var info struct {
+		Size, CursorPosition     struct{ X, Y int16 }
+		Attributes               uint16
+		Left, Top, Right, Bottom int16
+		MaximumWindowSize        struct{ X, Y int16 }
+	}
+	ok, _, _ := procGetConsoleScreenBufferInfo.Call(fd, uintptr(unsafe.Pointer(&info)))
+	if ok == 0 {
+		return 0
+	}
+	return int(info.Right-info.Left) + 1
+}
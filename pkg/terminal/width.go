@@ -0,0 +1,200 @@
+package terminal
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// StripANSI removes ANSI escape sequences, such as colors, from s.
+func StripANSI(s string) string {
+	if !strings.Contains(s, "\033") {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		if n := escapeLen(s[i:]); n > 0 {
+			i += n
+			continue
+		}
+		b.WriteByte(s[i])
+		i++
+	}
+	return b.String()
+}
+
+// escapeLen returns the length of the escape sequence at the start of s, or
+// zero. CSI sequences end with a final byte and OSC sequences, such as
+// hyperlinks, with BEL or ESC \.
+func escapeLen(s string) int {
+	if len(s) < 2 || s[0] != '\033' {
+		return 0
+	}
+	switch s[1] {
+	case '[':
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return i + 1
+			}
+		}
+		return len(s)
+	case ']':
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' {
+				return i + 1
+			}
+			if s[i] == '\033' && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return len(s)
+	}
+	return 2
+}
+
+// Width returns the number of terminal columns s occupies. Escape sequences
+// take none, and neither do combining marks, variation selectors and
+// characters joined into an emoji by a zero-width joiner; emoji and East
+// Asian wide characters take two.
+func Width(s string) int {
+	width := 0
+	var prev rune
+	for i := 0; i < len(s); {
+		if n := escapeLen(s[i:]); n > 0 {
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		width += runeWidth(r, prev)
+		prev = follow(r, prev)
+	}
+	return width
+}
+
+// Truncate shortens s to at most width columns, ending it with an
+// ellipsis. Escape sequences are kept, and a reset is added if s had any.
+func Truncate(s string, width int) string {
+	if Width(s) <= width {
+		return s
+	}
+	if width <= 0 {
+		return ""
+	}
+	var b strings.Builder
+	used, escaped := 0, false
+	var prev rune
+	for i := 0; i < len(s); {
+		if n := escapeLen(s[i:]); n > 0 {
+			b.WriteString(s[i : i+n])
+			escaped = true
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		w := runeWidth(r, prev)
+		if used+w > width-1 {
+			break
+		}
+		b.WriteString(s[i : i+size])
+		used += w
+		prev = follow(r, prev)
+		i += size
+	}
+	b.WriteString("…")
+	if escaped {
+		b.WriteString("\033[0m")
+	}
+	return b.String()
+}
+
+// follow returns the rune preceding the one after r. A pair of regional
+// indicators forms a flag, so the next one starts another.
+func follow(r, prev rune) rune {
+	if isRegional(r) && isRegional(prev) {
+		return 0
+	}
+	return r
+}
+
+func isRegional(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
+
+// runeWidth returns the width of r following prev.
+func runeWidth(r, prev rune) int {
+	switch {
+	case prev == '\u200d':
+		// Joined to the previous emoji
+		return 0
+	case isRegional(r) && isRegional(prev):
+		// The second half of a flag
+		return 0
+	case r == '\ufe0f':
+		// Emoji presentation widens a narrow symbol such as ⚠
+		if prev != 0 && !isWide(prev) && !isZeroWidth(prev) {
+			return 1
+		}
+		return 0
+	case r >= 0x1f3fb && r <= 0x1f3ff:
+		// Skin tone modifiers
+		return 0
+	case isZeroWidth(r):
+		return 0
+	case isWide(r):
+		return 2
+	}
+	return 1
+}
+
+func isZeroWidth(r rune) bool {
+	return r < 0x20 || (r >= 0x7f && r < 0xa0) ||
+		unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) ||
+		(r >= 0xfe00 && r <= 0xfe0f) || (r >= 0xe0100 && r <= 0xe01ef)
+}
+
+// wideRanges are the East Asian wide and fullwidth characters and the
+// characters with emoji presentation by default.
+var wideRanges = [][2]rune{
+	{0x1100, 0x115f}, {0x231a, 0x231b}, {0x2329, 0x232a}, {0x23e9, 0x23ec},
+	{0x23f0, 0x23f0}, {0x23f3, 0x23f3}, {0x25fd, 0x25fe}, {0x2614, 0x2615},
+	{0x2648, 0x2653}, {0x267f, 0x267f}, {0x2693, 0x2693}, {0x26a1, 0x26a1},
+	{0x26aa, 0x26ab}, {0x26bd, 0x26be}, {0x26c4, 0x26c5}, {0x26ce, 0x26ce},
+	{0x26d4, 0x26d4}, {0x26ea, 0x26ea}, {0x26f2, 0x26f3}, {0x26f5, 0x26f5},
+	{0x26fa, 0x26fa}, {0x26fd, 0x26fd}, {0x2705, 0x2705}, {0x270a, 0x270b},
+	{0x2728, 0x2728}, {0x274c, 0x274c}, {0x274e, 0x274e}, {0x2753, 0x2755},
+	{0x2757, 0x2757}, {0x2795, 0x2797}, {0x27b0, 0x27b0}, {0x27bf, 0x27bf},
+	{0x2b1b, 0x2b1c}, {0x2b50, 0x2b50}, {0x2b55, 0x2b55}, {0x2e80, 0x303e},
+	{0x3041, 0x33ff}, {0x3400, 0x4dbf}, {0x4e00, 0x9fff}, {0xa000, 0xa4cf},
+	{0xa960, 0xa97f}, {0xac00, 0xd7a3}, {0xf900, 0xfaff}, {0xfe10, 0xfe19},
+	{0xfe30, 0xfe6f}, {0xff00, 0xff60}, {0xffe0, 0xffe6}, {0x1f004, 0x1f004},
+	{0x1f0cf, 0x1f0cf}, {0x1f18e, 0x1f18e}, {0x1f191, 0x1f19a}, {0x1f1e6, 0x1f1ff},
+	{0x1f200, 0x1f251}, {0x1f300, 0x1f320}, {0x1f32d, 0x1f335}, {0x1f337, 0x1f37c},
+	{0x1f37e, 0x1f393}, {0x1f3a0, 0x1f3ca}, {0x1f3cf, 0x1f3d3}, {0x1f3e0, 0x1f3f0},
+	{0x1f3f4, 0x1f3f4}, {0x1f3f8, 0x1f43e}, {0x1f440, 0x1f440}, {0x1f442, 0x1f4fc},
+	{0x1f4ff, 0x1f53d}, {0x1f54b, 0x1f54e}, {0x1f550, 0x1f567}, {0x1f57a, 0x1f57a},
+	{0x1f595, 0x1f596}, {0x1f5a4, 0x1f5a4}, {0x1f5fb, 0x1f64f}, {0x1f680, 0x1f6c5},
+	{0x1f6cc, 0x1f6cc}, {0x1f6d0, 0x1f6d2}, {0x1f6d5, 0x1f6d7}, {0x1f6dc, 0x1f6df},
+	{0x1f6eb, 0x1f6ec}, {0x1f6f4, 0x1f6fc}, {0x1f7e0, 0x1f7eb}, {0x1f7f0, 0x1f7f0},
+	{0x1f90c, 0x1f93a}, {0x1f93c, 0x1f945}, {0x1f947, 0x1f9ff}, {0x1fa70, 0x1faff},
+	{0x20000, 0x2fffd}, {0x30000, 0x3fffd},
+}
+
+func isWide(r rune) bool {
+	if r < wideRanges[0][0] {
+		return false
+	}
+	lo, hi := 0, len(wideRanges)
+	for lo < hi {
+		mid := (lo + hi) / 2
+		switch {
+		case r < wideRanges[mid][0]:
+			hi = mid
+		case r > wideRanges[mid][1]:
+			lo = mid + 1
+		default:
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package terminal
+
+import (
+	"testing"
+)
+
+func TestWidth(t *testing.T) {
+	tests := map[string]int{
+		"":                                 0,
+		"hello":                            5,
+		"\033[1;31mfailed\033[0m":          6,
+		"\033]8;;https://x\afoo\033]8;;\a": 3,
+		"日本語":                              6,
+		"🚀 up":                             5,
+		"⚠\ufe0f":                          2,
+		"✔":                                1,
+		"e\u0301":                          1,
+		"👩\u200d💻":                         2,
+		"👍🏽":                               2,
+		"🇧🇷":                               2,
+	}
+	for s, expected := range tests {
+		if w := Width(s); w != expected {
+			t.Errorf("Width(%q): expected %d, got %d", s, expected, w)
+		}
+	}
+}
+
+func TestStripANSI(t *testing.T) {
+	if s := StripANSI("\033[32m✔ ok\033[0m \033]8;;u\033\\link\033]8;;\033\\"); s != "✔ ok link" {
+		t.Errorf("Expected escape sequences removed, got %q", s)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s        string
+		width    int
+		expected string
+	}{
+		{"short", 10, "short"},
+		{"worker-long", 7, "worker…"},
+		{"日本語", 4, "日…"},
+		{"\033[31mfailed\033[0m", 4, "\033[31mfai…\033[0m"},
+		{"abc", 0, ""},
+	}
+	for _, tt := range tests {
+		if s := Truncate(tt.s, tt.width); s != tt.expected {
+			t.Errorf("Truncate(%q, %d): expected %q, got %q", tt.s, tt.width, tt.expected, s)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package theme
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/jsas4coding/utify/pkg/colors"
+	"github.com/jsas4coding/utify/pkg/messages"
+)
+
+// Names of the built-in themes.
+const (
+	Default      = "default"
+	Monochrome   = "monochrome"
+	Solarized    = "solarized"
+	Dracula      = "dracula"
+	HighContrast = "high-contrast"
+)
+
+var builtins = map[string]func() *Theme{
+	Default:      defaultTheme,
+	Monochrome:   monochromeTheme,
+	Solarized:    solarizedTheme,
+	Dracula:      draculaTheme,
+	HighContrast: highContrastTheme,
+}
+
+// Builtin returns a copy of the named built-in theme.
+func Builtin(name string) (*Theme, error) {
+	build, exists := builtins[name]
+	if !exists {
+		return nil, fmt.Errorf("%w: '%s'", ErrUnknownTheme, name)
+	}
+	return build(), nil
+}
+
+// Names returns the names of the built-in themes in alphabetical order.
+func Names() []string {
+	names := make([]string, 0, len(builtins))
+	for name := range builtins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// fromPalette builds styles that only set the color of each type.
+func fromPalette(palette map[messages.Type]string) map[messages.Type]Style {
+	styles := make(map[messages.Type]Style, len(palette))
+	for msgType, spec := range palette {
+		styles[msgType] = Style{Color: colors.MustParse(spec)}
+	}
+	return styles
+}
+
+// defaultTheme keeps the built-in colors and icons of every message type.
+func defaultTheme() *Theme {
+	return &Theme{Name: Default, Styles: map[messages.Type]Style{}}
+}
+
+func monochromeTheme() *Theme {
+	return &Theme{
+		Name:       Monochrome,
+		Monochrome: true,
+		Styles: map[messages.Type]Style{
+			messages.Success:  {Bold: true, Label: "[OK]"},
+			messages.Error:    {Bold: true, Label: "[ERROR]"},
+			messages.Critical: {Bold: true, Underline: true, Label: "[CRITICAL]"},
+			messages.Warning:  {Bold: true, Label: "[WARN]"},
+			messages.Info:     {Label: "[INFO]"},
+			messages.Debug:    {Dim: true, Label: "[DEBUG]"},
+		},
+	}
+}
+
+func solarizedTheme() *Theme {
+	const (
+		yellow  = "#b58900"
+		orange  = "#cb4b16"
+		red     = "#dc322f"
+		magenta = "#d33682"
+		violet  = "#6c71c4"
+		blue    = "#268bd2"
+		cyan    = "#2aa198"
+		green   = "#859900"
+		base0   = "#839496"
+		base01  = "#586e75"
+	)
+	styles := fromPalette(map[messages.Type]string{
+		messages.Success: green, messages.Error: red, messages.Warning: yellow,
+		messages.Info: cyan, messages.Debug: base01, messages.Search: blue,
+		messages.Sync: violet, messages.Download: base0, messages.Refresh: blue,
+		messages.Upload: green, messages.Delete: red, messages.Git: orange,
+		messages.New: green, messages.Edit: blue, messages.Update: yellow,
+		messages.Generation: cyan, messages.Find: blue, messages.Link: violet,
+		messages.Unlink: orange, messages.Upgrade: blue, messages.Install: green,
+		messages.Font: base0, messages.Theme: magenta, messages.Icon: base0,
+		messages.Default: base0,
+	})
+	styles[messages.Critical] = Style{Color: colors.MustParse(magenta), Bold: true}
+	return &Theme{Name: Solarized, Styles: styles}
+}
+
+func draculaTheme() *Theme {
+	const (
+		foreground = "#f8f8f2"
+		comment    = "#6272a4"
+		cyan       = "#8be9fd"
+		green      = "#50fa7b"
+		orange     = "#ffb86c"
+		pink       = "#ff79c6"
+		purple     = "#bd93f9"
+		red        = "#ff5555"
+		yellow     = "#f1fa8c"
+	)
+	styles := fromPalette(map[messages.Type]string{
+		messages.Success: green, messages.Error: red, messages.Warning: orange,
+		messages.Info: cyan, messages.Debug: comment, messages.Search: purple,
+		messages.Sync: pink, messages.Download: foreground, messages.Refresh: cyan,
+		messages.Upload: green, messages.Delete: red, messages.Git: orange,
+		messages.New: green, messages.Edit: purple, messages.Update: yellow,
+		messages.Generation: cyan, messages.Find: purple, messages.Link: pink,
+		messages.Unlink: red, messages.Upgrade: cyan, messages.Install: green,
+		messages.Font: foreground, messages.Theme: pink, messages.Icon: foreground,
+		messages.Default: foreground,
+	})
+	styles[messages.Critical] = Style{Color: colors.MustParse(red), Bold: true, Underline: true}
+	return &Theme{Name: Dracula, Styles: styles}
+}
+
+func highContrastTheme() *Theme {
+	styles := fromPalette(map[messages.Type]string{
+		messages.Success: "brightgreen", messages.Warning: "brightyellow",
+		messages.Info: "brightcyan", messages.Debug: "brightwhite",
+		messages.Search: "brightblue", messages.Sync: "brightmagenta",
+		messages.Download: "brightwhite", messages.Refresh: "brightcyan",
+		messages.Upload: "brightgreen", messages.Delete: "brightred",
+		messages.Git: "brightmagenta", messages.New: "brightgreen",
+		messages.Edit: "brightblue", messages.Update: "brightyellow",
+		messages.Generation: "brightcyan", messages.Find: "brightblue",
+		messages.Link: "brightmagenta", messages.Unlink: "brightred",
+		messages.Upgrade: "brightcyan", messages.Install: "brightgreen",
+		messages.Font: "brightwhite", messages.Theme: "brightmagenta",
+		messages.Icon: "brightwhite", messages.Default: "brightwhite",
+	})
+	for msgType, style := range styles {
+		style.Bold = true
+		styles[msgType] = style
+	}
+	styles[messages.Error] = Style{
+		Color: colors.MustParse("brightwhite"), Background: colors.MustParse("red"), Bold: true,
+	}
+	styles[messages.Critical] = Style{
+		Color: colors.MustParse("brightwhite"), Background: colors.MustParse("magenta"),
+		Bold: true, Underline: true,
+	}
+	return &Theme{Name: HighContrast, Styles: styles}
+}
@@ -0,0 +1,120 @@
+package theme
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/jsas4coding/utify/pkg/colors"
+	"github.com/jsas4coding/utify/pkg/messages"
+)
+
+// ErrUnknownTheme is returned when a built-in theme name does not exist.
+var ErrUnknownTheme = errors.New("unknown theme")
+
+// Style describes how one message type is rendered. Zero fields keep the
+// default for that aspect, e.g. a Style with only Icon set keeps the color.
+type Style struct {
+	Color         colors.Color `json:"color,omitzero"`
+	Background    colors.Color `json:"background,omitzero"`
+	Bold          bool         `json:"bold,omitempty"`
+	Italic        bool         `json:"italic,omitempty"`
+	Dim           bool         `json:"dim,omitempty"`
+	Underline     bool         `json:"underline,omitempty"`
+	Reverse       bool         `json:"reverse,omitempty"`
+	Strikethrough bool         `json:"strikethrough,omitempty"`
+	Icon          string       `json:"icon,omitempty"`
+	NerdIcon      string       `json:"nerd_icon,omitempty"`
+	Label         string       `json:"label,omitempty"`
+}
+
+// Attributes returns the escape sequences for the style's text attributes.
+func (s Style) Attributes() string {
+	var attrs string
+	if s.Bold {
+		attrs += colors.Bold
+	}
+	if s.Dim {
+		attrs += colors.Dim
+	}
+	if s.Italic {
+		attrs += colors.Italic
+	}
+	if s.Underline {
+		attrs += colors.Underline
+	}
+	if s.Reverse {
+		attrs += colors.Reverse
+	}
+	if s.Strikethrough {
+		attrs += colors.Strikethrough
+	}
+	return attrs
+}
+
+// Theme bundles a Style per message type. A Monochrome theme renders no
+// colors at all, relying on attributes, icons and labels instead.
+type Theme struct {
+	Name       string                  `json:"name"`
+	Base       string                  `json:"base,omitempty"`
+	Monochrome bool                    `json:"monochrome,omitempty"`
+	Styles     map[messages.Type]Style `json:"styles"`
+}
+
+// Style returns the style for msgType, falling back to the messages.Default
+// entry when the theme has no style for the type.
+func (t *Theme) Style(msgType messages.Type) (Style, bool) {
+	if style, exists := t.Styles[msgType]; exists {
+		return style, true
+	}
+	style, exists := t.Styles[messages.Default]
+	return style, exists
+}
+
+// Parse decodes a theme from JSON. Colors are validated while decoding. If
+// Base names a built-in theme, its styles are used for every type the JSON
+// does not define.
+func Parse(data []byte) (*Theme, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	var t Theme
+	if err := dec.Decode(&t); err != nil {
+		return nil, fmt.Errorf("failed to parse theme: %w", err)
+	}
+	if t.Base == "" {
+		return &t, nil
+	}
+
+	base, err := Builtin(t.Base)
+	if err != nil {
+		return nil, fmt.Errorf("theme '%s': %w", t.Name, err)
+	}
+	for msgType, style := range t.Styles {
+		base.Styles[msgType] = style
+	}
+	t.Styles = base.Styles
+	t.Monochrome = t.Monochrome || base.Monochrome
+	return &t, nil
+}
+
+// Load reads and parses a theme file.
+func Load(path string) (*Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read theme file '%s': %w", path, err)
+	}
+	return Parse(data)
+}
+
+// Clone returns a deep copy of the theme.
+func (t *Theme) Clone() *Theme {
+	clone := *t
+	clone.Styles = make(map[messages.Type]Style, len(t.Styles))
+	for k, v := range t.Styles {
+		clone.Styles[k] = v
+	}
+	return &clone
+}
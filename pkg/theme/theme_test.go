@@ -0,0 +1,170 @@
+package theme
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jsas4coding/utify/pkg/colors"
+	"github.com/jsas4coding/utify/pkg/messages"
+)
+
+func TestParse(t *testing.T) {
+	data := []byte(`{
+		"name": "acme",
+		"styles": {
+			"success": {"color": "#00ff00", "bold": true, "icon": "✔", "label": "OK"},
+			"error": {"color": "red", "background": "rgb(0, 0, 0)", "underline": true}
+		}
+	}`)
+
+	th, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if th.Name != "acme" {
+		t.Errorf("Expected name 'acme', got %q", th.Name)
+	}
+
+	style, ok := th.Style(messages.Success)
+	if !ok {
+		t.Fatal("Expected a success style")
+	}
+	if style.Color != colors.RGB(0, 255, 0) || !style.Bold || style.Icon != "✔" || style.Label != "OK" {
+		t.Errorf("Unexpected success style: %+v", style)
+	}
+
+	style, _ = th.Style(messages.Error)
+	if style.Background != colors.RGB(0, 0, 0) || style.Attributes() != colors.Underline {
+		t.Errorf("Unexpected error style: %+v", style)
+	}
+
+	if _, ok := th.Style(messages.Info); ok {
+		t.Error("Expected no style for a type the theme does not define")
+	}
+}
+
+func TestParseRejectsInvalidThemes(t *testing.T) {
+	invalid := map[string]string{
+		"bad color":     `{"name": "x", "styles": {"success": {"color": "#zzzzzz"}}}`,
+		"unknown field": `{"name": "x", "colour": "red"}`,
+		"unknown base":  `{"name": "x", "base": "nope"}`,
+		"not json":      `name: x`,
+	}
+	for name, data := range invalid {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Parse([]byte(data)); err == nil {
+				t.Error("Expected Parse to fail")
+			}
+		})
+	}
+
+	_, err := Parse([]byte(`{"name": "x", "styles": {"success": {"color": "nope"}}}`))
+	if !errors.Is(err, colors.ErrInvalidColor) {
+		t.Errorf("Expected ErrInvalidColor, got %v", err)
+	}
+}
+
+func TestParseWithBase(t *testing.T) {
+	data := []byte(`{"name": "acme", "base": "dracula", "styles": {"success": {"color": "blue"}}}`)
+
+	th, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	style, _ := th.Style(messages.Success)
+	if style.Color != colors.ANSI(4) {
+		t.Errorf("Expected override to replace the base style, got %+v", style)
+	}
+
+	dracula, _ := Builtin(Dracula)
+	expected, _ := dracula.Style(messages.Error)
+	if style, _ := th.Style(messages.Error); style != expected {
+		t.Errorf("Expected base style for error, got %+v", style)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "theme.json")
+	if err := os.WriteFile(path, []byte(`{"name": "file", "monochrome": true}`), 0644); err != nil {
+		t.Fatalf("Failed to write theme: %v", err)
+	}
+
+	th, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if th.Name != "file" || !th.Monochrome {
+		t.Errorf("Unexpected theme: %+v", th)
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Expected Load to fail for a missing file")
+	}
+}
+
+func TestBuiltinThemes(t *testing.T) {
+	names := Names()
+	expected := []string{Default, Dracula, HighContrast, Monochrome, Solarized}
+	if len(names) != len(expected) {
+		t.Fatalf("Expected %d built-in themes, got %v", len(expected), names)
+	}
+
+	for i, name := range expected {
+		if names[i] != name {
+			t.Errorf("Expected theme %q at position %d, got %q", name, i, names[i])
+		}
+		th, err := Builtin(name)
+		if err != nil {
+			t.Fatalf("Builtin(%q) failed: %v", name, err)
+		}
+		if th.Name != name {
+			t.Errorf("Expected theme name %q, got %q", name, th.Name)
+		}
+
+		// Built-in themes must survive a JSON round trip so they can be
+		// exported as a starting point for custom themes.
+		data, err := json.Marshal(th)
+		if err != nil {
+			t.Fatalf("Marshal(%q) failed: %v", name, err)
+		}
+		if _, err := Parse(data); err != nil {
+			t.Errorf("Parse of marshaled %q failed: %v", name, err)
+		}
+	}
+
+	if _, err := Builtin("nope"); !errors.Is(err, ErrUnknownTheme) {
+		t.Errorf("Expected ErrUnknownTheme, got %v", err)
+	}
+}
+
+func TestBuiltinReturnsCopies(t *testing.T) {
+	a, _ := Builtin(Solarized)
+	a.Styles[messages.Success] = Style{Label: "changed"}
+
+	b, _ := Builtin(Solarized)
+	if style, _ := b.Style(messages.Success); style.Label == "changed" {
+		t.Error("Modifying a built-in theme should not affect later copies")
+	}
+
+	clone := b.Clone()
+	clone.Styles[messages.Success] = Style{Label: "cloned"}
+	if style, _ := b.Style(messages.Success); style.Label == "cloned" {
+		t.Error("Modifying a clone should not affect the original")
+	}
+}
+
+func TestStyleDefaultFallback(t *testing.T) {
+	th, _ := Builtin(Dracula)
+	style, ok := th.Style(messages.Type("custom"))
+	if !ok {
+		t.Fatal("Expected unknown types to use the default style")
+	}
+	expected, _ := th.Style(messages.Default)
+	if style != expected {
+		t.Errorf("Expected default style, got %+v", style)
+	}
+}
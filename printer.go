@@ -0,0 +1,872 @@
+package utify
+
+import (
+	"io"
+
+	"github.com/jsas4coding/utify/pkg/colors"
+	"github.com/jsas4coding/utify/pkg/formatter"
+	"github.com/jsas4coding/utify/pkg/icons"
+	"github.com/jsas4coding/utify/pkg/layout"
+	"github.com/jsas4coding/utify/pkg/logger"
+	"github.com/jsas4coding/utify/pkg/messages"
+	"github.com/jsas4coding/utify/pkg/options"
+	"github.com/jsas4coding/utify/pkg/redact"
+	"github.com/jsas4coding/utify/pkg/sloghandler"
+	"github.com/jsas4coding/utify/pkg/theme"
+)
+
+// Messenger is the full message method set implemented by Printer. Depend on
+// it instead of the package-level functions to inject or mock output.
+type Messenger interface {
+	Echo(msgType MessageType, text string, opts *Options, fields ...Field) (string, error)
+	Success(text string, opts *Options, fields ...Field)
+	Error(text string, opts *Options, fields ...Field)
+	Warning(text string, opts *Options, fields ...Field)
+	Info(text string, opts *Options, fields ...Field)
+	Debug(text string, opts *Options, fields ...Field)
+	Critical(text string, opts *Options, fields ...Field)
+	Delete(text string, opts *Options, fields ...Field)
+	Update(text string, opts *Options, fields ...Field)
+	Install(text string, opts *Options, fields ...Field)
+	Upgrade(text string, opts *Options, fields ...Field)
+	Edit(text string, opts *Options, fields ...Field)
+	New(text string, opts *Options, fields ...Field)
+	Download(text string, opts *Options, fields ...Field)
+	Upload(text string, opts *Options, fields ...Field)
+	Sync(text string, opts *Options, fields ...Field)
+	Search(text string, opts *Options, fields ...Field)
+	Successf(text string, opts *Options, args ...any)
+	Errorf(text string, opts *Options, args ...any)
+	Warningf(text string, opts *Options, args ...any)
+	Infof(text string, opts *Options, args ...any)
+	Debugf(text string, opts *Options, args ...any)
+	Criticalf(text string, opts *Options, args ...any)
+	Deletef(text string, opts *Options, args ...any)
+	Updatef(text string, opts *Options, args ...any)
+	Installf(text string, opts *Options, args ...any)
+	Upgradef(text string, opts *Options, args ...any)
+	Editf(text string, opts *Options, args ...any)
+	Newf(text string, opts *Options, args ...any)
+	Downloadf(text string, opts *Options, args ...any)
+	Uploadf(text string, opts *Options, args ...any)
+	Syncf(text string, opts *Options, args ...any)
+	Searchf(text string, opts *Options, args ...any)
+	GetSuccess(text string, opts *Options, fields ...Field) (string, error)
+	GetError(text string, opts *Options, fields ...Field) (string, error)
+	GetWarning(text string, opts *Options, fields ...Field) (string, error)
+	GetInfo(text string, opts *Options, fields ...Field) (string, error)
+	GetDebug(text string, opts *Options, fields ...Field) (string, error)
+	GetCritical(text string, opts *Options, fields ...Field) (string, error)
+	GetDelete(text string, opts *Options, fields ...Field) (string, error)
+	GetUpdate(text string, opts *Options, fields ...Field) (string, error)
+	GetInstall(text string, opts *Options, fields ...Field) (string, error)
+	GetUpgrade(text string, opts *Options, fields ...Field) (string, error)
+	GetEdit(text string, opts *Options, fields ...Field) (string, error)
+	GetNew(text string, opts *Options, fields ...Field) (string, error)
+	GetDownload(text string, opts *Options, fields ...Field) (string, error)
+	GetUpload(text string, opts *Options, fields ...Field) (string, error)
+	GetSync(text string, opts *Options, fields ...Field) (string, error)
+	GetSearch(text string, opts *Options, fields ...Field) (string, error)
+	GetSuccessf(text string, opts *Options, args ...any) (string, error)
+	GetErrorf(text string, opts *Options, args ...any) (string, error)
+	GetWarningf(text string, opts *Options, args ...any) (string, error)
+	GetInfof(text string, opts *Options, args ...any) (string, error)
+	GetDebugf(text string, opts *Options, args ...any) (string, error)
+	GetCriticalf(text string, opts *Options, args ...any) (string, error)
+	GetDeletef(text string, opts *Options, args ...any) (string, error)
+	GetUpdatef(text string, opts *Options, args ...any) (string, error)
+	GetInstallf(text string, opts *Options, args ...any) (string, error)
+	GetUpgradef(text string, opts *Options, args ...any) (string, error)
+	GetEditf(text string, opts *Options, args ...any) (string, error)
+	GetNewf(text string, opts *Options, args ...any) (string, error)
+	GetDownloadf(text string, opts *Options, args ...any) (string, error)
+	GetUploadf(text string, opts *Options, args ...any) (string, error)
+	GetSyncf(text string, opts *Options, args ...any) (string, error)
+	GetSearchf(text string, opts *Options, args ...any) (string, error)
+	LogSuccess(text string, fields ...Field)
+	LogError(text string, fields ...Field)
+	LogWarning(text string, fields ...Field)
+	LogInfo(text string, fields ...Field)
+	LogDebug(text string, fields ...Field)
+	LogCritical(text string, fields ...Field)
+	LogDelete(text string, fields ...Field)
+	LogUpdate(text string, fields ...Field)
+	LogInstall(text string, fields ...Field)
+	LogUpgrade(text string, fields ...Field)
+	LogEdit(text string, fields ...Field)
+	LogNew(text string, fields ...Field)
+	LogDownload(text string, fields ...Field)
+	LogUpload(text string, fields ...Field)
+	LogSync(text string, fields ...Field)
+	LogSearch(text string, fields ...Field)
+	LogSuccessf(text string, args ...any)
+	LogErrorf(text string, args ...any)
+	LogWarningf(text string, args ...any)
+	LogInfof(text string, args ...any)
+	LogDebugf(text string, args ...any)
+	LogCriticalf(text string, args ...any)
+	LogDeletef(text string, args ...any)
+	LogUpdatef(text string, args ...any)
+	LogInstallf(text string, args ...any)
+	LogUpgradef(text string, args ...any)
+	LogEditf(text string, args ...any)
+	LogNewf(text string, args ...any)
+	LogDownloadf(text string, args ...any)
+	LogUploadf(text string, args ...any)
+	LogSyncf(text string, args ...any)
+	LogSearchf(text string, args ...any)
+}
+
+// Printer renders messages with its own output, color table, icon set and
+// logger, so several differently configured printers can coexist.
+type Printer struct {
+	formatter *formatter.Formatter
+}
+
+// PrinterOption configures a Printer created by NewPrinter.
+type PrinterOption func(*formatter.Formatter)
+
+// WithOutput sets the writer messages are printed to.
+func WithOutput(w io.Writer) PrinterOption {
+	return func(f *formatter.Formatter) {
+		f.Output = w
+	}
+}
+
+// WithErrorOutput sets the writer messages routed to stderr are printed to.
+func WithErrorOutput(w io.Writer) PrinterOption {
+	return func(f *formatter.Formatter) {
+		f.ErrorOutput = w
+	}
+}
+
+// WithColorProfile forces the color profile used for both outputs instead
+// of detecting it.
+func WithColorProfile(profile colors.Profile) PrinterOption {
+	return func(f *formatter.Formatter) {
+		f.Profile = profile
+	}
+}
+
+// WithRouting sets the table deciding where each message type is delivered.
+func WithRouting(r Routing) PrinterOption {
+	return func(f *formatter.Formatter) {
+		f.Routing = r.Clone()
+	}
+}
+
+// WithLevels sets the severity thresholds for the console and the log.
+// Without it a Printer follows the package-level thresholds.
+func WithLevels(l Levels) PrinterOption {
+	return func(f *formatter.Formatter) {
+		f.Levels = &l
+	}
+}
+
+// WithVerbosity sets the thresholds for a -q/-v style verbosity; see
+// SetVerbosity.
+func WithVerbosity(verbosity int) PrinterOption {
+	return WithLevels(options.VerbosityLevels(verbosity))
+}
+
+// WithLayout sets the console line layout for all message types.
+func WithLayout(l *layout.Layout) PrinterOption {
+	return func(f *formatter.Formatter) {
+		if f.Layouts == nil {
+			f.Layouts = formatter.GetLayouts()
+		} else {
+			// The table may be shared with the printer this one was made from.
+			f.Layouts = f.Layouts.Clone()
+		}
+		f.Layouts[messages.Default] = l
+	}
+}
+
+// WithLayouts sets the table of console line layouts per message type.
+func WithLayouts(l Layouts) PrinterOption {
+	return func(f *formatter.Formatter) {
+		f.Layouts = l.Clone()
+	}
+}
+
+// WithCallers sets which messages capture their caller. Without it a
+// Printer follows the package-level configuration; see SetCallers.
+func WithCallers(c Callers) PrinterOption {
+	return func(f *formatter.Formatter) {
+		c = c.Clone()
+		f.Callers = &c
+	}
+}
+
+// WithScope sets the scope shown by layouts with a scope token, e.g. the
+// name of the component owning the Printer.
+func WithScope(scope string) PrinterOption {
+	return func(f *formatter.Formatter) {
+		f.Scope = scope
+	}
+}
+
+// WithContextFields sets whether fields extracted from the context of the
+// Ctx methods are shown on the console. They are always logged. Without it
+// a Printer follows SetContextFieldsOnConsole.
+func WithContextFields(onConsole bool) PrinterOption {
+	return func(f *formatter.Formatter) {
+		f.ContextOnConsole = &onConsole
+	}
+}
+
+// WithTheme sets the theme used to style messages.
+func WithTheme(t *theme.Theme) PrinterOption {
+	return func(f *formatter.Formatter) {
+		f.Theme = t
+	}
+}
+
+// WithColorTable sets the color table used to resolve message colors.
+func WithColorTable(table *colors.Table) PrinterOption {
+	return func(f *formatter.Formatter) {
+		f.Colors = table
+	}
+}
+
+// WithIconSet sets the icon set used to render message icons.
+func WithIconSet(set *icons.Set) PrinterOption {
+	return func(f *formatter.Formatter) {
+		f.Icons = set
+	}
+}
+
+// WithRedactor sets the redactor masking secrets in console output. Entries
+// are masked by the redactor of the printer's logger.
+func WithRedactor(r *redact.Redactor) PrinterOption {
+	return func(f *formatter.Formatter) {
+		f.Redactor = r
+	}
+}
+
+// WithLogger sets the structured logger messages are written to.
+func WithLogger(l *logger.Logger) PrinterOption {
+	return func(f *formatter.Formatter) {
+		f.Logger = l
+	}
+}
+
+// NewPrinter returns a Printer with its own empty color table, the default
+// routing table and an icon set initialized from the current icon type. It
+// follows the package-level severity thresholds and layouts unless they are
+// set with printer options.
+// Output defaults to os.Stdout and os.Stderr and logging to the default
+// logger unless overridden by opts.
+func NewPrinter(opts ...PrinterOption) *Printer {
+	f := &formatter.Formatter{
+		Routing: options.DefaultRouting(),
+		Colors:  colors.NewTable(),
+		Icons:   icons.NewSet(icons.GetIconType()),
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return &Printer{formatter: f}
+}
+
+var std = &Printer{formatter: formatter.Default()}
+
+// With returns a copy of the printer with opts applied, e.g. a printer
+// scoped to a component or request:
+//
+//	reqPrinter := p.With(utify.WithScope("api"))
+//
+// The copy shares the color table, icon set and logger of p unless opts
+// replace them.
+func (p *Printer) With(opts ...PrinterOption) *Printer {
+	f := *p.formatter
+	for _, opt := range opts {
+		opt(&f)
+	}
+	return &Printer{formatter: &f}
+}
+
+// DefaultPrinter returns the Printer used by the package-level functions.
+func DefaultPrinter() *Printer {
+	return std
+}
+
+// ColorTable returns the color table used by the printer.
+func (p *Printer) ColorTable() *colors.Table {
+	if p.formatter.Colors != nil {
+		return p.formatter.Colors
+	}
+	return colors.DefaultTable()
+}
+
+// IconSet returns the icon set used by the printer.
+func (p *Printer) IconSet() *icons.Set {
+	if p.formatter.Icons != nil {
+		return p.formatter.Icons
+	}
+	return icons.DefaultSet()
+}
+
+// Logger returns the structured logger used by the printer.
+func (p *Printer) Logger() *logger.Logger {
+	if p.formatter.Logger != nil {
+		return p.formatter.Logger
+	}
+	return logger.Default()
+}
+
+// SlogHandler returns a slog.Handler that renders records through the
+// printer. The Formatter of opts is ignored.
+func (p *Printer) SlogHandler(opts *sloghandler.Options) *sloghandler.Handler {
+	var o sloghandler.Options
+	if opts != nil {
+		o = *opts
+	}
+	o.Formatter = p.formatter
+	return sloghandler.New(&o)
+}
+
+// Echo formats and prints a message of any type.
+func (p *Printer) Echo(msgType MessageType, text string, opts *Options, fields ...Field) (string, error) {
+	return p.formatter.Echo(msgType, text, opts, fields...)
+}
+
+// --- Direct output methods ---
+
+// Success prints a success message.
+func (p *Printer) Success(text string, opts *Options, fields ...Field) {
+	_, _ = p.Echo(MessageSuccess, text, opts, fields...)
+}
+
+// Error prints an error message.
+func (p *Printer) Error(text string, opts *Options, fields ...Field) {
+	_, _ = p.Echo(MessageError, text, opts, fields...)
+}
+
+// Warning prints a warning message.
+func (p *Printer) Warning(text string, opts *Options, fields ...Field) {
+	_, _ = p.Echo(MessageWarning, text, opts, fields...)
+}
+
+// Info prints an info message.
+func (p *Printer) Info(text string, opts *Options, fields ...Field) {
+	_, _ = p.Echo(MessageInfo, text, opts, fields...)
+}
+
+// Debug prints a debug message.
+func (p *Printer) Debug(text string, opts *Options, fields ...Field) {
+	_, _ = p.Echo(MessageDebug, text, opts, fields...)
+}
+
+// Critical prints a critical message.
+func (p *Printer) Critical(text string, opts *Options, fields ...Field) {
+	_, _ = p.Echo(MessageCritical, text, opts, fields...)
+}
+
+// Delete prints a delete message.
+func (p *Printer) Delete(text string, opts *Options, fields ...Field) {
+	_, _ = p.Echo(MessageDelete, text, opts, fields...)
+}
+
+// Update prints an update message.
+func (p *Printer) Update(text string, opts *Options, fields ...Field) {
+	_, _ = p.Echo(MessageUpdate, text, opts, fields...)
+}
+
+// Install prints an install message.
+func (p *Printer) Install(text string, opts *Options, fields ...Field) {
+	_, _ = p.Echo(MessageInstall, text, opts, fields...)
+}
+
+// Upgrade prints an upgrade message.
+func (p *Printer) Upgrade(text string, opts *Options, fields ...Field) {
+	_, _ = p.Echo(MessageUpgrade, text, opts, fields...)
+}
+
+// Edit prints an edit message.
+func (p *Printer) Edit(text string, opts *Options, fields ...Field) {
+	_, _ = p.Echo(MessageEdit, text, opts, fields...)
+}
+
+// New prints a new message.
+func (p *Printer) New(text string, opts *Options, fields ...Field) {
+	_, _ = p.Echo(MessageNew, text, opts, fields...)
+}
+
+// Download prints a download message.
+func (p *Printer) Download(text string, opts *Options, fields ...Field) {
+	_, _ = p.Echo(MessageDownload, text, opts, fields...)
+}
+
+// Upload prints an upload message.
+func (p *Printer) Upload(text string, opts *Options, fields ...Field) {
+	_, _ = p.Echo(MessageUpload, text, opts, fields...)
+}
+
+// Sync prints a sync message.
+func (p *Printer) Sync(text string, opts *Options, fields ...Field) {
+	_, _ = p.Echo(MessageSync, text, opts, fields...)
+}
+
+// Search prints a search message.
+func (p *Printer) Search(text string, opts *Options, fields ...Field) {
+	_, _ = p.Echo(MessageSearch, text, opts, fields...)
+}
+
+// --- Formatted direct output methods (printf style) ---
+
+// Successf prints a formatted success message.
+func (p *Printer) Successf(text string, opts *Options, args ...any) {
+	msg, fields := sprintf(text, args)
+	p.Success(msg, opts, fields...)
+}
+
+// Errorf prints a formatted error message.
+func (p *Printer) Errorf(text string, opts *Options, args ...any) {
+	msg, fields := sprintf(text, args)
+	p.Error(msg, opts, fields...)
+}
+
+// Warningf prints a formatted warning message.
+func (p *Printer) Warningf(text string, opts *Options, args ...any) {
+	msg, fields := sprintf(text, args)
+	p.Warning(msg, opts, fields...)
+}
+
+// Infof prints a formatted info message.
+func (p *Printer) Infof(text string, opts *Options, args ...any) {
+	msg, fields := sprintf(text, args)
+	p.Info(msg, opts, fields...)
+}
+
+// Debugf prints a formatted debug message.
+func (p *Printer) Debugf(text string, opts *Options, args ...any) {
+	msg, fields := sprintf(text, args)
+	p.Debug(msg, opts, fields...)
+}
+
+// Criticalf prints a formatted critical message.
+func (p *Printer) Criticalf(text string, opts *Options, args ...any) {
+	msg, fields := sprintf(text, args)
+	p.Critical(msg, opts, fields...)
+}
+
+// Deletef prints a formatted delete message.
+func (p *Printer) Deletef(text string, opts *Options, args ...any) {
+	msg, fields := sprintf(text, args)
+	p.Delete(msg, opts, fields...)
+}
+
+// Updatef prints a formatted update message.
+func (p *Printer) Updatef(text string, opts *Options, args ...any) {
+	msg, fields := sprintf(text, args)
+	p.Update(msg, opts, fields...)
+}
+
+// Installf prints a formatted install message.
+func (p *Printer) Installf(text string, opts *Options, args ...any) {
+	msg, fields := sprintf(text, args)
+	p.Install(msg, opts, fields...)
+}
+
+// Upgradef prints a formatted upgrade message.
+func (p *Printer) Upgradef(text string, opts *Options, args ...any) {
+	msg, fields := sprintf(text, args)
+	p.Upgrade(msg, opts, fields...)
+}
+
+// Editf prints a formatted edit message.
+func (p *Printer) Editf(text string, opts *Options, args ...any) {
+	msg, fields := sprintf(text, args)
+	p.Edit(msg, opts, fields...)
+}
+
+// Newf prints a formatted new message.
+func (p *Printer) Newf(text string, opts *Options, args ...any) {
+	msg, fields := sprintf(text, args)
+	p.New(msg, opts, fields...)
+}
+
+// Downloadf prints a formatted download message.
+func (p *Printer) Downloadf(text string, opts *Options, args ...any) {
+	msg, fields := sprintf(text, args)
+	p.Download(msg, opts, fields...)
+}
+
+// Uploadf prints a formatted upload message.
+func (p *Printer) Uploadf(text string, opts *Options, args ...any) {
+	msg, fields := sprintf(text, args)
+	p.Upload(msg, opts, fields...)
+}
+
+// Syncf prints a formatted sync message.
+func (p *Printer) Syncf(text string, opts *Options, args ...any) {
+	msg, fields := sprintf(text, args)
+	p.Sync(msg, opts, fields...)
+}
+
+// Searchf prints a formatted search message.
+func (p *Printer) Searchf(text string, opts *Options, args ...any) {
+	msg, fields := sprintf(text, args)
+	p.Search(msg, opts, fields...)
+}
+
+// --- Get methods (return the message text and error) ---
+
+// GetSuccess prints a success message and returns its text.
+func (p *Printer) GetSuccess(text string, opts *Options, fields ...Field) (string, error) {
+	return p.Echo(MessageSuccess, text, opts, fields...)
+}
+
+// GetError prints an error message and returns its text.
+func (p *Printer) GetError(text string, opts *Options, fields ...Field) (string, error) {
+	return p.Echo(MessageError, text, opts, fields...)
+}
+
+// GetWarning prints a warning message and returns its text.
+func (p *Printer) GetWarning(text string, opts *Options, fields ...Field) (string, error) {
+	return p.Echo(MessageWarning, text, opts, fields...)
+}
+
+// GetInfo prints an info message and returns its text.
+func (p *Printer) GetInfo(text string, opts *Options, fields ...Field) (string, error) {
+	return p.Echo(MessageInfo, text, opts, fields...)
+}
+
+// GetDebug prints a debug message and returns its text.
+func (p *Printer) GetDebug(text string, opts *Options, fields ...Field) (string, error) {
+	return p.Echo(MessageDebug, text, opts, fields...)
+}
+
+// GetCritical prints a critical message and returns its text.
+func (p *Printer) GetCritical(text string, opts *Options, fields ...Field) (string, error) {
+	return p.Echo(MessageCritical, text, opts, fields...)
+}
+
+// GetDelete prints a delete message and returns its text.
+func (p *Printer) GetDelete(text string, opts *Options, fields ...Field) (string, error) {
+	return p.Echo(MessageDelete, text, opts, fields...)
+}
+
+// GetUpdate prints an update message and returns its text.
+func (p *Printer) GetUpdate(text string, opts *Options, fields ...Field) (string, error) {
+	return p.Echo(MessageUpdate, text, opts, fields...)
+}
+
+// GetInstall prints an install message and returns its text.
+func (p *Printer) GetInstall(text string, opts *Options, fields ...Field) (string, error) {
+	return p.Echo(MessageInstall, text, opts, fields...)
+}
+
+// GetUpgrade prints an upgrade message and returns its text.
+func (p *Printer) GetUpgrade(text string, opts *Options, fields ...Field) (string, error) {
+	return p.Echo(MessageUpgrade, text, opts, fields...)
+}
+
+// GetEdit prints an edit message and returns its text.
+func (p *Printer) GetEdit(text string, opts *Options, fields ...Field) (string, error) {
+	return p.Echo(MessageEdit, text, opts, fields...)
+}
+
+// GetNew prints a new message and returns its text.
+func (p *Printer) GetNew(text string, opts *Options, fields ...Field) (string, error) {
+	return p.Echo(MessageNew, text, opts, fields...)
+}
+
+// GetDownload prints a download message and returns its text.
+func (p *Printer) GetDownload(text string, opts *Options, fields ...Field) (string, error) {
+	return p.Echo(MessageDownload, text, opts, fields...)
+}
+
+// GetUpload prints an upload message and returns its text.
+func (p *Printer) GetUpload(text string, opts *Options, fields ...Field) (string, error) {
+	return p.Echo(MessageUpload, text, opts, fields...)
+}
+
+// GetSync prints a sync message and returns its text.
+func (p *Printer) GetSync(text string, opts *Options, fields ...Field) (string, error) {
+	return p.Echo(MessageSync, text, opts, fields...)
+}
+
+// GetSearch prints a search message and returns its text.
+func (p *Printer) GetSearch(text string, opts *Options, fields ...Field) (string, error) {
+	return p.Echo(MessageSearch, text, opts, fields...)
+}
+
+// --- Get formatted methods (printf style) ---
+
+// GetSuccessf prints a formatted success message and returns its text.
+func (p *Printer) GetSuccessf(text string, opts *Options, args ...any) (string, error) {
+	msg, fields := sprintf(text, args)
+	return p.GetSuccess(msg, opts, fields...)
+}
+
+// GetErrorf prints a formatted error message and returns its text.
+func (p *Printer) GetErrorf(text string, opts *Options, args ...any) (string, error) {
+	msg, fields := sprintf(text, args)
+	return p.GetError(msg, opts, fields...)
+}
+
+// GetWarningf prints a formatted warning message and returns its text.
+func (p *Printer) GetWarningf(text string, opts *Options, args ...any) (string, error) {
+	msg, fields := sprintf(text, args)
+	return p.GetWarning(msg, opts, fields...)
+}
+
+// GetInfof prints a formatted info message and returns its text.
+func (p *Printer) GetInfof(text string, opts *Options, args ...any) (string, error) {
+	msg, fields := sprintf(text, args)
+	return p.GetInfo(msg, opts, fields...)
+}
+
+// GetDebugf prints a formatted debug message and returns its text.
+func (p *Printer) GetDebugf(text string, opts *Options, args ...any) (string, error) {
+	msg, fields := sprintf(text, args)
+	return p.GetDebug(msg, opts, fields...)
+}
+
+// GetCriticalf prints a formatted critical message and returns its text.
+func (p *Printer) GetCriticalf(text string, opts *Options, args ...any) (string, error) {
+	msg, fields := sprintf(text, args)
+	return p.GetCritical(msg, opts, fields...)
+}
+
+// GetDeletef prints a formatted delete message and returns its text.
+func (p *Printer) GetDeletef(text string, opts *Options, args ...any) (string, error) {
+	msg, fields := sprintf(text, args)
+	return p.GetDelete(msg, opts, fields...)
+}
+
+// GetUpdatef prints a formatted update message and returns its text.
+func (p *Printer) GetUpdatef(text string, opts *Options, args ...any) (string, error) {
+	msg, fields := sprintf(text, args)
+	return p.GetUpdate(msg, opts, fields...)
+}
+
+// GetInstallf prints a formatted install message and returns its text.
+func (p *Printer) GetInstallf(text string, opts *Options, args ...any) (string, error) {
+	msg, fields := sprintf(text, args)
+	return p.GetInstall(msg, opts, fields...)
+}
+
+// GetUpgradef prints a formatted upgrade message and returns its text.
+func (p *Printer) GetUpgradef(text string, opts *Options, args ...any) (string, error) {
+	msg, fields := sprintf(text, args)
+	return p.GetUpgrade(msg, opts, fields...)
+}
+
+// GetEditf prints a formatted edit message and returns its text.
+func (p *Printer) GetEditf(text string, opts *Options, args ...any) (string, error) {
+	msg, fields := sprintf(text, args)
+	return p.GetEdit(msg, opts, fields...)
+}
+
+// GetNewf prints a formatted new message and returns its text.
+func (p *Printer) GetNewf(text string, opts *Options, args ...any) (string, error) {
+	msg, fields := sprintf(text, args)
+	return p.GetNew(msg, opts, fields...)
+}
+
+// GetDownloadf prints a formatted download message and returns its text.
+func (p *Printer) GetDownloadf(text string, opts *Options, args ...any) (string, error) {
+	msg, fields := sprintf(text, args)
+	return p.GetDownload(msg, opts, fields...)
+}
+
+// GetUploadf prints a formatted upload message and returns its text.
+func (p *Printer) GetUploadf(text string, opts *Options, args ...any) (string, error) {
+	msg, fields := sprintf(text, args)
+	return p.GetUpload(msg, opts, fields...)
+}
+
+// GetSyncf prints a formatted sync message and returns its text.
+func (p *Printer) GetSyncf(text string, opts *Options, args ...any) (string, error) {
+	msg, fields := sprintf(text, args)
+	return p.GetSync(msg, opts, fields...)
+}
+
+// GetSearchf prints a formatted search message and returns its text.
+func (p *Printer) GetSearchf(text string, opts *Options, args ...any) (string, error) {
+	msg, fields := sprintf(text, args)
+	return p.GetSearch(msg, opts, fields...)
+}
+
+// --- Log-only methods ---
+
+// LogSuccess logs a success message without printing it.
+func (p *Printer) LogSuccess(text string, fields ...Field) {
+	p.formatter.Log(MessageSuccess, text, fields...)
+}
+
+// LogError logs an error message without printing it.
+func (p *Printer) LogError(text string, fields ...Field) {
+	p.formatter.Log(MessageError, text, fields...)
+}
+
+// LogWarning logs a warning message without printing it.
+func (p *Printer) LogWarning(text string, fields ...Field) {
+	p.formatter.Log(MessageWarning, text, fields...)
+}
+
+// LogInfo logs an info message without printing it.
+func (p *Printer) LogInfo(text string, fields ...Field) {
+	p.formatter.Log(MessageInfo, text, fields...)
+}
+
+// LogDebug logs a debug message without printing it.
+func (p *Printer) LogDebug(text string, fields ...Field) {
+	p.formatter.Log(MessageDebug, text, fields...)
+}
+
+// LogCritical logs a critical message without printing it.
+func (p *Printer) LogCritical(text string, fields ...Field) {
+	p.formatter.Log(MessageCritical, text, fields...)
+}
+
+// LogDelete logs a delete message without printing it.
+func (p *Printer) LogDelete(text string, fields ...Field) {
+	p.formatter.Log(MessageDelete, text, fields...)
+}
+
+// LogUpdate logs an update message without printing it.
+func (p *Printer) LogUpdate(text string, fields ...Field) {
+	p.formatter.Log(MessageUpdate, text, fields...)
+}
+
+// LogInstall logs an install message without printing it.
+func (p *Printer) LogInstall(text string, fields ...Field) {
+	p.formatter.Log(MessageInstall, text, fields...)
+}
+
+// LogUpgrade logs an upgrade message without printing it.
+func (p *Printer) LogUpgrade(text string, fields ...Field) {
+	p.formatter.Log(MessageUpgrade, text, fields...)
+}
+
+// LogEdit logs an edit message without printing it.
+func (p *Printer) LogEdit(text string, fields ...Field) {
+	p.formatter.Log(MessageEdit, text, fields...)
+}
+
+// LogNew logs a new message without printing it.
+func (p *Printer) LogNew(text string, fields ...Field) {
+	p.formatter.Log(MessageNew, text, fields...)
+}
+
+// LogDownload logs a download message without printing it.
+func (p *Printer) LogDownload(text string, fields ...Field) {
+	p.formatter.Log(MessageDownload, text, fields...)
+}
+
+// LogUpload logs an upload message without printing it.
+func (p *Printer) LogUpload(text string, fields ...Field) {
+	p.formatter.Log(MessageUpload, text, fields...)
+}
+
+// LogSync logs a sync message without printing it.
+func (p *Printer) LogSync(text string, fields ...Field) {
+	p.formatter.Log(MessageSync, text, fields...)
+}
+
+// LogSearch logs a search message without printing it.
+func (p *Printer) LogSearch(text string, fields ...Field) {
+	p.formatter.Log(MessageSearch, text, fields...)
+}
+
+// --- Log-only formatted methods (printf style) ---
+
+// LogSuccessf logs a formatted success message without printing it.
+func (p *Printer) LogSuccessf(text string, args ...any) {
+	msg, fields := sprintf(text, args)
+	p.LogSuccess(msg, fields...)
+}
+
+// LogErrorf logs a formatted error message without printing it.
+func (p *Printer) LogErrorf(text string, args ...any) {
+	msg, fields := sprintf(text, args)
+	p.LogError(msg, fields...)
+}
+
+// LogWarningf logs a formatted warning message without printing it.
+func (p *Printer) LogWarningf(text string, args ...any) {
+	msg, fields := sprintf(text, args)
+	p.LogWarning(msg, fields...)
+}
+
+// LogInfof logs a formatted info message without printing it.
+func (p *Printer) LogInfof(text string, args ...any) {
+	msg, fields := sprintf(text, args)
+	p.LogInfo(msg, fields...)
+}
+
+// LogDebugf logs a formatted debug message without printing it.
+func (p *Printer) LogDebugf(text string, args ...any) {
+	msg, fields := sprintf(text, args)
+	p.LogDebug(msg, fields...)
+}
+
+// LogCriticalf logs a formatted critical message without printing it.
+func (p *Printer) LogCriticalf(text string, args ...any) {
+	msg, fields := sprintf(text, args)
+	p.LogCritical(msg, fields...)
+}
+
+// LogDeletef logs a formatted delete message without printing it.
+func (p *Printer) LogDeletef(text string, args ...any) {
+	msg, fields := sprintf(text, args)
+	p.LogDelete(msg, fields...)
+}
+
+// LogUpdatef logs a formatted update message without printing it.
+func (p *Printer) LogUpdatef(text string, args ...any) {
+	msg, fields := sprintf(text, args)
+	p.LogUpdate(msg, fields...)
+}
+
+// LogInstallf logs a formatted install message without printing it.
+func (p *Printer) LogInstallf(text string, args ...any) {
+	msg, fields := sprintf(text, args)
+	p.LogInstall(msg, fields...)
+}
+
+// LogUpgradef logs a formatted upgrade message without printing it.
+func (p *Printer) LogUpgradef(text string, args ...any) {
+	msg, fields := sprintf(text, args)
+	p.LogUpgrade(msg, fields...)
+}
+
+// LogEditf logs a formatted edit message without printing it.
+func (p *Printer) LogEditf(text string, args ...any) {
+	msg, fields := sprintf(text, args)
+	p.LogEdit(msg, fields...)
+}
+
+// LogNewf logs a formatted new message without printing it.
+func (p *Printer) LogNewf(text string, args ...any) {
+	msg, fields := sprintf(text, args)
+	p.LogNew(msg, fields...)
+}
+
+// LogDownloadf logs a formatted download message without printing it.
+func (p *Printer) LogDownloadf(text string, args ...any) {
+	msg, fields := sprintf(text, args)
+	p.LogDownload(msg, fields...)
+}
+
+// LogUploadf logs a formatted upload message without printing it.
+func (p *Printer) LogUploadf(text string, args ...any) {
+	msg, fields := sprintf(text, args)
+	p.LogUpload(msg, fields...)
+}
+
+// LogSyncf logs a formatted sync message without printing it.
+func (p *Printer) LogSyncf(text string, args ...any) {
+	msg, fields := sprintf(text, args)
+	p.LogSync(msg, fields...)
+}
+
+// LogSearchf logs a formatted search message without printing it.
+func (p *Printer) LogSearchf(text string, args ...any) {
+	msg, fields := sprintf(text, args)
+	p.LogSearch(msg, fields...)
+}
@@ -0,0 +1,383 @@
+package utify
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jsas4coding/utify/pkg/colors"
+	"github.com/jsas4coding/utify/pkg/icons"
+	"github.com/jsas4coding/utify/pkg/layout"
+	"github.com/jsas4coding/utify/pkg/logger"
+	"github.com/jsas4coding/utify/pkg/theme"
+)
+
+func TestNewPrinterWritesToOutput(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewPrinter(WithOutput(&buf), WithLogger(logger.NewWriter(nil)))
+
+	p.Success("printer output", OptionsDefault())
+	p.Infof("hello %s", OptionsDefault(), "world")
+
+	out := buf.String()
+	if !strings.Contains(out, "printer output") {
+		t.Errorf("expected output to contain %q, got %q", "printer output", out)
+	}
+	if !strings.Contains(out, "hello world") {
+		t.Errorf("expected output to contain %q, got %q", "hello world", out)
+	}
+}
+
+func TestPrintersAreIndependent(t *testing.T) {
+	var bufA, bufB bytes.Buffer
+	tableA := colors.NewTable()
+	if err := tableA.Set(map[string]string{string(MessageSuccess): "\033[95m"}); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	a := NewPrinter(
+		WithOutput(&bufA),
+		WithColorProfile(colors.ProfileANSI),
+		WithColorTable(tableA),
+		WithIconSet(icons.NewSet(icons.NoIcons)),
+		WithLogger(logger.NewWriter(nil)),
+	)
+	b := NewPrinter(
+		WithOutput(&bufB),
+		WithColorProfile(colors.ProfileANSI),
+		WithIconSet(icons.NewSet(icons.RegularIcons)),
+		WithLogger(logger.NewWriter(nil)),
+	)
+
+	a.Success("from a", OptionsDefault().WithIcon())
+	b.Success("from b", OptionsDefault().WithIcon())
+
+	if !strings.Contains(bufA.String(), "\033[95m") {
+		t.Errorf("expected printer a to use its color table, got %q", bufA.String())
+	}
+	if strings.Contains(bufA.String(), "✅") {
+		t.Errorf("expected printer a to render no icons, got %q", bufA.String())
+	}
+	if strings.Contains(bufB.String(), "\033[95m") {
+		t.Errorf("expected printer b to ignore printer a's colors, got %q", bufB.String())
+	}
+	if !strings.Contains(bufB.String(), "✅") {
+		t.Errorf("expected printer b to render regular icons, got %q", bufB.String())
+	}
+}
+
+func TestPrinterLogsToOwnLogger(t *testing.T) {
+	var out, logBuf bytes.Buffer
+	p := NewPrinter(WithOutput(&out), WithLogger(logger.NewWriter(&logBuf)))
+
+	p.Error("printed and logged", OptionsDefault())
+	p.LogWarningf("logged %d", 42)
+
+	if strings.Contains(out.String(), "logged 42") {
+		t.Errorf("log-only message should not be printed, got %q", out.String())
+	}
+	logged := logBuf.String()
+	if !strings.Contains(logged, "printed and logged") || !strings.Contains(logged, "logged 42") {
+		t.Errorf("expected both entries in the printer's log, got %q", logged)
+	}
+}
+
+func TestPrinterFileLogger(t *testing.T) {
+	target := filepath.Join(t.TempDir(), "printer.log")
+	l, err := logger.New(target)
+	if err != nil {
+		t.Fatalf("logger.New failed: %v", err)
+	}
+	defer l.Close()
+
+	p := NewPrinter(WithOutput(&bytes.Buffer{}), WithLogger(l))
+	if p.Logger() != l {
+		t.Error("expected Logger to return the configured logger")
+	}
+	p.LogInfo("to file")
+	l.Close()
+
+	content, err := os.ReadFile(target)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	if !strings.Contains(string(content), "to file") {
+		t.Errorf("expected log file to contain entry, got %q", content)
+	}
+}
+
+func TestPrinterTheme(t *testing.T) {
+	th, err := theme.Builtin(theme.Monochrome)
+	if err != nil {
+		t.Fatalf("Builtin failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	p := NewPrinter(WithOutput(&buf), WithTheme(th), WithColorProfile(colors.ProfileNone),
+		WithLogger(logger.NewWriter(nil)))
+	p.Info("themed", OptionsDefault())
+
+	style, _ := th.Style("info")
+	if buf.String() != style.Label+" themed\n" {
+		t.Errorf("Expected themed label, got %q", buf.String())
+	}
+}
+
+func TestLoadTheme(t *testing.T) {
+	defer SetTheme(nil)
+
+	path := filepath.Join(t.TempDir(), "theme.json")
+	if err := os.WriteFile(path, []byte(`{"name": "custom", "base": "solarized"}`), 0644); err != nil {
+		t.Fatalf("Failed to write theme: %v", err)
+	}
+	if err := LoadTheme(path); err != nil {
+		t.Fatalf("LoadTheme failed: %v", err)
+	}
+	if GetTheme() == nil || GetTheme().Name != "custom" {
+		t.Errorf("Expected loaded theme to be applied, got %+v", GetTheme())
+	}
+
+	if err := LoadTheme(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Expected LoadTheme to fail for a missing file")
+	}
+	if GetTheme() == nil || GetTheme().Name != "custom" {
+		t.Error("A failed LoadTheme should keep the current theme")
+	}
+}
+
+func TestPrinterLevels(t *testing.T) {
+	var buf bytes.Buffer
+	quiet := NewPrinter(WithOutput(&buf), WithErrorOutput(&buf), WithVerbosity(-1),
+		WithLogger(logger.NewWriter(nil)))
+	quiet.Info("quiet info", OptionsDefault())
+	quiet.Warning("quiet warning", OptionsDefault())
+
+	verbose := NewPrinter(WithOutput(&buf), WithVerbosity(1), WithLogger(logger.NewWriter(nil)))
+	verbose.Debug("verbose debug", OptionsDefault())
+
+	out := buf.String()
+	if strings.Contains(out, "quiet info") || !strings.Contains(out, "quiet warning") ||
+		!strings.Contains(out, "verbose debug") {
+		t.Errorf("Expected printers to apply their own levels, got %q", out)
+	}
+	if GetLevels() != DefaultLevels() {
+		t.Error("Printer levels should not change the package-level thresholds")
+	}
+}
+
+func TestPrinterLayout(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewPrinter(WithOutput(&buf), WithColorProfile(colors.ProfileNone),
+		WithLayout(layout.MustParse("{[scope]} {type}: {message}")), WithScope("worker"),
+		WithLogger(logger.NewWriter(nil)))
+	p.Info("ready", OptionsDefault())
+
+	if buf.String() != "[worker] INFO: ready\n" {
+		t.Errorf("Unexpected output %q", buf.String())
+	}
+	if len(GetLayouts()) != 0 {
+		t.Error("Printer layouts should not change the package-level table")
+	}
+}
+
+func TestSetLayout(t *testing.T) {
+	defer SetLayouts(nil)
+
+	if err := SetLayout("compact"); err != nil {
+		t.Fatalf("SetLayout failed: %v", err)
+	}
+	if err := SetTypeLayout(MessageError, "{type} {message}"); err != nil {
+		t.Fatalf("SetTypeLayout failed: %v", err)
+	}
+	if err := SetLayout("{unknown}"); err == nil {
+		t.Error("Expected SetLayout to reject invalid templates")
+	}
+
+	layouts := GetLayouts()
+	if layouts[Default].String() != "{icon} {message}" || layouts[MessageError].String() != "{type} {message}" {
+		t.Errorf("Unexpected layout table %v", layouts)
+	}
+}
+
+func TestPrinterSlogHandler(t *testing.T) {
+	var buf, logBuf bytes.Buffer
+	p := NewPrinter(WithOutput(&buf), WithColorProfile(colors.ProfileNone), WithLogger(logger.NewWriter(&logBuf)),
+		WithLayout(layout.MustParse("{type} {message} {fields}")))
+
+	log := slog.New(p.SlogHandler(nil))
+	log.Info("listening", "port", 8080)
+
+	if buf.String() != "INFO listening port=8080\n" {
+		t.Errorf("Unexpected output %q", buf.String())
+	}
+	if !strings.Contains(logBuf.String(), `"port":8080`) {
+		t.Errorf("Expected the record in the printer's log, got %q", logBuf.String())
+	}
+}
+
+func TestPrinterGetReturnsErrSilent(t *testing.T) {
+	p := NewPrinter(WithOutput(&bytes.Buffer{}), WithLogger(logger.NewWriter(nil)))
+
+	text, err := p.GetErrorf("failed %d", OptionsDefault(), 1)
+	if text != "failed 1" || err != ErrSilent {
+		t.Errorf("expected (%q, ErrSilent), got (%q, %v)", "failed 1", text, err)
+	}
+
+	text, err = p.GetSuccess("ok", OptionsDefault())
+	if text != "ok" || err != nil {
+		t.Errorf("expected (%q, nil), got (%q, %v)", "ok", text, err)
+	}
+}
+
+func TestDefaultPrinter(t *testing.T) {
+	p := DefaultPrinter()
+	if p.ColorTable() != colors.DefaultTable() {
+		t.Error("default printer should use the default color table")
+	}
+	if p.IconSet() != icons.DefaultSet() {
+		t.Error("default printer should use the default icon set")
+	}
+	if p.Logger() != logger.Default() {
+		t.Error("default printer should use the default logger")
+	}
+}
+
+func TestNopPrinter(t *testing.T) {
+	var m Messenger = NopPrinter{}
+
+	m.Success("ignored", OptionsDefault())
+	m.Errorf("ignored %d", OptionsDefault(), 1)
+	m.LogCritical("ignored")
+	m.LogInfof("ignored %d", 1)
+
+	text, err := m.GetCritical("nop critical", OptionsDefault())
+	if text != "nop critical" || err != ErrSilent {
+		t.Errorf("expected (%q, ErrSilent), got (%q, %v)", "nop critical", text, err)
+	}
+
+	text, err = m.GetInfof("nop %s", OptionsDefault(), "info")
+	if text != "nop info" || err != nil {
+		t.Errorf("expected (%q, nil), got (%q, %v)", "nop info", text, err)
+	}
+}
+
+func TestPrinterCallers(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewPrinter(WithOutput(&buf), WithErrorOutput(&buf), WithColorProfile(colors.ProfileNone),
+		WithLogger(logger.NewWriter(nil)), WithLayout(layout.MustParse("{message} {caller}")),
+		WithCallers(Callers{Console: []MessageType{MessageWarning, MessageInfo}}))
+
+	p.Warning("direct", OptionsDefault())
+	_, _, line, _ := runtime.Caller(0)
+	p.Warningf("formatted %d", OptionsDefault(), 1)
+	slog.New(p.SlogHandler(nil)).Info("slog")
+	p.Success("no caller", OptionsDefault())
+
+	expected := []struct{ message, location string }{
+		{"direct", "/printer_test.go:" + strconv.Itoa(line-1)},
+		{"formatted 1", "/printer_test.go:" + strconv.Itoa(line+1)},
+		{"slog", "/printer_test.go:" + strconv.Itoa(line+2)},
+		{"no caller", ""},
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != len(expected) {
+		t.Fatalf("Expected %d lines, got %q", len(expected), lines)
+	}
+	for i, l := range lines {
+		if !strings.HasPrefix(l, expected[i].message) || !strings.HasSuffix(l, expected[i].location) {
+			t.Errorf("Line %d is %q, expected %q at %q", i, l, expected[i].message, expected[i].location)
+		}
+	}
+	if lines[3] != "no caller" {
+		t.Errorf("Expected no caller on success messages, got %q", lines[3])
+	}
+}
+
+func TestPrinterRedaction(t *testing.T) {
+	var out, logBuf bytes.Buffer
+	p := NewPrinter(WithOutput(&out), WithColorProfile(colors.ProfileNone), WithLogger(logger.NewWriter(&logBuf)))
+
+	if err := AddRedactionRule("ticket", `TICKET-\d+`, ""); err != nil {
+		t.Fatalf("AddRedactionRule failed: %v", err)
+	}
+	defer RemoveRedactionRule("ticket")
+	if err := AddRedactionRule("broken", "(", ""); err == nil {
+		t.Error("Expected an error for an invalid pattern")
+	}
+
+	p.Infof("Logged in with %s for TICKET-42", OptionsDefault(), Secret("tok"), F("user", "ana"))
+
+	for _, s := range []string{out.String(), logBuf.String()} {
+		if strings.Contains(s, "with tok") || strings.Contains(s, "TICKET-42") || !strings.Contains(s, "user") {
+			t.Errorf("Expected the secret and ticket masked, got %q", s)
+		}
+	}
+}
+
+func TestPrinterProgress(t *testing.T) {
+	var errOut bytes.Buffer
+	p := NewPrinter(WithErrorOutput(&errOut), WithColorProfile(colors.ProfileNone))
+
+	bar := p.NewProgress(MessageDownload, "app.tar.gz", 4)
+	bar.Add(4)
+	bar.Done()
+	bar.Wait()
+
+	if !strings.Contains(errOut.String(), "app.tar.gz 100% 4/4") {
+		t.Errorf("Expected the bar on the error output, got %q", errOut.String())
+	}
+}
+
+func TestPrinterSpinner(t *testing.T) {
+	var out, logBuf bytes.Buffer
+	p := NewPrinter(WithOutput(&out), WithColorProfile(colors.ProfileNone), WithLogger(logger.NewWriter(&logBuf)))
+
+	s := p.Spinner(MessageSync, "Syncing repos", OptionsDefault())
+	s.Success("Synced")
+
+	if !strings.Contains(out.String(), "Synced") || strings.Contains(out.String(), "Syncing") {
+		t.Errorf("Expected only the final message, got %q", out.String())
+	}
+	if !strings.Contains(logBuf.String(), `"message":"Synced"`) {
+		t.Errorf("Expected the final message to be logged, got %q", logBuf.String())
+	}
+	if err := SetSpinnerFrames("bogus"); err == nil {
+		t.Error("Expected an error for unknown frames")
+	}
+}
+
+func TestPrinterTaskList(t *testing.T) {
+	var out, logBuf bytes.Buffer
+	p := NewPrinter(WithOutput(&out), WithColorProfile(colors.ProfileNone), WithLogger(logger.NewWriter(&logBuf)))
+
+	list := p.NewTaskList(nil)
+	list.Add("Build").Done()
+	list.Add("Deploy").Skip("dry run")
+	list.Finish()
+
+	for _, text := range []string{"Build (", "Deploy (skipped: dry run)", "1 done, 1 skipped"} {
+		if !strings.Contains(out.String(), text) {
+			t.Errorf("Expected %q in the output, got %q", text, out.String())
+		}
+	}
+}
+
+func TestPrinterTable(t *testing.T) {
+	var out bytes.Buffer
+	p := NewPrinter(WithOutput(&out), WithColorProfile(colors.ProfileNone))
+
+	tb := p.NewTable("NAME", "STATUS")
+	tb.AddRow("api", "running")
+	tb.AddStyledRow(MessageError, "worker", "failed")
+	tb.Print()
+
+	expected := "NAME       STATUS\napi        running\n❌ worker  failed\n"
+	if out.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, out.String())
+	}
+}
@@ -0,0 +1,52 @@
+package utify
+
+import (
+	"github.com/jsas4coding/utify/pkg/progress"
+)
+
+// ProgressBar is an alias for progress.Bar.
+type ProgressBar = progress.Bar
+
+// ProgressGroup is an alias for progress.Group.
+type ProgressGroup = progress.Group
+
+// ProgressOptions is an alias for progress.Options.
+type ProgressOptions = progress.Options
+
+// NewProgress returns a progress bar on stderr styled like messages of
+// msgType. A total of zero or less makes an indeterminate bar:
+//
+//	bar := utify.NewProgress(utify.MessageDownload, "app.tar.gz", size).WithBytes()
+//	_, err := io.Copy(file, bar.Reader(resp.Body))
+//	bar.Done()
+//	bar.Wait()
+func NewProgress(msgType MessageType, message string, total int64) *ProgressBar {
+	return std.NewProgress(msgType, message, total)
+}
+
+// NewProgressGroup returns a group drawing several bars at once, e.g. for
+// parallel downloads.
+func NewProgressGroup(opts *ProgressOptions) *ProgressGroup {
+	return std.NewProgressGroup(opts)
+}
+
+// NewProgress returns a progress bar styled by the printer, drawn on its
+// error output.
+func (p *Printer) NewProgress(msgType MessageType, message string, total int64) *ProgressBar {
+	return p.NewProgressGroup(nil).Add(msgType, message, total)
+}
+
+// NewProgressGroup returns a group of progress bars styled by the printer.
+// Without an Output in opts the bars are drawn on the printer's error
+// output. The Formatter of opts is ignored.
+func (p *Printer) NewProgressGroup(opts *ProgressOptions) *ProgressGroup {
+	var o progress.Options
+	if opts != nil {
+		o = *opts
+	}
+	o.Formatter = p.formatter
+	if o.Output == nil {
+		o.Output = p.formatter.ErrorOutput
+	}
+	return progress.NewGroup(&o)
+}
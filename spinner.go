@@ -0,0 +1,33 @@
+package utify
+
+import (
+	"github.com/jsas4coding/utify/pkg/spinner"
+)
+
+// Spinner starts a spinner animating text in the color of msgType, until it
+// is finished with a message that is printed and logged in its place:
+//
+//	s := utify.Spinner(utify.MessageSync, "Syncing repos", opts)
+//	s.SetText("Syncing repos (2/3)")
+//	s.Success("Synced 3 repos")
+//
+// Nothing animates when the output is not a terminal.
+func Spinner(msgType MessageType, text string, opts *Options) *spinner.Spinner {
+	return std.Spinner(msgType, text, opts)
+}
+
+// SetSpinnerFrames selects the built-in frames of new spinners: "dots"
+// (the default), "line" or "nerd-font".
+func SetSpinnerFrames(name string) error {
+	f, err := spinner.Builtin(name)
+	if err != nil {
+		return err
+	}
+	spinner.SetFrames(f)
+	return nil
+}
+
+// Spinner starts a spinner rendered by the printer, see Spinner.
+func (p *Printer) Spinner(msgType MessageType, text string, opts *Options) *spinner.Spinner {
+	return spinner.Start(p.formatter, msgType, text, opts)
+}
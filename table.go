@@ -0,0 +1,24 @@
+package utify
+
+import (
+	"github.com/jsas4coding/utify/pkg/table"
+)
+
+// Table is an alias for table.Table.
+type Table = table.Table
+
+// NewTable returns a table with a column per header. Rows styled by
+// message type take its color and icon:
+//
+//	t := utify.NewTable("NAME", "STATUS").SetBorder(table.Rounded)
+//	t.AddRow("api", "running")
+//	t.AddStyledRow(utify.MessageError, "worker", "failed")
+//	t.Print()
+func NewTable(headers ...string) *Table {
+	return std.NewTable(headers...)
+}
+
+// NewTable returns a table styled and printed by the printer.
+func (p *Printer) NewTable(headers ...string) *Table {
+	return table.New(&table.Options{Formatter: p.formatter}, headers...)
+}
@@ -0,0 +1,37 @@
+package utify
+
+import (
+	"github.com/jsas4coding/utify/pkg/tasks"
+)
+
+// TaskList is an alias for tasks.List.
+type TaskList = tasks.List
+
+// Task is an alias for tasks.Task.
+type Task = tasks.Task
+
+// TaskListOptions is an alias for tasks.Options.
+type TaskListOptions = tasks.Options
+
+// NewTaskList returns a list showing the live state of tasks and their
+// sub-tasks, with a summary when it is finished:
+//
+//	list := utify.NewTaskList(nil)
+//	deps, build := list.Add("Install dependencies"), list.Add("Build")
+//	_ = deps.Run(installDependencies)
+//	_ = build.Run(runBuild)
+//	list.Finish()
+func NewTaskList(opts *TaskListOptions) *TaskList {
+	return std.NewTaskList(opts)
+}
+
+// NewTaskList returns a task list rendered by the printer. The Formatter of
+// opts is ignored.
+func (p *Printer) NewTaskList(opts *TaskListOptions) *TaskList {
+	var o tasks.Options
+	if opts != nil {
+		o = *opts
+	}
+	o.Formatter = p.formatter
+	return tasks.New(&o)
+}
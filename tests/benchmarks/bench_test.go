@@ -1,10 +1,12 @@
 package benchmarks
 
 import (
+	"io"
 	"testing"
 
 	"github.com/jsas4coding/utify"
 	"github.com/jsas4coding/utify/pkg/formatter"
+	"github.com/jsas4coding/utify/pkg/logger"
 	"github.com/jsas4coding/utify/pkg/messages"
 	"github.com/jsas4coding/utify/pkg/options"
 )
@@ -50,3 +52,35 @@ func BenchmarkGetSuccess(b *testing.B) {
 		_, _ = utify.GetSuccess("Benchmark test", opts)
 	}
 }
+
+func BenchmarkEchoWithCaller(b *testing.B) {
+	f := &formatter.Formatter{Output: io.Discard, Logger: logger.NewWriter(io.Discard)}
+	opts := options.Default().WithCaller()
+	for i := 0; i < b.N; i++ {
+		_, _ = f.Echo(messages.Success, "Benchmark test", opts)
+	}
+}
+
+func BenchmarkEchoWithoutCaller(b *testing.B) {
+	f := &formatter.Formatter{Output: io.Discard, Logger: logger.NewWriter(io.Discard)}
+	opts := options.Default()
+	for i := 0; i < b.N; i++ {
+		_, _ = f.Echo(messages.Success, "Benchmark test", opts)
+	}
+}
+
+func BenchmarkLogMessage(b *testing.B) {
+	l := logger.NewWriter(io.Discard)
+	for i := 0; i < b.N; i++ {
+		l.LogMessage(messages.Info, "Benchmark test")
+	}
+}
+
+func BenchmarkLogMessageAsync(b *testing.B) {
+	l := logger.NewWriter(io.Discard)
+	l.EnableAsync(logger.Async{Policy: logger.DropNewest})
+	defer l.Close()
+	for i := 0; i < b.N; i++ {
+		l.LogMessage(messages.Info, "Benchmark test")
+	}
+}
@@ -2,6 +2,7 @@ package integration
 
 import (
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -9,40 +10,65 @@ import (
 	testutil "github.com/jsas4coding/utify/internal/tests"
 )
 
+// TestMain keeps the default log file, and those of subprocesses, in a
+// temporary state directory instead of the user's.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "utify-state")
+	if err != nil {
+		panic(err)
+	}
+	_ = os.Setenv("XDG_STATE_HOME", dir)
+	_ = utify.SetLogTarget(filepath.Join(dir, "test.log"))
+	code := m.Run()
+	_ = os.RemoveAll(dir)
+	os.Exit(code)
+}
+
 func TestPublicAPICompatibility(t *testing.T) {
 	tests := []struct {
-		name string
-		fn   func()
+		name    string
+		fn      func()
+		capture func(func()) string
 	}{
 		{
 			"Success",
 			func() { utify.Success("Test success", utify.OptionsDefault()) },
+			testutil.CaptureOutput,
 		},
 		{
 			"Error",
 			func() { utify.Error("Test error", utify.OptionsDefault()) },
+			testutil.CaptureStderr,
 		},
 		{
 			"Warning",
 			func() { utify.Warning("Test warning", utify.OptionsDefault()) },
+			testutil.CaptureStderr,
 		},
 		{
 			"Info",
 			func() { utify.Info("Test info", utify.OptionsDefault()) },
+			testutil.CaptureOutput,
 		},
 		{
 			"Debug",
-			func() { utify.Debug("Test debug", utify.OptionsDefault()) },
+			func() {
+				utify.SetVerbosity(1)
+				defer utify.SetVerbosity(0)
+				utify.Debug("Test debug", utify.OptionsDefault())
+			},
+			testutil.CaptureOutput,
 		},
 		{
 			"Critical",
 			func() { utify.Critical("Test critical", utify.OptionsDefault()) },
+			testutil.CaptureStderr,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			output := testutil.CaptureOutput(tt.fn)
+			output := tt.capture(tt.fn)
 
 			if !strings.Contains(output, "Test") {
 				t.Errorf("Expected output to contain 'Test', got %q", output)
@@ -53,26 +79,30 @@ func TestPublicAPICompatibility(t *testing.T) {
 
 func TestFormattedFunctions(t *testing.T) {
 	tests := []struct {
-		name string
-		fn   func()
+		name    string
+		fn      func()
+		capture func(func()) string
 	}{
 		{
 			"Successf",
 			func() { utify.Successf("Test %s", utify.OptionsDefault(), "success") },
+			testutil.CaptureOutput,
 		},
 		{
 			"Errorf",
 			func() { utify.Errorf("Test %s", utify.OptionsDefault(), "error") },
+			testutil.CaptureStderr,
 		},
 		{
 			"Warningf",
 			func() { utify.Warningf("Test %s", utify.OptionsDefault(), "warning") },
+			testutil.CaptureStderr,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			output := testutil.CaptureOutput(tt.fn)
+			output := tt.capture(tt.fn)
 
 			if !strings.Contains(output, "Test") {
 				t.Errorf("Expected output to contain 'Test', got %q", output)
@@ -118,12 +148,14 @@ func TestOptionsChaining(t *testing.T) {
 
 func TestColorTableOverride(t *testing.T) {
 	customColor := "\033[95m"
-	utify.SetColorTable(map[string]string{
+	if err := utify.SetColorTable(map[string]string{
 		string(utify.MessageSuccess): customColor,
-	})
+	}); err != nil {
+		t.Fatalf("SetColorTable failed: %v", err)
+	}
 
 	output := testutil.CaptureOutput(func() {
-		utify.Success("Custom color test", utify.OptionsDefault().WithoutStyle())
+		utify.Success("Custom color test", utify.OptionsDefault().WithoutStyle().WithColor())
 	})
 
 	if !strings.Contains(output, customColor) {
@@ -236,7 +268,7 @@ func TestIconFunctionality(t *testing.T) {
 
 	// Test Nerd Font icons
 	utify.ForceNerdFont()
-	output = testutil.CaptureOutput(func() {
+	output = testutil.CaptureStderr(func() {
 		utify.Error("Nerd font test", utify.OptionsDefault().WithIcon())
 	})
 
@@ -29,6 +29,10 @@ Formatted output:
 
 	utify.Infof("Hello %s!", opts, "World")
 
+Structured fields, shown as key=value pairs and logged as JSON properties:
+
+	utify.Info("Deployed", opts, utify.F("service", "api"), utify.F("took", d))
+
 Returning messages without printing:
 
 	msg, err := utify.GetSuccess("Hello!", opts)
@@ -45,16 +49,85 @@ Logging-only (no stdout output):
 
 Colors and icons can be fully customized:
 
-	utify.SetColorTable(map[string]string{
-	    "Red": "#FF0000",
-	    "Green": "#00FF00",
+	err := utify.SetColorTable(map[string]string{
+	    "success": "#00FF00",
+	    "error":   "rgb(255, 0, 0)",
+	    "info":    "39",
 	})
 	utify.ForceNerdFont()   // Force Nerd Font icons
 	utify.DisableIcons()    // Disable icons completely
 
+Themes bundle color, background, attributes, icon and label per message type
+and are applied with one call:
+
+	t, _ := theme.Builtin(theme.Dracula)
+	utify.SetTheme(t)
+	err := utify.LoadTheme("/etc/acme/utify-theme.json")
+
+# Custom message types
+
+Applications can add their own message types at runtime:
+
+	_ = utify.RegisterType("rollback", utify.Definition{
+	    Color:    colors.MustParse("#ff8700"),
+	    Icon:     "↩",
+	    NerdIcon: "\uf0e2",
+	    Label:    "ROLLBACK",
+	    Severity: utify.SeverityWarn,
+	})
+	utify.Echo("rollback", "Reverted to v1.4.2", opts)
+
+# Layouts
+
+Console lines are rendered from a layout template with the tokens
+timestamp, elapsed, type, label, scope, icon, message, fields and caller.
+Use a preset or write a template, for all types or per type:
+
+	_ = utify.SetLayout("timestamped")
+	_ = utify.SetTypeLayout(utify.MessageError, "{timestamp:dim} {type:bold,red} {message}")
+
+# log/slog
+
+SlogHandler renders slog records with utify icons, colors and layouts and
+writes them to the utify JSON log; slog attributes become fields.
+
+# Severity levels
+
+Every message type has a severity (trace, debug, info, notice, warn, error,
+critical). Messages below the console threshold are not printed and those
+below the log threshold are not logged; by default debug messages are
+logged but not printed. Map command-line flags onto the thresholds with:
+
+	utify.SetVerbosity(verbose - quiet) // e.g. -v, -vv, -q
+	utify.SetConsoleLevel(utify.SeverityWarn)
+
+# Terminal detection
+
+Colors and styles are only emitted when the output supports them. Detection
+runs separately for stdout and stderr and honors NO_COLOR, FORCE_COLOR,
+CLICOLOR, CLICOLOR_FORCE, TERM=dumb and common CI environments. Use
+Options.WithColor or Options.WithoutColor to override it per call, or
+SetColorProfile to force a profile globally.
+
+# Printers
+
+The package-level functions use a default Printer. Components that need
+their own output, colors, icons or logger can create an independent one:
+
+	var buf bytes.Buffer
+	p := utify.NewPrinter(utify.WithOutput(&buf))
+	p.Success("Written to buf", opts)
+
+Code that accepts a utify.Messenger can be given a Printer in production and
+a utify.NopPrinter (or any mock) in tests.
+
 # Logging
 
-Utify can log messages to a configurable target:
+By default messages are logged to $XDG_STATE_HOME/<binary>/<binary>.log,
+created when the first entry is written. The UTIFY_LOG_FILE,
+UTIFY_LOG_DISABLE, UTIFY_LOG_FORMAT and UTIFY_LOG_LEVEL environment
+variables configure logging without code changes. Utify can also log
+messages to a target set in code:
 
 	_ = utify.SetLogTarget("/var/log/myapp.log")
 	utify.LogError("An error occurred")
@@ -66,14 +139,16 @@ Logging can be enabled or disabled at runtime using:
 package utify
 
 import (
-	"fmt"
-
 	"github.com/jsas4coding/utify/pkg/colors"
 	"github.com/jsas4coding/utify/pkg/formatter"
 	"github.com/jsas4coding/utify/pkg/icons"
+	"github.com/jsas4coding/utify/pkg/layout"
 	"github.com/jsas4coding/utify/pkg/logger"
 	"github.com/jsas4coding/utify/pkg/messages"
 	"github.com/jsas4coding/utify/pkg/options"
+	"github.com/jsas4coding/utify/pkg/redact"
+	"github.com/jsas4coding/utify/pkg/sloghandler"
+	"github.com/jsas4coding/utify/pkg/theme"
 )
 
 // MessageType is an alias for messages.Type for backward compatibility.
@@ -82,11 +157,83 @@ type MessageType = messages.Type
 // Options is an alias for options.Options for backward compatibility.
 type Options = options.Options
 
+// Definition is an alias for messages.Definition.
+type Definition = messages.Definition
+
+// Severity is an alias for messages.Severity.
+type Severity = messages.Severity
+
+// Message severities, from the most verbose to the most severe.
+const (
+	SeverityTrace    = messages.SeverityTrace
+	SeverityDebug    = messages.SeverityDebug
+	SeverityInfo     = messages.SeverityInfo
+	SeverityNotice   = messages.SeverityNotice
+	SeverityWarn     = messages.SeverityWarn
+	SeverityError    = messages.SeverityError
+	SeverityCritical = messages.SeverityCritical
+)
+
+// Levels is an alias for options.Levels.
+type Levels = options.Levels
+
+// Layouts is an alias for layout.Layouts.
+type Layouts = layout.Layouts
+
+// LogRotation is an alias for logger.Rotation.
+type LogRotation = logger.Rotation
+
+// LogSink is an alias for logger.Sink.
+type LogSink = logger.Sink
+
+// LogEncoder is an alias for logger.Encoder.
+type LogEncoder = logger.Encoder
+
+// LogAsync is an alias for logger.Async.
+type LogAsync = logger.Async
+
+// Policies for a full async log queue, see LogAsync.
+const (
+	LogBlock      = logger.Block
+	LogDropNewest = logger.DropNewest
+	LogDropOldest = logger.DropOldest
+)
+
+// Rotation intervals for LogRotation.Interval.
+const (
+	RotateHourly = logger.Hourly
+	RotateDaily  = logger.Daily
+)
+
+// Callers is an alias for options.Callers.
+type Callers = options.Callers
+
+// Secret is an alias for redact.Secret, a string that always renders
+// masked:
+//
+//	utify.Infof("Logged in with %s", opts, utify.Secret(token))
+type Secret = redact.Secret
+
+// RedactionRule is an alias for redact.Rule.
+type RedactionRule = redact.Rule
+
+// Route is an alias for options.Route.
+type Route = options.Route
+
+// Routing is an alias for options.Routing.
+type Routing = options.Routing
+
+// Message destinations that can be combined into a Route.
+const (
+	RouteStdout   = options.RouteStdout
+	RouteStderr   = options.RouteStderr
+	RouteLog      = options.RouteLog
+	RouteCallback = options.RouteCallback
+)
+
 var (
 	// ErrSilent is returned when output is intentionally silenced.
 	ErrSilent = formatter.ErrSilent
-	// Echo formats and prints a message to the terminal.
-	Echo = formatter.Echo
 
 	// Predefined message types for quick use.
 	MessageSuccess    = messages.Success
@@ -136,9 +283,187 @@ func OptionsDefault() *Options {
 	return options.Default()
 }
 
-// SetColorTable overrides the default color table with a custom one.
-func SetColorTable(newColors map[string]string) {
-	colors.SetColorTable(newColors)
+// SetColorTable overrides message type colors. Values may be hex ("#ff8800"),
+// "rgb(255, 136, 0)", a 256-palette index, a color name or an ANSI sequence;
+// they are downsampled to what the terminal supports. Invalid values are
+// rejected with an error and leave the table unchanged.
+func SetColorTable(newColors map[string]string) error {
+	return colors.SetColorTable(newColors)
+}
+
+// RegisterType adds a custom message type. Registered types are printed with
+// Echo and get the color, icons, label and error behavior of their
+// definition; themes, color tables and routing tables can refer to them by
+// name. Predefined types cannot be replaced.
+func RegisterType(msgType MessageType, def Definition) error {
+	return messages.Register(msgType, def)
+}
+
+// SetLayout sets the console line layout for all message types. spec is a
+// preset name ("default", "compact", "labeled", "timestamped") or a template
+// such as "{timestamp:dim} {icon} {message} {fields}".
+func SetLayout(spec string) error {
+	l, err := layout.Resolve(spec)
+	if err != nil {
+		return err
+	}
+	formatter.SetLayout(messages.Default, l)
+	return nil
+}
+
+// SetTypeLayout sets the console line layout for one message type,
+// overriding the layout set with SetLayout.
+func SetTypeLayout(msgType MessageType, spec string) error {
+	l, err := layout.Resolve(spec)
+	if err != nil {
+		return err
+	}
+	formatter.SetLayout(msgType, l)
+	return nil
+}
+
+// SetLayouts replaces the layout table. The messages.Default entry applies
+// to types without their own entry; an empty table restores the default
+// layout.
+func SetLayouts(l Layouts) {
+	formatter.SetLayouts(l)
+}
+
+// GetLayouts returns a copy of the layout table.
+func GetLayouts() Layouts {
+	return formatter.GetLayouts()
+}
+
+// SetTheme applies a theme to the package-level functions. Use
+// theme.Builtin for the built-in themes; nil restores the built-in look.
+func SetTheme(t *theme.Theme) {
+	formatter.SetTheme(t)
+}
+
+// GetTheme returns the theme used by the package-level functions, or nil.
+func GetTheme() *theme.Theme {
+	return formatter.GetTheme()
+}
+
+// LoadTheme reads a JSON theme file and applies it.
+func LoadTheme(path string) error {
+	t, err := theme.Load(path)
+	if err != nil {
+		return err
+	}
+	formatter.SetTheme(t)
+	return nil
+}
+
+// SetColorProfile forces the color profile used by the package-level
+// functions. colors.ProfileAuto restores terminal detection.
+func SetColorProfile(profile colors.Profile) {
+	formatter.SetProfile(profile)
+}
+
+// GetColorProfile returns the forced color profile, or colors.ProfileAuto
+// when it is detected per output.
+func GetColorProfile() colors.Profile {
+	return formatter.GetProfile()
+}
+
+// SetCallers sets which messages capture the file, line and function they
+// were called from. The caller is shown by the {caller} layout token for
+// the Console types and recorded in log entries if Log is set. The zero
+// value turns capture off at no cost.
+func SetCallers(c Callers) {
+	formatter.SetCallers(c)
+}
+
+// GetCallers returns the caller capture configuration.
+func GetCallers() Callers {
+	return formatter.GetCallers()
+}
+
+// SetRedactionEnabled enables or disables the masking of secrets in console
+// output and log entries. Secret values are masked either way.
+func SetRedactionEnabled(enabled bool) {
+	redact.Default().SetEnabled(enabled)
+}
+
+// AddRedactionRule masks the matches of pattern in message text and string
+// field values, replacing a rule with the same name. replace is expanded
+// like regexp.Regexp.ReplaceAllString; if empty the whole match is masked.
+func AddRedactionRule(name, pattern, replace string) error {
+	rule, err := redact.NewRule(name, pattern, replace)
+	if err != nil {
+		return err
+	}
+	redact.Default().AddRule(rule)
+	return nil
+}
+
+// RemoveRedactionRule removes a redaction rule, including the built-in
+// ones, and reports whether it existed.
+func RemoveRedactionRule(name string) bool {
+	return redact.Default().RemoveRule(name)
+}
+
+// AddRedactedFields masks the values of fields with the given names, in
+// addition to built-in names such as password and authorization.
+func AddRedactedFields(names ...string) {
+	redact.Default().AddKeys(names...)
+}
+
+// SetRouting replaces the table deciding where each message type is delivered.
+func SetRouting(r Routing) {
+	formatter.SetRouting(r)
+}
+
+// GetRouting returns a copy of the current routing table.
+func GetRouting() Routing {
+	return formatter.GetRouting()
+}
+
+// DefaultRouting returns the default routing table, which sends errors,
+// critical messages and warnings to stderr and everything else to stdout.
+func DefaultRouting() Routing {
+	return options.DefaultRouting()
+}
+
+// DefaultLevels returns the default thresholds: info and above on the
+// console, debug and above in the log.
+func DefaultLevels() Levels {
+	return options.DefaultLevels()
+}
+
+// SetLevels sets the minimum severities printed to the console and written
+// to the log. A UTIFY_LOG_LEVEL setting is also applied by the logger and
+// takes precedence: the log threshold cannot be lowered below it.
+func SetLevels(l Levels) {
+	formatter.SetLevels(l)
+}
+
+// GetLevels returns the current severity thresholds.
+func GetLevels() Levels {
+	return formatter.GetLevels()
+}
+
+// SetConsoleLevel sets the minimum severity printed to the console.
+func SetConsoleLevel(s Severity) {
+	formatter.SetConsoleLevel(s)
+}
+
+// SetLogLevel sets the minimum severity written to the log. A
+// UTIFY_LOG_LEVEL setting is also applied by the logger and takes
+// precedence, so entries less severe than it are still skipped; use
+// logger.Default().SetLevel to change it.
+func SetLogLevel(s Severity) {
+	formatter.SetLogLevel(s)
+}
+
+// SetVerbosity sets both thresholds from a -q/-v style count: 0 is the
+// default (info on the console, debug in the log), -1 shows only warnings
+// and above, -2 only errors, 1 adds debug messages and 2 trace messages.
+// As with SetLogLevel, a UTIFY_LOG_LEVEL setting takes precedence for the
+// log, so 2 does not log trace messages when it is e.g. warn.
+func SetVerbosity(verbosity int) {
+	formatter.SetLevels(options.VerbosityLevels(verbosity))
 }
 
 // SetLogTarget sets the destination for structured logs (e.g., file path or stdout).
@@ -151,6 +476,64 @@ func GetLogTarget() string {
 	return logger.GetLogTarget()
 }
 
+// SetLogRotation sets how the log file is rotated, e.g. by size or daily,
+// and how many rotated files are kept. The zero LogRotation disables it.
+func SetLogRotation(r LogRotation) error {
+	return logger.SetRotation(r)
+}
+
+// AddLogSink adds a destination receiving every structured log entry in
+// addition to the log file, e.g. logger.NewStderrSink(logger.TextEncoder{},
+// utify.SeverityDebug). CloseLogger closes it.
+func AddLogSink(s LogSink) {
+	logger.AddSink(s)
+}
+
+// RemoveLogSink removes a sink added with AddLogSink without closing it.
+func RemoveLogSink(s LogSink) bool {
+	return logger.RemoveSink(s)
+}
+
+// EnableAsyncLogging queues structured log entries for a background writer
+// so that logging does not block the caller. Call FlushLogger or
+// CloseLogger before exiting; WithExit does so automatically.
+func EnableAsyncLogging(a LogAsync) {
+	logger.EnableAsync(a)
+}
+
+// DisableAsyncLogging writes the queued entries and logs synchronously again.
+func DisableAsyncLogging() {
+	logger.DisableAsync()
+}
+
+// FlushLogger waits until every queued log entry has been written.
+func FlushLogger() {
+	logger.Flush()
+}
+
+// DroppedLogEntries returns the number of log entries discarded because the
+// async queue was full.
+func DroppedLogEntries() uint64 {
+	return logger.Dropped()
+}
+
+// SetLogEncoder sets how entries are encoded in the log file, e.g.
+// logger.LogfmtEncoder{} or logger.ECSEncoder{}. Nil restores JSON.
+func SetLogEncoder(enc LogEncoder) {
+	logger.SetEncoder(enc)
+}
+
+// SetLogFormat sets the log file encoding by name: "json", "logfmt",
+// "text", "ecs" or "gelf".
+func SetLogFormat(name string) error {
+	enc, err := logger.EncoderByName(name)
+	if err != nil {
+		return err
+	}
+	logger.SetEncoder(enc)
+	return nil
+}
+
 // SetLoggingEnabled enables or disables structured logging.
 func SetLoggingEnabled(enabled bool) {
 	logger.SetEnabled(enabled)
@@ -161,7 +544,14 @@ func IsLoggingEnabled() bool {
 	return logger.IsEnabled()
 }
 
-// CloseLogger closes any active log writers.
+// LoggerErr returns why structured logging is not writing to the target
+// it was configured with, e.g. because the default location or
+// UTIFY_LOG_FILE was not writable, or nil.
+func LoggerErr() error {
+	return logger.Err()
+}
+
+// CloseLogger writes queued entries and closes any active log writers.
 func CloseLogger() {
 	logger.Close()
 }
@@ -191,494 +581,555 @@ func GetIconType() int {
 	return int(icons.GetIconType())
 }
 
-// --- Direct output functions (print to stdout) ---
+// SlogHandler returns a slog.Handler rendering records like the
+// package-level functions. Use it to switch a program to utify output:
+//
+//	slog.SetDefault(slog.New(utify.SlogHandler(nil)))
+func SlogHandler(opts *sloghandler.Options) *sloghandler.Handler {
+	return std.SlogHandler(opts)
+}
+
+// Echo formats and prints a message to the terminal.
+func Echo(msgType MessageType, text string, opts *Options, fields ...Field) (string, error) {
+	return std.Echo(msgType, text, opts, fields...)
+}
+
+// --- Direct output functions (print to stdout, or stderr for errors and warnings) ---
 
 // Success prints a success message to stdout.
-func Success(text string, opts *Options) {
-	_, _ = Echo(MessageSuccess, text, opts)
+func Success(text string, opts *Options, fields ...Field) {
+	std.Success(text, opts, fields...)
 }
 
-// Error prints an error message to stdout.
-func Error(text string, opts *Options) {
-	_, _ = Echo(MessageError, text, opts)
+// Error prints an error message to stderr.
+func Error(text string, opts *Options, fields ...Field) {
+	std.Error(text, opts, fields...)
 }
 
-// Warning prints a warning message to stdout.
-func Warning(text string, opts *Options) {
-	_, _ = Echo(MessageWarning, text, opts)
+// Warning prints a warning message to stderr.
+func Warning(text string, opts *Options, fields ...Field) {
+	std.Warning(text, opts, fields...)
 }
 
 // Info prints an info message to stdout.
-func Info(text string, opts *Options) {
-	_, _ = Echo(MessageInfo, text, opts)
+func Info(text string, opts *Options, fields ...Field) {
+	std.Info(text, opts, fields...)
 }
 
 // Debug prints a debug message to stdout.
-func Debug(text string, opts *Options) {
-	_, _ = Echo(MessageDebug, text, opts)
+func Debug(text string, opts *Options, fields ...Field) {
+	std.Debug(text, opts, fields...)
 }
 
-// Critical prints a critical message to stdout.
-func Critical(text string, opts *Options) {
-	_, _ = Echo(MessageCritical, text, opts)
+// Critical prints a critical message to stderr.
+func Critical(text string, opts *Options, fields ...Field) {
+	std.Critical(text, opts, fields...)
 }
 
 // Delete prints a delete message to stdout.
-func Delete(text string, opts *Options) {
-	_, _ = Echo(MessageDelete, text, opts)
+func Delete(text string, opts *Options, fields ...Field) {
+	std.Delete(text, opts, fields...)
 }
 
 // Update prints an update message to stdout.
-func Update(text string, opts *Options) {
-	_, _ = Echo(MessageUpdate, text, opts)
+func Update(text string, opts *Options, fields ...Field) {
+	std.Update(text, opts, fields...)
 }
 
 // Install prints an install message to stdout.
-func Install(text string, opts *Options) {
-	_, _ = Echo(MessageInstall, text, opts)
+func Install(text string, opts *Options, fields ...Field) {
+	std.Install(text, opts, fields...)
 }
 
 // Upgrade prints an upgrade message to stdout.
-func Upgrade(text string, opts *Options) {
-	_, _ = Echo(MessageUpgrade, text, opts)
+func Upgrade(text string, opts *Options, fields ...Field) {
+	std.Upgrade(text, opts, fields...)
 }
 
 // Edit prints an edit message to stdout.
-func Edit(text string, opts *Options) {
-	_, _ = Echo(MessageEdit, text, opts)
+func Edit(text string, opts *Options, fields ...Field) {
+	std.Edit(text, opts, fields...)
 }
 
 // New prints a new message to stdout.
-func New(text string, opts *Options) {
-	_, _ = Echo(MessageNew, text, opts)
+func New(text string, opts *Options, fields ...Field) {
+	std.New(text, opts, fields...)
 }
 
 // Download prints a download message to stdout.
-func Download(text string, opts *Options) {
-	_, _ = Echo(MessageDownload, text, opts)
+func Download(text string, opts *Options, fields ...Field) {
+	std.Download(text, opts, fields...)
 }
 
 // Upload prints an upload message to stdout.
-func Upload(text string, opts *Options) {
-	_, _ = Echo(MessageUpload, text, opts)
+func Upload(text string, opts *Options, fields ...Field) {
+	std.Upload(text, opts, fields...)
 }
 
 // Sync prints a sync message to stdout.
-func Sync(text string, opts *Options) {
-	_, _ = Echo(MessageSync, text, opts)
+func Sync(text string, opts *Options, fields ...Field) {
+	std.Sync(text, opts, fields...)
 }
 
 // Search prints a search message to stdout.
-func Search(text string, opts *Options) {
-	_, _ = Echo(MessageSearch, text, opts)
+func Search(text string, opts *Options, fields ...Field) {
+	std.Search(text, opts, fields...)
 }
 
 // --- Formatted direct output functions (printf style) ---
 
 // Successf prints a formatted success message to stdout.
 func Successf(text string, opts *Options, args ...any) {
-	Success(fmt.Sprintf(text, args...), opts)
+	msg, fields := sprintf(text, args)
+	Success(msg, opts, fields...)
 }
 
-// Errorf prints a formatted error message to stdout.
+// Errorf prints a formatted error message to stderr.
 func Errorf(text string, opts *Options, args ...any) {
-	Error(fmt.Sprintf(text, args...), opts)
+	msg, fields := sprintf(text, args)
+	Error(msg, opts, fields...)
 }
 
-// Warningf prints a formatted warning message to stdout.
+// Warningf prints a formatted warning message to stderr.
 func Warningf(text string, opts *Options, args ...any) {
-	Warning(fmt.Sprintf(text, args...), opts)
+	msg, fields := sprintf(text, args)
+	Warning(msg, opts, fields...)
 }
 
 // Infof prints a formatted info message to stdout.
 func Infof(text string, opts *Options, args ...any) {
-	Info(fmt.Sprintf(text, args...), opts)
+	msg, fields := sprintf(text, args)
+	Info(msg, opts, fields...)
 }
 
 // Debugf prints a formatted debug message to stdout.
 func Debugf(text string, opts *Options, args ...any) {
-	Debug(fmt.Sprintf(text, args...), opts)
+	msg, fields := sprintf(text, args)
+	Debug(msg, opts, fields...)
 }
 
-// Criticalf prints a formatted critical message to stdout.
+// Criticalf prints a formatted critical message to stderr.
 func Criticalf(text string, opts *Options, args ...any) {
-	Critical(fmt.Sprintf(text, args...), opts)
+	msg, fields := sprintf(text, args)
+	Critical(msg, opts, fields...)
 }
 
 // Deletef prints a formatted delete message to stdout.
 func Deletef(text string, opts *Options, args ...any) {
-	Delete(fmt.Sprintf(text, args...), opts)
+	msg, fields := sprintf(text, args)
+	Delete(msg, opts, fields...)
 }
 
 // Updatef prints a formatted update message to stdout.
 func Updatef(text string, opts *Options, args ...any) {
-	Update(fmt.Sprintf(text, args...), opts)
+	msg, fields := sprintf(text, args)
+	Update(msg, opts, fields...)
 }
 
 // Installf prints a formatted install message to stdout.
 func Installf(text string, opts *Options, args ...any) {
-	Install(fmt.Sprintf(text, args...), opts)
+	msg, fields := sprintf(text, args)
+	Install(msg, opts, fields...)
 }
 
 // Upgradef prints a formatted upgrade message to stdout.
 func Upgradef(text string, opts *Options, args ...any) {
-	Upgrade(fmt.Sprintf(text, args...), opts)
+	msg, fields := sprintf(text, args)
+	Upgrade(msg, opts, fields...)
 }
 
 // Editf prints a formatted edit message to stdout.
 func Editf(text string, opts *Options, args ...any) {
-	Edit(fmt.Sprintf(text, args...), opts)
+	msg, fields := sprintf(text, args)
+	Edit(msg, opts, fields...)
 }
 
 // Newf prints a formatted new message to stdout.
 func Newf(text string, opts *Options, args ...any) {
-	New(fmt.Sprintf(text, args...), opts)
+	msg, fields := sprintf(text, args)
+	New(msg, opts, fields...)
 }
 
 // Downloadf prints a formatted download message to stdout.
 func Downloadf(text string, opts *Options, args ...any) {
-	Download(fmt.Sprintf(text, args...), opts)
+	msg, fields := sprintf(text, args)
+	Download(msg, opts, fields...)
 }
 
 // Uploadf prints a formatted upload message to stdout.
 func Uploadf(text string, opts *Options, args ...any) {
-	Upload(fmt.Sprintf(text, args...), opts)
+	msg, fields := sprintf(text, args)
+	Upload(msg, opts, fields...)
 }
 
 // Syncf prints a formatted sync message to stdout.
 func Syncf(text string, opts *Options, args ...any) {
-	Sync(fmt.Sprintf(text, args...), opts)
+	msg, fields := sprintf(text, args)
+	Sync(msg, opts, fields...)
 }
 
 // Searchf prints a formatted search message to stdout.
 func Searchf(text string, opts *Options, args ...any) {
-	Search(fmt.Sprintf(text, args...), opts)
+	msg, fields := sprintf(text, args)
+	Search(msg, opts, fields...)
 }
 
 // --- Get functions (return formatted strings instead of printing) ---
 
 // GetSuccess returns a formatted success message as a string.
-func GetSuccess(text string, opts *Options) (string, error) {
-	return Echo(MessageSuccess, text, opts)
+func GetSuccess(text string, opts *Options, fields ...Field) (string, error) {
+	return std.GetSuccess(text, opts, fields...)
 }
 
 // GetError returns a formatted error message as a string.
-func GetError(text string, opts *Options) (string, error) {
-	return Echo(MessageError, text, opts)
+func GetError(text string, opts *Options, fields ...Field) (string, error) {
+	return std.GetError(text, opts, fields...)
 }
 
 // GetWarning returns a formatted warning message as a string.
-func GetWarning(text string, opts *Options) (string, error) {
-	return Echo(MessageWarning, text, opts)
+func GetWarning(text string, opts *Options, fields ...Field) (string, error) {
+	return std.GetWarning(text, opts, fields...)
 }
 
 // GetInfo returns a formatted info message as a string.
-func GetInfo(text string, opts *Options) (string, error) {
-	return Echo(MessageInfo, text, opts)
+func GetInfo(text string, opts *Options, fields ...Field) (string, error) {
+	return std.GetInfo(text, opts, fields...)
 }
 
 // GetDebug returns a formatted debug message as a string.
-func GetDebug(text string, opts *Options) (string, error) {
-	return Echo(MessageDebug, text, opts)
+func GetDebug(text string, opts *Options, fields ...Field) (string, error) {
+	return std.GetDebug(text, opts, fields...)
 }
 
 // GetCritical returns a formatted critical message as a string.
-func GetCritical(text string, opts *Options) (string, error) {
-	return Echo(MessageCritical, text, opts)
+func GetCritical(text string, opts *Options, fields ...Field) (string, error) {
+	return std.GetCritical(text, opts, fields...)
 }
 
 // GetDelete returns a formatted delete message as a string.
-func GetDelete(text string, opts *Options) (string, error) {
-	return Echo(MessageDelete, text, opts)
+func GetDelete(text string, opts *Options, fields ...Field) (string, error) {
+	return std.GetDelete(text, opts, fields...)
 }
 
 // GetUpdate returns a formatted update message as a string.
-func GetUpdate(text string, opts *Options) (string, error) {
-	return Echo(MessageUpdate, text, opts)
+func GetUpdate(text string, opts *Options, fields ...Field) (string, error) {
+	return std.GetUpdate(text, opts, fields...)
 }
 
 // GetInstall returns a formatted install message as a string.
-func GetInstall(text string, opts *Options) (string, error) {
-	return Echo(MessageInstall, text, opts)
+func GetInstall(text string, opts *Options, fields ...Field) (string, error) {
+	return std.GetInstall(text, opts, fields...)
 }
 
 // GetUpgrade returns a formatted upgrade message as a string.
-func GetUpgrade(text string, opts *Options) (string, error) {
-	return Echo(MessageUpgrade, text, opts)
+func GetUpgrade(text string, opts *Options, fields ...Field) (string, error) {
+	return std.GetUpgrade(text, opts, fields...)
 }
 
 // GetEdit returns a formatted edit message as a string.
-func GetEdit(text string, opts *Options) (string, error) {
-	return Echo(MessageEdit, text, opts)
+func GetEdit(text string, opts *Options, fields ...Field) (string, error) {
+	return std.GetEdit(text, opts, fields...)
 }
 
 // GetNew returns a formatted new message as a string.
-func GetNew(text string, opts *Options) (string, error) {
-	return Echo(MessageNew, text, opts)
+func GetNew(text string, opts *Options, fields ...Field) (string, error) {
+	return std.GetNew(text, opts, fields...)
 }
 
 // GetDownload returns a formatted download message as a string.
-func GetDownload(text string, opts *Options) (string, error) {
-	return Echo(MessageDownload, text, opts)
+func GetDownload(text string, opts *Options, fields ...Field) (string, error) {
+	return std.GetDownload(text, opts, fields...)
 }
 
 // GetUpload returns a formatted upload message as a string.
-func GetUpload(text string, opts *Options) (string, error) {
-	return Echo(MessageUpload, text, opts)
+func GetUpload(text string, opts *Options, fields ...Field) (string, error) {
+	return std.GetUpload(text, opts, fields...)
 }
 
 // GetSync returns a formatted sync message as a string.
-func GetSync(text string, opts *Options) (string, error) {
-	return Echo(MessageSync, text, opts)
+func GetSync(text string, opts *Options, fields ...Field) (string, error) {
+	return std.GetSync(text, opts, fields...)
 }
 
 // GetSearch returns a formatted search message as a string.
-func GetSearch(text string, opts *Options) (string, error) {
-	return Echo(MessageSearch, text, opts)
+func GetSearch(text string, opts *Options, fields ...Field) (string, error) {
+	return std.GetSearch(text, opts, fields...)
 }
 
 // --- Get formatted functions (printf style) ---
 
 // GetSuccessf returns a formatted success message with arguments.
 func GetSuccessf(text string, opts *Options, args ...any) (string, error) {
-	return GetSuccess(fmt.Sprintf(text, args...), opts)
+	msg, fields := sprintf(text, args)
+	return GetSuccess(msg, opts, fields...)
 }
 
 // GetErrorf returns a formatted error message with arguments.
 func GetErrorf(text string, opts *Options, args ...any) (string, error) {
-	return GetError(fmt.Sprintf(text, args...), opts)
+	msg, fields := sprintf(text, args)
+	return GetError(msg, opts, fields...)
 }
 
 // GetWarningf returns a formatted warning message with arguments.
 func GetWarningf(text string, opts *Options, args ...any) (string, error) {
-	return GetWarning(fmt.Sprintf(text, args...), opts)
+	msg, fields := sprintf(text, args)
+	return GetWarning(msg, opts, fields...)
 }
 
 // GetInfof returns a formatted info message with arguments.
 func GetInfof(text string, opts *Options, args ...any) (string, error) {
-	return GetInfo(fmt.Sprintf(text, args...), opts)
+	msg, fields := sprintf(text, args)
+	return GetInfo(msg, opts, fields...)
 }
 
 // GetDebugf returns a formatted debug message with arguments.
 func GetDebugf(text string, opts *Options, args ...any) (string, error) {
-	return GetDebug(fmt.Sprintf(text, args...), opts)
+	msg, fields := sprintf(text, args)
+	return GetDebug(msg, opts, fields...)
 }
 
 // GetCriticalf returns a formatted critical message with arguments.
 func GetCriticalf(text string, opts *Options, args ...any) (string, error) {
-	return GetCritical(fmt.Sprintf(text, args...), opts)
+	msg, fields := sprintf(text, args)
+	return GetCritical(msg, opts, fields...)
 }
 
 // GetDeletef returns a formatted delete message with arguments.
 func GetDeletef(text string, opts *Options, args ...any) (string, error) {
-	return GetDelete(fmt.Sprintf(text, args...), opts)
+	msg, fields := sprintf(text, args)
+	return GetDelete(msg, opts, fields...)
 }
 
 // GetUpdatef returns a formatted update message with arguments.
 func GetUpdatef(text string, opts *Options, args ...any) (string, error) {
-	return GetUpdate(fmt.Sprintf(text, args...), opts)
+	msg, fields := sprintf(text, args)
+	return GetUpdate(msg, opts, fields...)
 }
 
 // GetInstallf returns a formatted install message with arguments.
 func GetInstallf(text string, opts *Options, args ...any) (string, error) {
-	return GetInstall(fmt.Sprintf(text, args...), opts)
+	msg, fields := sprintf(text, args)
+	return GetInstall(msg, opts, fields...)
 }
 
 // GetUpgradef returns a formatted upgrade message with arguments.
 func GetUpgradef(text string, opts *Options, args ...any) (string, error) {
-	return GetUpgrade(fmt.Sprintf(text, args...), opts)
+	msg, fields := sprintf(text, args)
+	return GetUpgrade(msg, opts, fields...)
 }
 
 // GetEditf returns a formatted edit message with arguments.
 func GetEditf(text string, opts *Options, args ...any) (string, error) {
-	return GetEdit(fmt.Sprintf(text, args...), opts)
+	msg, fields := sprintf(text, args)
+	return GetEdit(msg, opts, fields...)
 }
 
 // GetNewf returns a formatted new message with arguments.
 func GetNewf(text string, opts *Options, args ...any) (string, error) {
-	return GetNew(fmt.Sprintf(text, args...), opts)
+	msg, fields := sprintf(text, args)
+	return GetNew(msg, opts, fields...)
 }
 
 // GetDownloadf returns a formatted download message with arguments.
 func GetDownloadf(text string, opts *Options, args ...any) (string, error) {
-	return GetDownload(fmt.Sprintf(text, args...), opts)
+	msg, fields := sprintf(text, args)
+	return GetDownload(msg, opts, fields...)
 }
 
 // GetUploadf returns a formatted upload message with arguments.
 func GetUploadf(text string, opts *Options, args ...any) (string, error) {
-	return GetUpload(fmt.Sprintf(text, args...), opts)
+	msg, fields := sprintf(text, args)
+	return GetUpload(msg, opts, fields...)
 }
 
 // GetSyncf returns a formatted sync message with arguments.
 func GetSyncf(text string, opts *Options, args ...any) (string, error) {
-	return GetSync(fmt.Sprintf(text, args...), opts)
+	msg, fields := sprintf(text, args)
+	return GetSync(msg, opts, fields...)
 }
 
 // GetSearchf returns a formatted search message with arguments.
 func GetSearchf(text string, opts *Options, args ...any) (string, error) {
-	return GetSearch(fmt.Sprintf(text, args...), opts)
+	msg, fields := sprintf(text, args)
+	return GetSearch(msg, opts, fields...)
 }
 
 // --- Log-only functions (structured logging only, no stdout) ---
 
 // LogSuccess logs a success message without printing to stdout.
-func LogSuccess(text string) {
-	logger.LogOnly(MessageSuccess, text)
+func LogSuccess(text string, fields ...Field) {
+	std.LogSuccess(text, fields...)
 }
 
 // LogError logs an error message without printing to stdout.
-func LogError(text string) {
-	logger.LogOnly(MessageError, text)
+func LogError(text string, fields ...Field) {
+	std.LogError(text, fields...)
 }
 
 // LogWarning logs a warning message without printing to stdout.
-func LogWarning(text string) {
-	logger.LogOnly(MessageWarning, text)
+func LogWarning(text string, fields ...Field) {
+	std.LogWarning(text, fields...)
 }
 
 // LogInfo logs an info message without printing to stdout.
-func LogInfo(text string) {
-	logger.LogOnly(MessageInfo, text)
+func LogInfo(text string, fields ...Field) {
+	std.LogInfo(text, fields...)
 }
 
 // LogDebug logs a debug message without printing to stdout.
-func LogDebug(text string) {
-	logger.LogOnly(MessageDebug, text)
+func LogDebug(text string, fields ...Field) {
+	std.LogDebug(text, fields...)
 }
 
 // LogCritical logs a critical message without printing to stdout.
-func LogCritical(text string) {
-	logger.LogOnly(MessageCritical, text)
+func LogCritical(text string, fields ...Field) {
+	std.LogCritical(text, fields...)
 }
 
 // LogDelete logs a delete message without printing to stdout.
-func LogDelete(text string) {
-	logger.LogOnly(MessageDelete, text)
+func LogDelete(text string, fields ...Field) {
+	std.LogDelete(text, fields...)
 }
 
 // LogUpdate logs an update message without printing to stdout.
-func LogUpdate(text string) {
-	logger.LogOnly(MessageUpdate, text)
+func LogUpdate(text string, fields ...Field) {
+	std.LogUpdate(text, fields...)
 }
 
 // LogInstall logs an install message without printing to stdout.
-func LogInstall(text string) {
-	logger.LogOnly(MessageInstall, text)
+func LogInstall(text string, fields ...Field) {
+	std.LogInstall(text, fields...)
 }
 
 // LogUpgrade logs an upgrade message without printing to stdout.
-func LogUpgrade(text string) {
-	logger.LogOnly(MessageUpgrade, text)
+func LogUpgrade(text string, fields ...Field) {
+	std.LogUpgrade(text, fields...)
 }
 
 // LogEdit logs an edit message without printing to stdout.
-func LogEdit(text string) {
-	logger.LogOnly(MessageEdit, text)
+func LogEdit(text string, fields ...Field) {
+	std.LogEdit(text, fields...)
 }
 
 // LogNew logs a new message without printing to stdout.
-func LogNew(text string) {
-	logger.LogOnly(MessageNew, text)
+func LogNew(text string, fields ...Field) {
+	std.LogNew(text, fields...)
 }
 
 // LogDownload logs a download message without printing to stdout.
-func LogDownload(text string) {
-	logger.LogOnly(MessageDownload, text)
+func LogDownload(text string, fields ...Field) {
+	std.LogDownload(text, fields...)
 }
 
 // LogUpload logs an upload message without printing to stdout.
-func LogUpload(text string) {
-	logger.LogOnly(MessageUpload, text)
+func LogUpload(text string, fields ...Field) {
+	std.LogUpload(text, fields...)
 }
 
 // LogSync logs a sync message without printing to stdout.
-func LogSync(text string) {
-	logger.LogOnly(MessageSync, text)
+func LogSync(text string, fields ...Field) {
+	std.LogSync(text, fields...)
 }
 
 // LogSearch logs a search message without printing to stdout.
-func LogSearch(text string) {
-	logger.LogOnly(MessageSearch, text)
+func LogSearch(text string, fields ...Field) {
+	std.LogSearch(text, fields...)
 }
 
 // --- Log-only formatted functions (printf style) ---
 
 // LogSuccessf logs a formatted success message without printing to stdout.
 func LogSuccessf(text string, args ...any) {
-	LogSuccess(fmt.Sprintf(text, args...))
+	msg, fields := sprintf(text, args)
+	LogSuccess(msg, fields...)
 }
 
 // LogErrorf logs a formatted error message without printing to stdout.
 func LogErrorf(text string, args ...any) {
-	LogError(fmt.Sprintf(text, args...))
+	msg, fields := sprintf(text, args)
+	LogError(msg, fields...)
 }
 
 // LogWarningf logs a formatted warning message without printing to stdout.
 func LogWarningf(text string, args ...any) {
-	LogWarning(fmt.Sprintf(text, args...))
+	msg, fields := sprintf(text, args)
+	LogWarning(msg, fields...)
 }
 
 // LogInfof logs a formatted info message without printing to stdout.
 func LogInfof(text string, args ...any) {
-	LogInfo(fmt.Sprintf(text, args...))
+	msg, fields := sprintf(text, args)
+	LogInfo(msg, fields...)
 }
 
 // LogDebugf logs a formatted debug message without printing to stdout.
 func LogDebugf(text string, args ...any) {
-	LogDebug(fmt.Sprintf(text, args...))
+	msg, fields := sprintf(text, args)
+	LogDebug(msg, fields...)
 }
 
 // LogCriticalf logs a formatted critical message without printing to stdout.
 func LogCriticalf(text string, args ...any) {
-	LogCritical(fmt.Sprintf(text, args...))
+	msg, fields := sprintf(text, args)
+	LogCritical(msg, fields...)
 }
 
 // LogDeletef logs a formatted delete message without printing to stdout.
 func LogDeletef(text string, args ...any) {
-	LogDelete(fmt.Sprintf(text, args...))
+	msg, fields := sprintf(text, args)
+	LogDelete(msg, fields...)
 }
 
 // LogUpdatef logs a formatted update message without printing to stdout.
 func LogUpdatef(text string, args ...any) {
-	LogUpdate(fmt.Sprintf(text, args...))
+	msg, fields := sprintf(text, args)
+	LogUpdate(msg, fields...)
 }
 
 // LogInstallf logs a formatted install message without printing to stdout.
 func LogInstallf(text string, args ...any) {
-	LogInstall(fmt.Sprintf(text, args...))
+	msg, fields := sprintf(text, args)
+	LogInstall(msg, fields...)
 }
 
 // LogUpgradef logs a formatted upgrade message without printing to stdout.
 func LogUpgradef(text string, args ...any) {
-	LogUpgrade(fmt.Sprintf(text, args...))
+	msg, fields := sprintf(text, args)
+	LogUpgrade(msg, fields...)
 }
 
 // LogEditf logs a formatted edit message without printing to stdout.
 func LogEditf(text string, args ...any) {
-	LogEdit(fmt.Sprintf(text, args...))
+	msg, fields := sprintf(text, args)
+	LogEdit(msg, fields...)
 }
 
 // LogNewf logs a formatted new message without printing to stdout.
 func LogNewf(text string, args ...any) {
-	LogNew(fmt.Sprintf(text, args...))
+	msg, fields := sprintf(text, args)
+	LogNew(msg, fields...)
 }
 
 // LogDownloadf logs a formatted download message without printing to stdout.
 func LogDownloadf(text string, args ...any) {
-	LogDownload(fmt.Sprintf(text, args...))
+	msg, fields := sprintf(text, args)
+	LogDownload(msg, fields...)
 }
 
 // LogUploadf logs a formatted upload message without printing to stdout.
 func LogUploadf(text string, args ...any) {
-	LogUpload(fmt.Sprintf(text, args...))
+	msg, fields := sprintf(text, args)
+	LogUpload(msg, fields...)
 }
 
 // LogSyncf logs a formatted sync message without printing to stdout.
 func LogSyncf(text string, args ...any) {
-	LogSync(fmt.Sprintf(text, args...))
+	msg, fields := sprintf(text, args)
+	LogSync(msg, fields...)
 }
 
 // LogSearchf logs a formatted search message without printing to stdout.
 func LogSearchf(text string, args ...any) {
-	LogSearch(fmt.Sprintf(text, args...))
+	msg, fields := sprintf(text, args)
+	LogSearch(msg, fields...)
 }
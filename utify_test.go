@@ -1,11 +1,33 @@
 package utify
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+
+	"github.com/jsas4coding/utify/pkg/colors"
+	"github.com/jsas4coding/utify/pkg/icons"
+	"github.com/jsas4coding/utify/pkg/logger"
+	"github.com/jsas4coding/utify/pkg/messages"
 )
 
+// TestMain keeps the default log file, and those of subprocesses, in a
+// temporary state directory instead of the user's.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "utify-state")
+	if err != nil {
+		panic(err)
+	}
+	_ = os.Setenv("XDG_STATE_HOME", dir)
+	_ = SetLogTarget(filepath.Join(dir, "test.log"))
+	code := m.Run()
+	_ = os.RemoveAll(dir)
+	os.Exit(code)
+}
+
 func defaultOpts() *Options {
 	return OptionsDefault()
 }
@@ -13,7 +35,7 @@ func defaultOpts() *Options {
 func TestBasicOutputFunctions(t *testing.T) {
 	tests := []struct {
 		name string
-		fn   func(string, *Options)
+		fn   func(string, *Options, ...Field)
 	}{
 		{"Success", Success},
 		{"Error", Error},
@@ -73,7 +95,7 @@ func TestFormattedOutputFunctions(t *testing.T) {
 func TestGetFunctions(t *testing.T) {
 	tests := []struct {
 		name string
-		fn   func(string, *Options) (string, error)
+		fn   func(string, *Options, ...Field) (string, error)
 	}{
 		{"GetSuccess", GetSuccess},
 		{"GetError", GetError},
@@ -105,7 +127,7 @@ func TestGetFunctions(t *testing.T) {
 func TestLogOnlyFunctions(t *testing.T) {
 	tests := []struct {
 		name string
-		fn   func(string)
+		fn   func(string, ...Field)
 	}{
 		{"LogSuccess", LogSuccess},
 		{"LogError", LogError},
@@ -134,10 +156,16 @@ func TestLogOnlyFunctions(t *testing.T) {
 
 func TestConfigFunctions(t *testing.T) {
 	t.Run("SetColorTable", func(t *testing.T) {
-		SetColorTable(map[string]string{
+		err := SetColorTable(map[string]string{
 			"Red":  "#FF0000",
 			"Blue": "#0000FF",
 		})
+		if err != nil {
+			t.Errorf("SetColorTable failed: %v", err)
+		}
+		if err := SetColorTable(map[string]string{"Red": "not-a-color"}); err == nil {
+			t.Error("expected SetColorTable to reject an invalid color")
+		}
 	})
 
 	t.Run("SetLogTargetAndGet", func(t *testing.T) {
@@ -233,3 +261,114 @@ func TestLogFormattedFunctions(t *testing.T) {
 		})
 	}
 }
+
+func TestRegisterType(t *testing.T) {
+	const canary MessageType = "canary"
+	defer messages.Unregister(canary)
+
+	if err := RegisterType(canary, Definition{Label: "CANARY", Severity: SeverityNotice}); err != nil {
+		t.Fatalf("RegisterType failed: %v", err)
+	}
+	if err := RegisterType(MessageSuccess, Definition{}); err == nil {
+		t.Error("Expected RegisterType to reject predefined types")
+	}
+
+	var buf bytes.Buffer
+	p := NewPrinter(WithOutput(&buf), WithColorProfile(colors.ProfileNone), WithLogger(logger.NewWriter(nil)))
+	text, err := p.Echo(canary, "5% of traffic", defaultOpts())
+	if text != "5% of traffic" || err != nil {
+		t.Errorf("Expected non-error result, got %q, %v", text, err)
+	}
+	if buf.String() != "CANARY 5% of traffic\n" {
+		t.Errorf("Unexpected output %q", buf.String())
+	}
+}
+
+func TestFields(t *testing.T) {
+	var buf, logBuf bytes.Buffer
+	p := NewPrinter(WithOutput(&buf), WithColorProfile(colors.ProfileNone), WithLogger(logger.NewWriter(&logBuf)))
+
+	p.Info("deployed", defaultOpts(), F("service", "api"), F("size", Bytes(1536)))
+	p.Successf("migrated %d tables", defaultOpts(), 3, F("db", "main"))
+	p.LogWarningf("slow %s", "query", F("ms", 250))
+
+	expected := "deployed service=api size=\"1.5 KiB\"\nmigrated 3 tables db=main\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+	for _, want := range []string{`"size":1536`, `"message":"migrated 3 tables","type":"success","binary":"`, `"db":"main"`,
+		`"message":"slow query"`, `"ms":250`} {
+		if !strings.Contains(logBuf.String(), want) {
+			t.Errorf("Expected log to contain %s, got %q", want, logBuf.String())
+		}
+	}
+
+	text, err := NopPrinter{}.GetInfof("n=%d", defaultOpts(), 1, F("k", "v"))
+	if text != "n=1" || err != nil {
+		t.Errorf("Expected NopPrinter to drop fields from the text, got %q, %v", text, err)
+	}
+}
+
+func TestConcurrentPrintAndReconfigure(t *testing.T) {
+	originalTarget := GetLogTarget()
+	defer func() {
+		_ = SetLogTarget(originalTarget)
+		SetLoggingEnabled(true)
+		colors.ClearUserColors()
+		ForceRegularIcons()
+		SetTheme(nil)
+		SetLayouts(nil)
+		SetLevels(DefaultLevels())
+		SetRouting(DefaultRouting())
+		SetColorProfile(colors.ProfileAuto)
+	}()
+
+	var out syncBuffer
+	p := NewPrinter(
+		WithOutput(&out),
+		WithErrorOutput(&out),
+		WithColorTable(colors.DefaultTable()),
+		WithIconSet(icons.DefaultSet()),
+		WithLogger(logger.Default()),
+	)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				p.Info("concurrent", defaultOpts().WithIcon(), F("i", i))
+				p.Warningf("concurrent %d", defaultOpts(), i)
+			}
+		}()
+	}
+
+	dir := t.TempDir()
+	for i := 0; i < 30; i++ {
+		_ = SetColorTable(map[string]string{"info": "#00aaff"})
+		colors.ClearUserColors()
+		ForceNerdFont()
+		ForceRegularIcons()
+		_ = SetLayout([]string{"default", "compact", "timestamped"}[i%3])
+		SetTheme(nil)
+		SetVerbosity(i%3 - 1)
+		SetColorProfile(colors.Profile(i % 5))
+		SetRouting(DefaultRouting())
+		_ = SetLogTarget(filepath.Join(dir, "concurrent.log"))
+		SetLoggingEnabled(i%2 == 0)
+	}
+	wg.Wait()
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}